@@ -0,0 +1,24 @@
+package common
+
+import (
+	"database/sql"
+
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// IsDecimalColumn reports whether a result column holds fixed precision
+// numbers: either its type is a decimal type, or it is a number type with a
+// scale (such as Oracle's NUMBER(10,2)). Decimal columns are scanned into
+// strings so that no precision is lost.
+func IsDecimalColumn(g *sg.SQLGenerator, ct *sql.ColumnType) bool {
+	typeName := ct.DatabaseTypeName()
+	if g.IsDecimalType(typeName) {
+		return true
+	}
+	if g.IsNumberType(typeName) {
+		if _, scale, ok := ct.DecimalSize(); ok && scale > 0 {
+			return true
+		}
+	}
+	return false
+}
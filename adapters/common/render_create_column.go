@@ -5,6 +5,7 @@ package common
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/rbastic/dyndao/schema"
@@ -15,13 +16,35 @@ import (
 // See http://www.sqlitetutorial.net/sqlite-autoincrement/
 
 func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column, identityStr string, mapTypeFn func(dbType string) string) string {
-	dataType := strings.ToUpper(f.DBType)
+	return RenderCreateColumnWithType(sg, f, identityStr, RenderColumnType(sg, f, mapTypeFn))
+}
 
+// RenderColumnType renders the type of a column, mapped by mapTypeFn if it
+// is given, with its precision and scale or its length.
+func RenderColumnType(sg *sg.SQLGenerator, f *schema.Column, mapTypeFn func(dbType string) string) string {
+	dataType := strings.ToUpper(sg.ColumnDBType(sg, f))
+	if mapTypeFn != nil {
+		dataType = mapTypeFn(dataType)
+	}
+	if f.Precision > 0 {
+		return fmt.Sprintf("%s(%d,%d)", dataType, f.Precision, f.Scale)
+	} else if f.Length > 0 {
+		return fmt.Sprintf("%s(%d)", dataType, f.Length)
+	}
+	return dataType
+}
+
+// RenderCreateColumnWithType is RenderCreateColumn for a column whose type
+// has already been rendered, such as a MySQL ENUM.
+func RenderCreateColumnWithType(sg *sg.SQLGenerator, f *schema.Column, identityStr string, dataType string) string {
 	notNull := ""
 	identity := ""
 	unique := ""
 
-	if f.IsIdentity {
+	if f.IsUUID {
+		// UUIDs are generated by the ORM, not the database
+		identity = "PRIMARY KEY"
+	} else if f.IsIdentity {
 		identity = identityStr
 	}
 	if f.AllowNull {
@@ -29,16 +52,174 @@ func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column, identityStr strin
 	} else {
 		notNull = "NOT NULL"
 	}
-	if mapTypeFn != nil {
-		dataType = mapTypeFn(dataType)
-	}
-	if f.Length > 0 {
-		dataType = fmt.Sprintf("%s(%d)", dataType, f.Length)
-	}
 
 	if f.IsUnique {
 		unique = "UNIQUE"
 	}
 
-	return strings.Join([]string{f.Name, dataType, identity, notNull, unique}, " ")
+	if f.Generated != "" {
+		return strings.Join([]string{f.Name, dataType, RenderGenerated(f.Generated), notNull, unique, RenderChecks(sg, f)}, " ")
+	}
+
+	defaultValue := ""
+	if !f.IsIdentity {
+		defaultValue = RenderDefault(sg, f)
+	}
+
+	return strings.Join([]string{f.Name, dataType, identity, defaultValue, notNull, unique, RenderChecks(sg, f)}, " ")
+}
+
+// defaultKeywords are DefaultValues which must be rendered unquoted.
+var defaultKeywords = map[string]bool{
+	"NULL":              true,
+	"CURRENT_TIMESTAMP": true,
+	"CURRENT_DATE":      true,
+	"CURRENT_TIME":      true,
+	"SYSDATE":           true,
+	"SYSTIMESTAMP":      true,
+}
+
+// defaultCall matches a function call, or a keyword with a precision such as
+// CURRENT_TIMESTAMP(6).
+var defaultCall = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*\s*\(.*\)$`)
+
+// RenderDefault renders a DEFAULT clause for the column's DefaultValue, or an
+// empty string if there is none. Numbers and SQL keywords such as
+// CURRENT_TIMESTAMP are rendered unquoted, as are function calls such as
+// NOW() for timestamp columns, true and false become 1 and 0 for non-string
+// columns, and anything else is rendered as a string literal unless it
+// already is one, as the defaults read back from a database are.
+func RenderDefault(g *sg.SQLGenerator, f *schema.Column) string {
+	v := f.DefaultValue
+	if v == "" {
+		return ""
+	}
+
+	upper := strings.ToUpper(v)
+	dbType := g.ColumnDBType(g, f)
+	isString := g.IsStringType(dbType) || g.IsLOBType(dbType)
+
+	switch {
+	case defaultKeywords[upper]:
+		return "DEFAULT " + upper
+	case len(v) >= 2 && strings.HasPrefix(v, "'") && strings.HasSuffix(v, "'"):
+		return "DEFAULT " + v
+	case !isString && g.IsTimestampType(dbType) && defaultCall.MatchString(v):
+		return "DEFAULT " + v
+	case !isString && upper == "TRUE":
+		return "DEFAULT 1"
+	case !isString && upper == "FALSE":
+		return "DEFAULT 0"
+	case !isString && (f.IsNumber || g.IsNumberType(dbType) || g.IsFloatingType(dbType)):
+		return "DEFAULT " + v
+	}
+	return "DEFAULT " + g.QuoteLiteral(v)
+}
+
+// QuoteString renders s as a single-quoted SQL string literal, doubling any
+// single quotes. It is the standard SQL QuoteLiteral.
+func QuoteString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// RenderGenerated renders the clause for a generated column computed from
+// expr, in the form shared by SQLite, MySQL and Oracle.
+func RenderGenerated(expr string) string {
+	return "GENERATED ALWAYS AS (" + expr + ")"
+}
+
+// RenderCheck renders a CHECK constraint for the given expression, or an empty
+// string if there is no expression. The given column names are quoted with
+// the dialect's QuoteIdentifier wherever they appear in the expression on
+// their own. String literals, quoted identifiers, function names and the
+// rest of the expression are left as they are, and must suit the dialect.
+func RenderCheck(g *sg.SQLGenerator, expr string, columnNames []string) string {
+	if expr == "" {
+		return ""
+	}
+	return "CHECK (" + quoteColumnNames(g, expr, columnNames) + ")"
+}
+
+// quoteColumnNames quotes the bare identifiers of expr which are amongst
+// columnNames.
+func quoteColumnNames(g *sg.SQLGenerator, expr string, columnNames []string) string {
+	known := make(map[string]bool, len(columnNames))
+	for _, c := range columnNames {
+		known[c] = true
+	}
+	var b strings.Builder
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			// A literal or quoted identifier, in which a doubled
+			// closing quote stands for itself
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			j := i + 1
+			for j < len(expr) {
+				if expr[j] == end {
+					if j+1 < len(expr) && expr[j+1] == end {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+			b.WriteString(expr[i:j])
+			i = j
+		case isIdentByte(c):
+			j := i
+			for j < len(expr) && isIdentByte(expr[j]) {
+				j++
+			}
+			word := expr[i:j]
+			k := j
+			for k < len(expr) && expr[k] == ' ' {
+				k++
+			}
+			isCall := k < len(expr) && expr[k] == '('
+			isQualified := i > 0 && expr[i-1] == '.'
+			if known[word] && !isCall && !isQualified && !(c >= '0' && c <= '9') {
+				b.WriteString(g.QuoteIdentifier(word))
+			} else {
+				b.WriteString(word)
+			}
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// RenderChecks renders the CHECK constraints of a column: its Check
+// expression, and an IN list of its AllowedValues. It returns an empty string
+// if the column has neither.
+func RenderChecks(g *sg.SQLGenerator, f *schema.Column) string {
+	names := []string{f.Name}
+	checks := []string{RenderCheck(g, f.Check, names)}
+	if len(f.AllowedValues) > 0 {
+		checks = append(checks, RenderCheck(g, f.Name+" IN ("+RenderValueList(g, f.AllowedValues)+")", names))
+	}
+	return strings.TrimSpace(strings.Join(checks, " "))
+}
+
+// RenderValueList renders values as a comma-separated list of string
+// literals.
+func RenderValueList(g *sg.SQLGenerator, values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = g.QuoteLiteral(v)
+	}
+	return strings.Join(quoted, ",")
 }
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/query"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingAggregate renders a SELECT of the given group columns and aggregate
+// expressions, grouped by the group columns and filtered by any HAVING
+// predicates. It returns the sqlStr, the result names (the group columns
+// followed by each aggregate's Name) and the binding arguments.
+func BindingAggregate(g *sg.SQLGenerator, sch *schema.Schema, table string, groupCols []string, aggs []query.Aggregate, having []query.Having) (string, []string, []interface{}, error) {
+	schTable := sch.GetTable(table)
+	if schTable == nil {
+		return "", nil, nil, errors.New("BindingAggregate: Table map unavailable for table " + table)
+	}
+	if len(aggs) == 0 {
+		return "", nil, nil, errors.New("BindingAggregate: no aggregates given for table " + table)
+	}
+
+	var names []string
+	var selects []string
+	var groups []string
+	for _, c := range groupCols {
+		f := schTable.GetColumn(c)
+		if f == nil {
+			return "", nil, nil, errors.New("BindingAggregate: unknown group column " + c + " in table " + table)
+		}
+		groups = append(groups, g.QuoteIdentifier(f.Name))
+		names = append(names, f.Name)
+	}
+	selects = append(selects, groups...)
+
+	for _, a := range aggs {
+		expr, err := renderAggregate(g, schTable, a)
+		if err != nil {
+			return "", nil, nil, errors.Wrap(err, "BindingAggregate")
+		}
+		selects = append(selects, fmt.Sprintf("%s AS %s", expr, g.QuoteIdentifier(a.Name())))
+		names = append(names, a.Name())
+	}
+
+	tableName := sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)
+	if sch.TableSchemaName(table) == "" {
+		tableName = g.QuoteIdentifier(tableName)
+	}
+	sqlStr := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ","), tableName)
+	if len(groups) > 0 {
+		sqlStr += " GROUP BY " + strings.Join(groups, ",")
+	}
+
+	var bindArgs []interface{}
+	if len(having) > 0 {
+		preds := make([]sg.Predicate, len(having))
+		for i, h := range having {
+			expr, err := renderAggregate(g, schTable, h.Aggregate)
+			if err != nil {
+				return "", nil, nil, errors.Wrap(err, "BindingAggregate")
+			}
+			op := strings.ToUpper(strings.TrimSpace(h.Operator))
+			if !query.Operators[op] {
+				return "", nil, nil, errors.New("BindingAggregate: unsupported operator " + h.Operator)
+			}
+			preds[i] = sg.Predicate{Column: expr, Name: "having", Operator: op, Value: h.Value}
+		}
+		where := sg.NewWhereBuilder(g)
+		where.NumberBinds = true
+		havingClause, havingArgs, err := where.Render(preds)
+		if err != nil {
+			return "", nil, nil, errors.Wrap(err, "BindingAggregate")
+		}
+		bindArgs = havingArgs
+		sqlStr += " HAVING " + havingClause
+	}
+
+	return sqlStr, names, bindArgs, nil
+}
+
+func renderAggregate(g *sg.SQLGenerator, schTable *schema.Table, a query.Aggregate) (string, error) {
+	if err := a.Validate(); err != nil {
+		return "", err
+	}
+	fn := strings.ToUpper(a.Func)
+	if a.Column == "*" {
+		return fn + "(*)", nil
+	}
+	f := schTable.GetColumn(a.Column)
+	if f == nil {
+		return "", errors.New("unknown aggregate column " + a.Column + " in table " + schTable.Name)
+	}
+	return fmt.Sprintf("%s(%s)", fn, g.QuoteIdentifier(f.Name)), nil
+}
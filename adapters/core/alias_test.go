@@ -0,0 +1,54 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func aliasedWidgetSchema() *schema.Schema {
+	sch := mock.WidgetSchema()
+	sch.TableAliases = map[string]string{"gizmos": mock.GadgetsObjectType}
+	sch.Tables[mock.GadgetsObjectType].ColumnAliases = map[string]string{"Title": "Name"}
+	return sch
+}
+
+func TestBindingInsertResolvesAliases(t *testing.T) {
+	g := New()
+	sch := aliasedWidgetSchema()
+
+	sqlStr, bindArgs, err := BindingInsert(g, sch, "gizmos", map[string]interface{}{"Title": "sprocket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != "INSERT INTO gadgets (Name) VALUES (?)" {
+		t.Fatal("unexpected sql", sqlStr)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{"sprocket"}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+}
+
+func TestBindingRetrieveResolvesAliases(t *testing.T) {
+	g := New()
+	sch := aliasedWidgetSchema()
+
+	obj := object.New("gizmos")
+	obj.Set("Title", "sprocket")
+	sqlStr, columns, bindArgs, err := BindingRetrieveColumns(g, sch, obj, []string{"GadgetID", "Title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != "SELECT GadgetID,Name FROM gadgets WHERE Name = ?" {
+		t.Fatal("unexpected sql", sqlStr)
+	}
+	if !reflect.DeepEqual(columns, []string{"GadgetID", "Name"}) {
+		t.Fatal("unexpected columns", columns)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{"sprocket"}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// AlterTable renders an ALTER TABLE statement applying action, such as
+// DROP COLUMN Age, to a table within a schema.
+func AlterTable(g *sg.SQLGenerator, s *schema.Schema, table string, action string) (string, error) {
+	tbl := s.GetTable(table)
+	if tbl == nil {
+		return "", errors.New("dyndao: unknown schema for table with name " + table)
+	}
+	return "ALTER TABLE " + s.QualifiedTableName(tbl.Name, table, g.QuoteIdentifier) + " " + action, nil
+}
+
+// AddColumn renders the ALTER TABLE statement adding the column f, defined
+// as CreateTable would, to a table within a schema.
+func AddColumn(g *sg.SQLGenerator, s *schema.Schema, table string, f *schema.Column) (string, error) {
+	return AlterTable(g, s, table, "ADD COLUMN "+g.RenderCreateColumn(g, f))
+}
+
+// DropColumn renders the ALTER TABLE statement dropping a column, which may
+// be an alias, from a table within a schema.
+func DropColumn(g *sg.SQLGenerator, s *schema.Schema, table string, column string) (string, error) {
+	tbl := s.GetTable(table)
+	if tbl == nil {
+		return "", errors.New("dyndao: unknown schema for table with name " + table)
+	}
+	return AlterTable(g, s, table, "DROP COLUMN "+tbl.GetColumnName(column))
+}
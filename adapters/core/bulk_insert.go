@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+	"github.com/rbastic/nils"
+)
+
+// BindingBulkInsert generates a single INSERT statement for several rows,
+// which must all hold values for the same columns. It returns an empty
+// sqlStr if the dialect's BindingBulkInsertSQL cannot render one.
+func BindingBulkInsert(g *sg.SQLGenerator, sch *schema.Schema, table string, rows []map[string]interface{}) (string, []interface{}, error) {
+	if table == "" {
+		return "", nil, errors.New("BindingBulkInsert: Empty table name")
+	}
+	if len(rows) == 0 {
+		return "", nil, errors.New("BindingBulkInsert: No rows passed")
+	}
+
+	schTable := sch.GetTable(table)
+	if schTable == nil {
+		return "", nil, errors.New("BindingBulkInsert: Table map unavailable for table " + table)
+	}
+	fieldsMap := schTable.Columns
+	if fieldsMap == nil {
+		return "", nil, errors.New("BindingBulkInsert: Column map unavailable for table " + table)
+	}
+	tableName := sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)
+
+	// Read-only columns are populated by the database
+	first := writableData(schTable, rows[0])
+	keys := make([]string, 0, len(first))
+	for k := range first {
+		keys = append(keys, k)
+	}
+	schTable.SortColumns(keys)
+	if len(keys) == 0 {
+		// There is no multi-row form of an INSERT of defaults only
+		return "", nil, nil
+	}
+
+	var colNames []string
+	var bindArgs []interface{}
+	rowBindNames := make([][]string, len(rows))
+	bound := 0
+	for i, row := range rows {
+		row = writableData(schTable, row)
+		if len(row) != len(keys) {
+			return "", nil, fmt.Errorf("BindingBulkInsert: row %d has different columns than the first row", i)
+		}
+		for _, k := range keys {
+			if _, ok := row[k]; !ok {
+				return "", nil, fmt.Errorf("BindingBulkInsert: row %d has no value for %s", i, k)
+			}
+		}
+		var args []interface{}
+		var err error
+		rowBindNames[i], colNames, args, bound, err = bindInsertRow(g, schTable, keys, row, fieldsMap, bound)
+		if err != nil {
+			return "", nil, fmt.Errorf("BindingBulkInsert: row %d: %s", i, err)
+		}
+		bindArgs = append(bindArgs, args...)
+	}
+
+	sqlStr := g.BindingBulkInsertSQL(schTable, tableName, colNames, rowBindNames, schTable.Primary)
+	if sqlStr == "" {
+		return "", nil, nil
+	}
+	return sqlStr, nils.RemoveNilsIfNeeded(bindArgs), nil
+}
+
+// BindingBulkInsertSQL renders a multi-row INSERT, with a RETURNING clause
+// for the generated keys of the tables which have them.
+func BindingBulkInsertSQL(schTable *schema.Table, tableName string, colNames []string, rowBindNames [][]string, identityCol string) string {
+	sqlStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		tableName,
+		strings.Join(colNames, ","),
+		RenderValueRows(rowBindNames))
+	if schTable.UsesLastInsertID() {
+		sqlStr += " RETURNING " + identityCol
+	}
+	return sqlStr
+}
+
+// RenderValueRows renders the parenthesised rows of a multi-row VALUES list.
+func RenderValueRows(rowBindNames [][]string) string {
+	rows := make([]string, len(rowBindNames))
+	for i, bindNames := range rowBindNames {
+		rows[i] = "(" + strings.Join(bindNames, ",") + ")"
+	}
+	return strings.Join(rows, ",")
+}
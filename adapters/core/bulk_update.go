@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+	"github.com/rbastic/nils"
+)
+
+// BindingBulkUpdate renders an update of the setCols of several rows of a
+// table, located by their keyCols, through a temporary table. Every row
+// must hold a value for each of the columns, which are real column names.
+// It returns nil if the dialect's BulkUpdateSQL cannot render one.
+func BindingBulkUpdate(g *sg.SQLGenerator, sch *schema.Schema, table string, keyCols []string, setCols []string, rows []map[string]interface{}) (*sg.BulkUpdate, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("BindingBulkUpdate: No rows passed")
+	}
+	if len(keyCols) == 0 || len(setCols) == 0 {
+		return nil, errors.New("BindingBulkUpdate: key and value columns are required for table " + table)
+	}
+	schTable := sch.GetTable(table)
+	if schTable == nil {
+		return nil, errors.New("BindingBulkUpdate: Table map unavailable for table " + table)
+	}
+	tableName := sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)
+	tempName := "dyndao_bulk_" + sch.RealTableName(schTable.Name, table)
+
+	bu := g.BulkUpdateSQL(g, schTable, tableName, tempName, keyCols, setCols)
+	if bu == nil {
+		return nil, nil
+	}
+
+	cols := append(append([]string{}, keyCols...), setCols...)
+	var colNames []string
+	var bindArgs []interface{}
+	rowBindNames := make([][]string, len(rows))
+	bound := 0
+	for i, row := range rows {
+		data := make(map[string]interface{}, len(cols))
+		for _, c := range cols {
+			v, ok := row[c]
+			if !ok {
+				return nil, fmt.Errorf("BindingBulkUpdate: row %d has no value for %s", i, c)
+			}
+			if v == nil {
+				// Rendered inline, so that it isn't lost with the unbound values
+				v = object.NewNULLValue()
+			}
+			data[c] = v
+		}
+		var args []interface{}
+		var err error
+		rowBindNames[i], colNames, args, bound, err = bindInsertRow(g, schTable, cols, data, schTable.Columns, bound)
+		if err != nil {
+			return nil, fmt.Errorf("BindingBulkUpdate: row %d: %s", i, err)
+		}
+		bindArgs = append(bindArgs, args...)
+	}
+
+	bu.Load = fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", bu.TempTable, strings.Join(colNames, ","), RenderValueRows(rowBindNames))
+	bu.LoadArgs = nils.RemoveNilsIfNeeded(bindArgs)
+	return bu, nil
+}
+
+// BulkUpdateSQL renders a bulk update for dialects with UPDATE ... FROM,
+// such as SQLite (from 3.33) and PostgreSQL. The temporary table is created
+// from the table's own columns, so that their types match.
+func BulkUpdateSQL(g *sg.SQLGenerator, schTable *schema.Table, tableName string, tempName string, keyCols []string, setCols []string) *sg.BulkUpdate {
+	cols := append(append([]string{}, keyCols...), setCols...)
+
+	sets := make([]string, len(setCols))
+	for i, c := range setCols {
+		sets[i] = fmt.Sprintf("%s = %s.%s", c, tempName, c)
+	}
+	return &sg.BulkUpdate{
+		TempTable: tempName,
+		Create:    fmt.Sprintf("CREATE TEMPORARY TABLE %s AS SELECT %s FROM %s WHERE 1=0", tempName, strings.Join(cols, ","), tableName),
+		Update: fmt.Sprintf("UPDATE %s SET %s FROM %s WHERE %s",
+			tableName, strings.Join(sets, ","), tempName, RenderKeyJoin(tableName, tempName, keyCols)),
+		Drop: "DROP TABLE " + tempName,
+	}
+}
+
+// RenderKeyJoin renders the condition joining the key columns of two
+// tables.
+func RenderKeyJoin(tableName string, tempName string, keyCols []string) string {
+	conds := make([]string, len(keyCols))
+	for i, c := range keyCols {
+		conds[i] = fmt.Sprintf("%s.%s = %s.%s", tableName, c, tempName, c)
+	}
+	return strings.Join(conds, " AND ")
+}
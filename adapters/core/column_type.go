@@ -0,0 +1,28 @@
+package core
+
+import (
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// LogicalTypes is the default mapping from schema logical types to column
+// types. Adapters replace it with their own in New.
+var LogicalTypes = map[string]string{
+	schema.LogicalString:    "VARCHAR",
+	schema.LogicalText:      "TEXT",
+	schema.LogicalInt:       "INTEGER",
+	schema.LogicalFloat:     "DOUBLE PRECISION",
+	schema.LogicalBlob:      "BLOB",
+	schema.LogicalTimestamp: "TIMESTAMP",
+	schema.LogicalBool:      "BOOLEAN",
+	schema.LogicalDecimal:   "DECIMAL",
+}
+
+// ColumnDBType returns the column's DBType, or the generator's mapping of its
+// LogicalType if DBType is empty.
+func ColumnDBType(g *sg.SQLGenerator, f *schema.Column) string {
+	if f.DBType != "" || f.LogicalType == "" {
+		return f.DBType
+	}
+	return g.LogicalTypes[f.LogicalType]
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rbastic/dyndao/adapters/common"
 	"github.com/rbastic/dyndao/schema"
 	sg "github.com/rbastic/dyndao/sqlgen"
 )
@@ -17,14 +18,30 @@ func CreateTable(g *sg.SQLGenerator, s *schema.Schema, table string) (string, er
 	if !ok {
 		return "", errors.New("dyndao: unknown schema for table with name " + table)
 	}
-	tableName := schema.GetTableName(tbl.Name, table)
-	fieldsMap := tbl.Columns
+	tableName := s.QualifiedTableName(tbl.Name, table, g.QuoteIdentifier)
 
-	sqlColumns := make([]string, len(fieldsMap))
-	i := 0
-	for _, v := range fieldsMap {
-		sqlColumns[i] = g.RenderCreateColumn(g, v)
-		i++
+	names := tbl.ColumnNames()
+	sqlColumns := make([]string, len(names))
+	for i, name := range names {
+		sqlColumns[i] = g.RenderCreateColumn(g, tbl.Columns[name])
+	}
+
+	if pk := RenderCompositePrimaryKey(tbl); pk != "" {
+		sqlColumns = append(sqlColumns, pk)
+	}
+
+	foreignKeys, err := RenderForeignKeys(g, s, table)
+	if err != nil {
+		return "", err
+	}
+	sqlColumns = append(sqlColumns, foreignKeys...)
+
+	columnNames := make([]string, 0, len(tbl.Columns))
+	for _, f := range tbl.Columns {
+		columnNames = append(columnNames, f.Name)
+	}
+	for _, check := range tbl.Checks {
+		sqlColumns = append(sqlColumns, common.RenderCheck(g, check, columnNames))
 	}
 
 	sql := fmt.Sprintf(`CREATE TABLE %s (
@@ -38,3 +55,109 @@ func CreateTable(g *sg.SQLGenerator, s *schema.Schema, table string) (string, er
 
 	return sql, nil
 }
+
+// RenderCompositePrimaryKey renders a table-level PRIMARY KEY constraint for
+// MultiKey tables where none of the key columns are generated by the database
+// (since a generated column is rendered as the primary key on its own).
+func RenderCompositePrimaryKey(tbl *schema.Table) string {
+	if !tbl.MultiKey {
+		return ""
+	}
+	keys := tbl.PrimaryKeyColumns()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		f := tbl.GetColumn(k)
+		if f == nil || f.IsIdentity || f.IsUUID {
+			return ""
+		}
+		names[i] = f.Name
+	}
+	return "PRIMARY KEY (" + strings.Join(names, ",") + ")"
+}
+
+// RenderForeignKeys determines the FOREIGN KEY constraints for a table by
+// looking for it amongst the Children of the other tables in the schema.
+// Relationships that don't specify any key columns are skipped.
+func RenderForeignKeys(g *sg.SQLGenerator, s *schema.Schema, table string) ([]string, error) {
+	tbl := s.GetTable(table)
+	if tbl == nil {
+		return nil, errors.New("dyndao: unknown schema for table with name " + table)
+	}
+
+	var constraints []string
+	for _, parent := range s.ParentTableNames(table) {
+		parentTbl := s.GetTable(parent)
+		if parentTbl == nil {
+			continue
+		}
+		for childName, child := range parentTbl.Children {
+			if s.GetTableName(childName) != s.GetTableName(table) {
+				continue
+			}
+
+			localCols, foreignCols, err := child.KeyColumns()
+			if err != nil {
+				return nil, fmt.Errorf("%s (child %s of %s)", err.Error(), table, parent)
+			}
+			if len(localCols) == 0 {
+				continue
+			}
+
+			localNames := make([]string, len(localCols))
+			for i, c := range localCols {
+				localNames[i] = tbl.GetColumnName(c)
+			}
+			foreignNames := make([]string, len(foreignCols))
+			for i, c := range foreignCols {
+				foreignNames[i] = parentTbl.GetColumnName(c)
+			}
+
+			constraint := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+				strings.Join(localNames, ","),
+				s.QualifiedTableName(parentTbl.Name, parent, g.QuoteIdentifier),
+				strings.Join(foreignNames, ","))
+			if child.OnDeleteCascade {
+				constraint += " ON DELETE CASCADE"
+			}
+			constraints = append(constraints, constraint)
+		}
+	}
+	return constraints, nil
+}
+
+// CreateIndex determines the SQL to create a single index for a given table
+// within a schema. Indexes without a Name are named after the table and their
+// columns.
+func CreateIndex(g *sg.SQLGenerator, s *schema.Schema, table string, idx schema.Index) (string, error) {
+	tbl := s.GetTable(table)
+	if tbl == nil {
+		return "", errors.New("dyndao: unknown schema for table with name " + table)
+	}
+	if len(idx.Columns) == 0 {
+		return "", errors.New("dyndao: index " + idx.Name + " on table " + table + " has no columns")
+	}
+	tableName := s.QualifiedTableName(tbl.Name, table, g.QuoteIdentifier)
+
+	colNames := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		colNames[i] = tbl.GetColumnName(c)
+	}
+
+	indexName := idx.Name
+	if indexName == "" {
+		indexName = fmt.Sprintf("%s_%s_idx", s.RealTableName(tbl.Name, table), strings.Join(colNames, "_"))
+	}
+
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+
+	sql := fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, indexName, tableName, strings.Join(colNames, ","))
+
+	if g.Tracing {
+		fmt.Printf("dyndao: CreateIndex SQL:[%s]\n", sql)
+	}
+
+	return sql, nil
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/adapters/common"
+	"github.com/rbastic/dyndao/schema"
+	"github.com/rbastic/dyndao/schema/test/mock"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+func TestCreateIndex(t *testing.T) {
+	g := New()
+	sch := mock.NestedSchema()
+
+	idx := schema.Index{Name: "addr_city_state", Columns: []string{"City", "State"}}
+	sqlStr, err := CreateIndex(g, sch, "addresses", idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "CREATE INDEX addr_city_state ON addresses (City,State)"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+
+	idx.Unique = true
+	idx.Name = ""
+	sqlStr, err = CreateIndex(g, sch, "addresses", idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "CREATE UNIQUE INDEX addresses_City_State_idx ON addresses (City,State)"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+}
+
+func TestColumnDBType(t *testing.T) {
+	g := New()
+
+	f := schema.DefaultColumn()
+	f.Name = "Flag"
+	f.LogicalType = schema.LogicalBool
+	if dbType := ColumnDBType(g, f); dbType != "BOOLEAN" {
+		t.Fatal("expected the logical type to be mapped, got", dbType)
+	}
+
+	f.DBType = "tinyint"
+	if dbType := ColumnDBType(g, f); dbType != "tinyint" {
+		t.Fatal("expected DBType to take precedence, got", dbType)
+	}
+
+	for _, lt := range schema.LogicalTypes {
+		if LogicalTypes[lt] == "" {
+			t.Error("no default column type for logical type", lt)
+		}
+	}
+}
+
+func TestRenderCreateDecimalColumn(t *testing.T) {
+	g := New()
+	price := mock.WidgetSchema().Tables["widgets"].Columns["Price"]
+	col := common.RenderCreateColumn(g, price, "", nil)
+	if !strings.HasPrefix(col, "Price DECIMAL(10,2) ") {
+		t.Fatal("expected the precision and scale to be rendered, got", col)
+	}
+}
+
+func TestCreateTableColumnOrder(t *testing.T) {
+	g := New()
+	g.RenderCreateColumn = func(g *sg.SQLGenerator, f *schema.Column) string {
+		return f.Name + " " + ColumnDBType(g, f)
+	}
+	sch := mock.WidgetSchema()
+	widgets := sch.GetTable("widgets")
+	widgets.ColumnOrder = []string{"WidgetID", "Price", "Color", "Age", "Created", "Active", "NextAge"}
+
+	sqlStr, err := CreateTable(g, sch, "widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, line := range strings.Split(sqlStr, "\n")[1:] {
+		if fields := strings.Fields(line); len(fields) > 1 {
+			names = append(names, fields[0])
+		}
+	}
+	if strings.Join(names, ",") != strings.Join(widgets.ColumnOrder, ",") {
+		t.Fatal("expected the columns in ColumnOrder, got", sqlStr)
+	}
+
+	again, err := CreateTable(g, sch, "widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != sqlStr {
+		t.Fatal("expected identical DDL on every call")
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	for in, expected := range map[string]string{
+		"blue":       "'blue'",
+		"it's":       "'it''s'",
+		"''":         "''''''",
+		`back\slash`: `'back\slash'`,
+	} {
+		if quoted := QuoteLiteral(in); quoted != expected {
+			t.Errorf("expected %s for %q, got %s", expected, in, quoted)
+		}
+	}
+
+	g := New()
+	g.IsStringType = func(string) bool { return true }
+	color := mock.WidgetSchema().Tables["widgets"].Columns["Color"]
+	color.DefaultValue = "robin's egg"
+	col := common.RenderCreateColumn(g, color, "", nil)
+	if !strings.Contains(col, "DEFAULT 'robin''s egg'") {
+		t.Fatal("expected the default to be quoted with QuoteLiteral, got", col)
+	}
+}
+
+func TestRenderAllowedValues(t *testing.T) {
+	g := New()
+	g.IsStringType = func(string) bool { return true }
+	color := mock.WidgetSchema().Tables["widgets"].Columns["Color"]
+	color.AllowedValues = []string{"red", "blue", "robin's egg"}
+	col := common.RenderCreateColumn(g, color, "", nil)
+	if !strings.HasSuffix(col, `CHECK ("Color" IN ('red','blue','robin''s egg'))`) {
+		t.Fatal("expected a CHECK constraint for the allowed values, got", col)
+	}
+
+	color.Check = "Color <> ''"
+	col = common.RenderCreateColumn(g, color, "", nil)
+	if !strings.HasSuffix(col, `CHECK ("Color" <> '') CHECK ("Color" IN ('red','blue','robin''s egg'))`) {
+		t.Fatal("expected both CHECK constraints, got", col)
+	}
+}
+
+func TestRenderChecks(t *testing.T) {
+	g := New()
+	g.RenderCreateColumn = func(g *sg.SQLGenerator, f *schema.Column) string {
+		return f.Name + " " + ColumnDBType(g, f)
+	}
+	sch := mock.WidgetSchema()
+	widgets := sch.GetTable("widgets")
+	widgets.Checks = []string{`NextAge > Age OR "Age" = 0`, `Color <> 'Age' AND LENGTH(Color) < 20`}
+	sqlStr, err := CreateTable(g, sch, "widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, check := range []string{
+		`CHECK ("NextAge" > "Age" OR "Age" = 0)`,
+		`CHECK ("Color" <> 'Age' AND LENGTH("Color") < 20)`,
+	} {
+		if !strings.Contains(sqlStr, check) {
+			t.Errorf("expected %s, got %s", check, sqlStr)
+		}
+	}
+
+	// Column names are only quoted as identifiers of their own
+	if check := common.RenderCheck(g, "Age2 >= 0 AND w.Age >= 1e5", []string{"Age", "e5"}); check != "CHECK (Age2 >= 0 AND w.Age >= 1e5)" {
+		t.Fatal("expected nothing to be quoted, got", check)
+	}
+}
+
+func TestAlterTableColumns(t *testing.T) {
+	g := New()
+	g.RenderCreateColumn = func(g *sg.SQLGenerator, f *schema.Column) string {
+		return f.Name + " " + ColumnDBType(g, f)
+	}
+	sch := mock.WidgetSchema()
+	sch.SchemaName = "shop"
+
+	f := schema.DefaultColumn()
+	f.Name = "Weight"
+	f.LogicalType = schema.LogicalInt
+	sqlStr, err := AddColumn(g, sch, "widgets", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != `ALTER TABLE "shop"."widgets" ADD COLUMN Weight INTEGER` {
+		t.Fatal("unexpected ADD COLUMN statement", sqlStr)
+	}
+
+	sch.GetTable("widgets").ColumnAliases = map[string]string{"colour": "Color"}
+	sqlStr, err = DropColumn(g, sch, "widgets", "colour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != `ALTER TABLE "shop"."widgets" DROP COLUMN Color` {
+		t.Fatal("unexpected DROP COLUMN statement", sqlStr)
+	}
+
+	if _, err := DropColumn(g, sch, "sprockets", "Color"); err == nil {
+		t.Fatal("expected an error for a table that isn't in the schema")
+	}
+}
+
+func TestRenderSchemaDDL(t *testing.T) {
+	g := New()
+	g.RenderCreateColumn = func(g *sg.SQLGenerator, f *schema.Column) string {
+		return f.Name + " " + ColumnDBType(g, f)
+	}
+	stmts, err := g.RenderSchemaDDL(mock.NestedSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"CREATE TABLE people (\n\tName text,\nNullBlob blob,\nNullInt integer,\nNullText text,\nNullVarchar varchar,\nPersonID integer\n)",
+		"CREATE TABLE addresses (\n\tAddress1 text,\nAddress2 text,\nAddressID integer,\nCity text,\nPersonID integer,\nState text,\nZip text\n)",
+		"CREATE INDEX addresses_PersonID_idx ON addresses (PersonID)",
+	}
+	if len(stmts) != len(expected) {
+		t.Fatalf("expected %d statements, got %q", len(expected), stmts)
+	}
+	for i := range expected {
+		if stmts[i] != expected[i] {
+			t.Errorf("statement %d: expected [%s], got [%s]", i, expected[i], stmts[i])
+		}
+	}
+}
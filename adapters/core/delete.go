@@ -3,6 +3,7 @@ package core
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/rbastic/dyndao/object"
 	"github.com/rbastic/dyndao/schema"
@@ -11,21 +12,31 @@ import (
 
 // BindingDelete generates the appropriate SQL, binding args, and binding where clause parameters
 // to execute the requested delete operation. 'obj' is not required to be a
+// complete object, any values it holds are used for the where clause. For
+// MultiKey tables, an object holding the entire composite key is deleted by
+// that key alone.
 func BindingDelete(g *sg.SQLGenerator, sch *schema.Schema, queryVals *object.Object) (string, []interface{}, error) {
 	table := queryVals.Type
 	schTable := sch.GetTable(table)
 	if schTable == nil {
 		return "", nil, errors.New("BindingDelete: Table map unavailable for table " + table)
 	}
-	tableName := schema.GetTableName(schTable.Name, table)
+	tableName := sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)
 
-	whereClause, bindWhere, err := g.RenderWhereClause(g, schTable, queryVals)
+	whereObj := queryVals
+	if schTable.MultiKey {
+		if keyObj := keyQueryObject(schTable, queryVals); keyObj != nil {
+			whereObj = keyObj
+		}
+	}
+
+	whereClause, bindWhere, err := g.RenderWhereClause(g, schTable, whereObj)
 	if err != nil {
 		return "", nil, err
 	}
 
 	whereString := "WHERE"
-	if len(bindWhere) == 0 {
+	if whereClause == "" {
 		whereString = ""
 	}
 	sqlStr := fmt.Sprintf("DELETE FROM %s %s %s", tableName, whereString, whereClause)
@@ -34,3 +45,73 @@ func BindingDelete(g *sg.SQLGenerator, sch *schema.Schema, queryVals *object.Obj
 	}
 	return sqlStr, bindWhere, nil
 }
+
+// BindingDeleteReturning is BindingDelete for a DELETE which also returns the
+// given columns, which may be given by their aliases, of the rows it deletes.
+// The returned column names are the real column names, in the order
+// requested. sqlStr is "" if the dialect's DeleteReturningSQL cannot render
+// such a statement.
+func BindingDeleteReturning(g *sg.SQLGenerator, sch *schema.Schema, queryVals *object.Object, columns []string) (string, []string, []interface{}, error) {
+	table := queryVals.Type
+	schTable := sch.GetTable(table)
+	if schTable == nil {
+		return "", nil, nil, errors.New("BindingDeleteReturning: Table map unavailable for table " + table)
+	}
+	if len(columns) == 0 {
+		return "", nil, nil, errors.New("BindingDeleteReturning: no columns given for table " + table)
+	}
+	columnNames := make([]string, len(columns))
+	for i, c := range columns {
+		f := schTable.GetColumn(c)
+		if f == nil {
+			return "", nil, nil, errors.New("BindingDeleteReturning: unknown column " + c + " in table " + table)
+		}
+		columnNames[i] = f.Name
+	}
+	tableName := sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)
+
+	whereObj := queryVals
+	if schTable.MultiKey {
+		if keyObj := keyQueryObject(schTable, queryVals); keyObj != nil {
+			whereObj = keyObj
+		}
+	}
+
+	whereClause, bindWhere, err := g.RenderWhereClause(g, schTable, whereObj)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	sqlStr := g.DeleteReturningSQL(tableName, whereClause, columnNames)
+	if sqlStr == "" {
+		return "", nil, nil, nil
+	}
+	if g.Tracing {
+		fmt.Println(sqlStr)
+	}
+	return sqlStr, columnNames, bindWhere, nil
+}
+
+// DeleteReturningSQL renders a DELETE with a RETURNING clause, as SQLite and
+// MariaDB have.
+func DeleteReturningSQL(tableName string, whereClause string, columnNames []string) string {
+	whereString := "WHERE"
+	if whereClause == "" {
+		whereString = ""
+	}
+	return fmt.Sprintf("DELETE FROM %s %s %s RETURNING %s", tableName, whereString, whereClause, strings.Join(columnNames, ","))
+}
+
+// keyQueryObject returns a copy of obj holding only the table's primary key
+// columns, or nil if obj doesn't hold a value for every one of them.
+func keyQueryObject(schTable *schema.Table, obj *object.Object) *object.Object {
+	keyObj := object.New(obj.Type)
+	for _, k := range schTable.PrimaryKeyColumns() {
+		v, ok := obj.KV[k]
+		if !ok || v == nil {
+			return nil
+		}
+		keyObj.KV[k] = v
+	}
+	return keyObj
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func TestBindingDeleteReturning(t *testing.T) {
+	g := New()
+	sch := mock.WidgetSchema()
+	obj := object.New("widgets")
+	obj.Set("Color", "red")
+
+	sqlStr, columnNames, bindWhere, err := BindingDeleteReturning(g, sch, obj, []string{"WidgetID", "Color"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sqlStr, "DELETE FROM widgets WHERE") || !strings.HasSuffix(sqlStr, "RETURNING WidgetID,Color") {
+		t.Fatal("expected a DELETE ... RETURNING, got", sqlStr)
+	}
+	if len(columnNames) != 2 || len(bindWhere) != 1 {
+		t.Fatal("unexpected columns or bind arguments", columnNames, bindWhere)
+	}
+
+	// Dialects without DELETE ... RETURNING render nothing
+	g.DeleteReturningSQL = func(tableName string, whereClause string, columnNames []string) string {
+		return ""
+	}
+	sqlStr, _, _, err = BindingDeleteReturning(g, sch, obj, []string{"WidgetID"})
+	if err != nil || sqlStr != "" {
+		t.Fatal("expected no statement, got", sqlStr, err)
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingExists renders a 'SELECT 1' statement that returns at most a single
+// row if any row matches the values in obj.
+func BindingExists(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object) (string, []interface{}, error) {
+	table := obj.Type
+	schTable := sch.GetTable(table)
+	if schTable == nil {
+		return "", nil, errors.New("BindingExists: Table map unavailable for table " + table)
+	}
+
+	whereClause, bindWhere, err := g.RenderWhereClause(g, schTable, obj)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "BindingExists")
+	}
+
+	sqlStr := fmt.Sprintf("SELECT 1 FROM %s", sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier))
+	if whereClause != "" {
+		sqlStr += " WHERE " + whereClause
+	}
+	sqlStr += " " + g.RenderLimit(false, 1, 0)
+	return sqlStr, bindWhere, nil
+}
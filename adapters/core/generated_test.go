@@ -0,0 +1,57 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/adapters/common"
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func TestGeneratedColumnsAreNotBound(t *testing.T) {
+	g := New()
+	g.IsTimestampType = func(string) bool { return false }
+	sch := mock.WidgetSchema()
+
+	sqlStr, bindArgs, err := BindingInsert(g, sch, mock.WidgetsObjectType, map[string]interface{}{"Age": 3, "NextAge": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != "INSERT INTO widgets (Age) VALUES (?)" {
+		t.Fatal("unexpected sql", sqlStr)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{3}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	// A retrieved widget carries the generated value, which must not be
+	// written back.
+	obj := object.New(mock.WidgetsObjectType)
+	obj.SetCore("WidgetID", int64(1))
+	obj.SetCore("Age", int64(3))
+	obj.SetCore("NextAge", int64(4))
+	sqlStr, _, _, err = BindingUpdate(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sqlStr, "NextAge") {
+		t.Fatal("expected NextAge not to be updated", sqlStr)
+	}
+
+	obj.ResetChangedColumns()
+	obj.Set("NextAge", int64(5))
+	if _, _, _, err = BindingUpdate(g, sch, obj); err == nil {
+		t.Fatal("expected an error for an update of only read-only columns")
+	}
+}
+
+func TestRenderCreateGeneratedColumn(t *testing.T) {
+	g := New()
+	col := mock.WidgetSchema().Tables[mock.WidgetsObjectType].Columns["NextAge"]
+	sqlStr := common.RenderCreateColumn(g, col, "", nil)
+	if !strings.HasPrefix(sqlStr, "NextAge INTEGER GENERATED ALWAYS AS (Age + 1) ") {
+		t.Fatal("unexpected column definition", sqlStr)
+	}
+}
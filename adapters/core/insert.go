@@ -1,10 +1,12 @@
 package core
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	sg "github.com/rbastic/dyndao/sqlgen"
 
@@ -14,13 +16,28 @@ import (
 	"github.com/tidwall/gjson"
 )
 
-func CoreBindingInsert(g *sg.SQLGenerator, schTable *schema.Table, data map[string]interface{}, identityCol string, fieldsMap map[string]*schema.Column) ([]string, []string, []interface{}) {
-	dataLen := len(data)
-	bindNames := make([]string, dataLen)
-	colNames := make([]string, dataLen)
-	bindArgs := make([]interface{}, dataLen)
-	i := 0
-	for k, v := range data {
+func CoreBindingInsert(g *sg.SQLGenerator, schTable *schema.Table, data map[string]interface{}, identityCol string, fieldsMap map[string]*schema.Column) ([]string, []string, []interface{}, error) {
+	// Order the keys so that the rendered SQL and bind order are deterministic
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	schTable.SortColumns(keys)
+
+	bindNames, colNames, bindArgs, _, err := bindInsertRow(g, schTable, keys, data, fieldsMap, 0)
+	return bindNames, colNames, bindArgs, err
+}
+
+// bindInsertRow renders the values of data for the columns keys, numbering
+// the placeholders from bound. It returns the bind names, the column names,
+// the bind arguments and the number of placeholders rendered so far, or an
+// error if a value cannot be bound to its column.
+func bindInsertRow(g *sg.SQLGenerator, schTable *schema.Table, keys []string, data map[string]interface{}, fieldsMap map[string]*schema.Column, bound int) ([]string, []string, []interface{}, int, error) {
+	bindNames := make([]string, len(keys))
+	colNames := make([]string, len(keys))
+	bindArgs := make([]interface{}, len(keys))
+	for i, k := range keys {
+		v := data[k]
 		realName := schTable.GetColumnName(k)
 
 		colNames[i] = realName
@@ -31,12 +48,13 @@ func CoreBindingInsert(g *sg.SQLGenerator, schTable *schema.Table, data map[stri
 			if !ok {
 				panic(fmt.Sprintf("coreBindingInsert: Unknown field for key: [%s] realName: [%s] for table %s", k, realName, schTable.Name))
 			}
-			r = g.RenderBindingValue(f)
+			r = g.Placeholder(bound, f.Name)
 		}
 
 		if v == nil {
 			bindNames[i] = r
 			bindArgs[i] = v
+			bound++
 		} else {
 			switch v.(type) {
 			case *object.SQLValue:
@@ -50,16 +68,21 @@ func CoreBindingInsert(g *sg.SQLGenerator, schTable *schema.Table, data map[stri
 				bindArgs[i] = nil
 			default:
 				bindNames[i] = r
+				bound++
 				barg, err := g.RenderInsertValue(fieldsMap[realName], v)
 				if err != nil {
-					panic(err)
+					return nil, nil, nil, bound, err
+				}
+				if na, ok := barg.(sql.NamedArg); ok {
+					// Bind by the placeholder's name, which need not be the column's
+					na.Name = placeholderName(r)
+					barg = na
 				}
 				bindArgs[i] = barg
 			}
 		}
-		i++
 	}
-	return bindNames, colNames, bindArgs
+	return bindNames, colNames, bindArgs, bound, nil
 }
 
 // BindingInsert generates the SQL for a given INSERT statement for oracle with binding parameter values
@@ -76,7 +99,7 @@ func BindingInsert(g *sg.SQLGenerator, sch *schema.Schema, table string, data ma
 		return "", nil, errors.New("BindingInsert: Table map unavailable for table " + table)
 	}
 
-	tableName := schema.GetTableName(schTable.Name, table)
+	tableName := sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)
 
 	fieldsMap := schTable.Columns
 	if fieldsMap == nil {
@@ -85,14 +108,44 @@ func BindingInsert(g *sg.SQLGenerator, sch *schema.Schema, table string, data ma
 
 	identityCol := schTable.Primary
 
-	bindNames, colNames, bindArgs := g.CoreBindingInsert(g, schTable, data, identityCol, fieldsMap)
+	// Read-only columns are populated by the database
+	for k := range data {
+		if f := schTable.GetColumn(k); f != nil && f.IsReadOnly() {
+			data = writableData(schTable, data)
+			break
+		}
+	}
+
+	bindNames, colNames, bindArgs, err := g.CoreBindingInsert(g, schTable, data, identityCol, fieldsMap)
+	if err != nil {
+		return "", nil, errors.New("BindingInsert: " + err.Error())
+	}
 	bindArgs = nils.RemoveNilsIfNeeded(bindArgs)
 
 	sqlStr := g.BindingInsertSQL(schTable, tableName, colNames, bindNames, identityCol)
+	if g.FixLastInsertIDbug && schTable.UsesLastInsertID() {
+		// The generated key is returned into an out bind, whose destination
+		// the caller sets
+		_, name := g.LastInsertIDBind(len(colNames), identityCol)
+		bindArgs = append(bindArgs, sql.Named(name, sql.Out{}))
+	}
 
 	return sqlStr, bindArgs, nil
 }
 
+// writableData returns a copy of data without the values of read-only
+// columns.
+func writableData(schTable *schema.Table, data map[string]interface{}) map[string]interface{} {
+	writable := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if f := schTable.GetColumn(k); f != nil && f.IsReadOnly() {
+			continue
+		}
+		writable[k] = v
+	}
+	return writable
+}
+
 func BindingInsertSQL(schTable *schema.Table, tableName string, colNames []string, bindNames []string, identityCol string) string {
 	var sqlStr string
 	sqlStr = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
@@ -103,6 +156,11 @@ func BindingInsertSQL(schTable *schema.Table, tableName string, colNames []strin
 }
 
 func RenderInsertValue(f *schema.Column, value interface{}) (interface{}, error) {
+	if f.IsDecimal() {
+		if d, ok, err := f.DecimalString(value); ok {
+			return d, err
+		}
+	}
 	switch value.(type) {
 	case string:
 		str, ok := value.(string)
@@ -120,14 +178,22 @@ func RenderInsertValue(f *schema.Column, value interface{}) (interface{}, error)
 		num := value.(int64)
 		return num, nil
 	case uint64:
-		num := value.(uint64)
-		return fmt.Sprintf("%d", num), nil
+		return f.Uint64Value(value.(uint64))
 	case float64:
 		num := value.(float64)
 		if f.IsNumber {
 			return int64(num), nil
 		}
 		return fmt.Sprintf("%f", num), nil
+	case bool:
+		return value, nil
+	case []byte:
+		return value, nil
+	case time.Time:
+		t := value.(time.Time)
+		return t, nil
+	case *time.Time:
+		return value, nil
 	case *object.SQLValue:
 		val := value.(*object.SQLValue)
 		return val.String(), nil
@@ -13,17 +13,43 @@ func New() *sg.SQLGenerator {
 	}
 
 	g.CreateTable = sg.FnCreateTable(CreateTable)
+	g.CreateIndex = sg.FnCreateIndex(CreateIndex)
+	g.AddColumn = sg.FnAddColumn(AddColumn)
+	g.DropColumn = sg.FnDropColumn(DropColumn)
 	g.DropTable = sg.FnDropTable(DropTable)
+	g.BindingTableExists = sg.FnBindingTableExists(BindingTableExists)
+	g.Truncate = sg.FnTruncate(Truncate)
+	g.Savepoint = sg.FnSavepoint(Savepoint)
+	g.RollbackToSavepoint = sg.FnSavepoint(RollbackToSavepoint)
+	g.ReleaseSavepoint = sg.FnSavepoint(ReleaseSavepoint)
+	g.ColumnDBType = sg.FnColumnDBType(ColumnDBType)
+	g.LogicalTypes = LogicalTypes
 	g.CoreBindingInsert = sg.FnCoreBindingInsert(CoreBindingInsert)
 	g.BindingInsert = sg.FnBindingInsert(BindingInsert)
 	g.BindingInsertSQL = sg.FnBindingInsertSQL(BindingInsertSQL)
+	g.BindingBulkInsert = sg.FnBindingBulkInsert(BindingBulkInsert)
+	g.BindingBulkInsertSQL = sg.FnBindingBulkInsertSQL(BindingBulkInsertSQL)
+	g.BindingBulkUpdate = sg.FnBindingBulkUpdate(BindingBulkUpdate)
+	g.BulkUpdateSQL = sg.FnBulkUpdateSQL(BulkUpdateSQL)
 	g.BindingRetrieve = sg.FnBindingRetrieve(BindingRetrieve)
+	g.BindingRetrieveColumns = sg.FnBindingRetrieveColumns(BindingRetrieveColumns)
+	g.BindingRetrieveJoined = sg.FnBindingRetrieveJoined(BindingRetrieveJoined)
+	g.BindingQuery = sg.FnBindingQuery(BindingQuery)
+	g.BindingExists = sg.FnBindingExists(BindingExists)
+	g.BindingAggregate = sg.FnBindingAggregate(BindingAggregate)
 	g.BindingUpdate = sg.FnBindingUpdate(BindingUpdate)
 	g.BindingDelete = sg.FnBindingDelete(BindingDelete)
+	g.BindingDeleteReturning = sg.FnBindingDeleteReturning(BindingDeleteReturning)
+	g.DeleteReturningSQL = sg.FnDeleteReturningSQL(DeleteReturningSQL)
+	g.Placeholder = sg.FnPlaceholder(Placeholder)
 	g.RenderBindingValue = sg.FnRenderBindingValue(RenderBindingValue)
 	g.RenderBindingValueWithInt = sg.FnRenderBindingValueWithInt(RenderBindingValueWithInt)
 	g.RenderWhereClause = sg.FnRenderWhereClause(RenderWhereClause)
 	g.RenderInsertValue = sg.FnRenderInsertValue(RenderInsertValue)
+	g.RenderLimit = sg.FnRenderLimit(RenderLimit)
+	g.QuoteIdentifier = sg.FnQuoteIdentifier(QuoteIdentifier)
+	g.QuoteLiteral = sg.FnQuoteLiteral(QuoteLiteral)
+	g.IsRetryable = sg.FnIsRetryable(IsRetryable)
 	g.RenderUpdateWhereClause = sg.FnRenderUpdateWhereClause(RenderUpdateWhereClause)
 	g.DynamicObjectSetter = sg.FnDynamicObjectSetter(DynamicObjectSetter)
 	g.MakeColumnPointers = sg.FnMakeColumnPointers(MakeColumnPointers)
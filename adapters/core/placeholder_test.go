@@ -0,0 +1,182 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+// dollarPlaceholder renders Postgres style $n placeholders.
+func dollarPlaceholder(index int, name string) string {
+	return fmt.Sprintf("$%d", index+1)
+}
+
+func TestPlaceholderStyle(t *testing.T) {
+	g := New()
+	g.Placeholder = dollarPlaceholder
+	sch := mock.WidgetSchema()
+
+	q := query.New().From("widgets").Where("Color", "=", "red").And("Age", ">", 18)
+	sqlStr, _, _, err := BindingQuery(g, sch, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(sqlStr, "WHERE Color = $1 AND Age > $2") {
+		t.Fatal("expected numbered placeholders in the WHERE clause, got", sqlStr)
+	}
+
+	obj := object.New("widgets")
+	obj.Set("Age", 18)
+	obj.Set("Color", "red")
+	whereClause, _, err := RenderWhereClause(g, sch.GetTable("widgets"), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if whereClause != "Age = $1 AND Color = $2" {
+		t.Fatal("unexpected where clause", whereClause)
+	}
+
+	obj.Set("Created", object.NewSQLValue("CURRENT_TIMESTAMP"))
+	sqlStr, bindArgs, err := BindingInsert(g, sch, "widgets", obj.KV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bindArgs) != 2 || !strings.Contains(sqlStr, "$1") || !strings.Contains(sqlStr, "$2") || strings.Contains(sqlStr, "$3") {
+		t.Fatal("expected one placeholder per bound value, got", sqlStr, bindArgs)
+	}
+}
+
+func TestBindingInsertDeterministic(t *testing.T) {
+	g := New()
+	sch := mock.WidgetSchema()
+
+	obj := object.New("widgets")
+	obj.Set("WidgetID", 7)
+	obj.Set("Color", "red")
+	obj.Set("Age", 18)
+	obj.Set("Created", object.NewSQLValue("CURRENT_TIMESTAMP"))
+
+	first, firstArgs, err := BindingInsert(g, sch, "widgets", obj.KV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		sqlStr, bindArgs, err := BindingInsert(g, sch, "widgets", obj.KV)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sqlStr != first || fmt.Sprint(bindArgs) != fmt.Sprint(firstArgs) {
+			t.Fatal("expected identical INSERT statements, got", first, "and", sqlStr)
+		}
+	}
+	if !strings.Contains(first, "(Age,Color,Created,WidgetID)") {
+		t.Fatal("expected columns in sorted order, got", first)
+	}
+}
+
+func TestBindingBulkInsert(t *testing.T) {
+	g := New()
+	g.Placeholder = dollarPlaceholder
+	sch := mock.WidgetSchema()
+
+	rows := []map[string]interface{}{
+		{"Age": 1, "Color": "red"},
+		{"Age": 2, "Color": object.NewSQLValue("'blue'")},
+		{"Age": 3, "Color": "green"},
+	}
+	sqlStr, bindArgs, err := BindingBulkInsert(g, sch, "widgets", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "INSERT INTO widgets (Age,Color) VALUES ($1,$2),($3,'blue'),($4,$5) RETURNING WidgetID"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if fmt.Sprint(bindArgs) != "[1 red 2 3 green]" {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	rows[1] = map[string]interface{}{"Age": 2}
+	if _, _, err := BindingBulkInsert(g, sch, "widgets", rows); err == nil {
+		t.Fatal("expected rows with different columns to be refused")
+	}
+}
+
+func TestBindingBulkUpdate(t *testing.T) {
+	g := New()
+	g.Placeholder = dollarPlaceholder
+	sch := mock.WidgetSchema()
+
+	rows := []map[string]interface{}{
+		{"WidgetID": 1, "Age": 10, "Color": "red"},
+		{"WidgetID": 2, "Age": 20, "Color": nil},
+	}
+	bu, err := BindingBulkUpdate(g, sch, "widgets", []string{"WidgetID"}, []string{"Age", "Color"}, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"CREATE TEMPORARY TABLE dyndao_bulk_widgets AS SELECT WidgetID,Age,Color FROM widgets WHERE 1=0",
+		"INSERT INTO dyndao_bulk_widgets (WidgetID,Age,Color) VALUES ($1,$2,$3),($4,$5,NULL)",
+		"UPDATE widgets SET Age = dyndao_bulk_widgets.Age,Color = dyndao_bulk_widgets.Color FROM dyndao_bulk_widgets WHERE widgets.WidgetID = dyndao_bulk_widgets.WidgetID",
+		"DROP TABLE dyndao_bulk_widgets",
+	}
+	for i, got := range []string{bu.Create, bu.Load, bu.Update, bu.Drop} {
+		if got != expected[i] {
+			t.Fatalf("expected [%s], got [%s]", expected[i], got)
+		}
+	}
+	if fmt.Sprint(bu.LoadArgs) != "[1 10 red 2 20]" {
+		t.Fatal("unexpected load args", bu.LoadArgs)
+	}
+
+	delete(rows[1], "Age")
+	if _, err := BindingBulkUpdate(g, sch, "widgets", []string{"WidgetID"}, []string{"Age", "Color"}, rows); err == nil {
+		t.Fatal("expected a row without a value to be refused")
+	}
+}
+
+func TestBindingSQLValues(t *testing.T) {
+	g := New()
+	g.Placeholder = dollarPlaceholder
+	g.IsTimestampType = func(k string) bool { return k == "datetime" }
+	sch := mock.WidgetSchema()
+
+	data := map[string]interface{}{
+		"Age":     18,
+		"Color":   object.NewSQLValue("UPPER('red')"),
+		"Created": object.NewSQLValue("NOW()"),
+		"Price":   "9.99",
+	}
+	sqlStr, bindArgs, err := BindingInsert(g, sch, "widgets", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != "INSERT INTO widgets (Age,Color,Created,Price) VALUES ($1,UPPER('red'),NOW(),$2)" {
+		t.Fatal("expected the expressions to be rendered in place of binds, got", sqlStr)
+	}
+	if fmt.Sprint(bindArgs) != "[18 9.99]" {
+		t.Fatal("expected only the other values to be bound, got", bindArgs)
+	}
+
+	obj := object.New("widgets")
+	obj.SetCore("WidgetID", 1)
+	obj.SetCore("Created", "2017-06-01T12:30:15Z")
+	obj.SetCore("Age", 18)
+	obj.Set("Created", object.NewSQLValue("NOW()"))
+	obj.Set("Age", 19)
+	sqlStr, bindArgs, _, err = BindingUpdate(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sqlStr, "Created = NOW()") || !strings.Contains(sqlStr, "Age = $1") || fmt.Sprint(bindArgs) != "[19]" {
+		t.Fatal("expected the expression to be rendered in place of a bind, got", sqlStr, bindArgs)
+	}
+	if !strings.HasSuffix(sqlStr, "WHERE WidgetID = $2") {
+		t.Fatal("expected the WHERE clause to be numbered after the SET clause, got", sqlStr)
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
+	"github.com/rbastic/dyndao/schema"
+	"github.com/rbastic/dyndao/schema/test/mock"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+func TestTablePrefix(t *testing.T) {
+	g := New()
+	g.RenderCreateColumn = func(g *sg.SQLGenerator, f *schema.Column) string {
+		return f.Name + " " + ColumnDBType(g, f)
+	}
+	sch := mock.NestedSchema()
+	sch.TablePrefix = "staging_"
+	sch.Tables["people"].Children["addresses"].LocalColumn = "PersonID"
+	sch.Tables["people"].Children["addresses"].ForeignColumn = "PersonID"
+
+	sqlStr, _, err := BindingInsert(g, sch, "people", map[string]interface{}{"Name": "Ryan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != "INSERT INTO staging_people (Name) VALUES (?)" {
+		t.Fatal("unexpected insert", sqlStr)
+	}
+
+	obj := object.New("people")
+	obj.Set("PersonID", 1)
+	sqlStr, _, _, err = BindingRetrieve(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sqlStr, " FROM staging_people WHERE ") {
+		t.Fatal("unexpected retrieve", sqlStr)
+	}
+
+	sqlStr, err = CreateTable(g, sch, "addresses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sqlStr, "CREATE TABLE staging_addresses (") || !strings.Contains(sqlStr, "REFERENCES staging_people") {
+		t.Fatal("expected the prefix on the table and the referenced table", sqlStr)
+	}
+}
+
+func TestSchemaName(t *testing.T) {
+	g := New()
+	g.IsTimestampType = func(string) bool { return false }
+	g.RenderCreateColumn = func(g *sg.SQLGenerator, f *schema.Column) string {
+		return f.Name + " " + ColumnDBType(g, f)
+	}
+	sch := mock.NestedSchema()
+	sch.SchemaName = "hr"
+	sch.Tables["people"].Children["addresses"].LocalColumn = "PersonID"
+	sch.Tables["people"].Children["addresses"].ForeignColumn = "PersonID"
+	sch.Tables["addresses"].SchemaName = "sales.crm"
+
+	sqlStr, _, err := BindingInsert(g, sch, "people", map[string]interface{}{"Name": "Ryan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != `INSERT INTO "hr"."people" (Name) VALUES (?)` {
+		t.Fatal("unexpected insert", sqlStr)
+	}
+
+	obj := object.New("people")
+	obj.Set("PersonID", 1)
+	obj.Set("Name", "Ryan")
+	sqlStr, _, _, err = BindingUpdate(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sqlStr, `UPDATE "hr"."people" SET `) {
+		t.Fatal("unexpected update", sqlStr)
+	}
+	sqlStr, _, err = BindingDelete(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sqlStr, `DELETE FROM "hr"."people" `) {
+		t.Fatal("unexpected delete", sqlStr)
+	}
+
+	sqlStr, _, _, err = BindingAggregate(g, sch, "addresses", nil, []query.Aggregate{query.Count("*")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(sqlStr, ` FROM "sales"."crm"."addresses"`) {
+		t.Fatal("expected the table's own schema and catalog to qualify it", sqlStr)
+	}
+
+	sqlStr, err = CreateTable(g, sch, "addresses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sqlStr, `CREATE TABLE "sales"."crm"."addresses" (`) || !strings.Contains(sqlStr, `REFERENCES "hr"."people"`) {
+		t.Fatal("expected the schema on the table and the referenced table", sqlStr)
+	}
+
+	sqlStr, err = CreateIndex(g, sch, "people", schema.Index{Columns: []string{"Name"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlStr != `CREATE INDEX people_Name_idx ON "hr"."people" (Name)` {
+		t.Fatal("unexpected index", sqlStr)
+	}
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/query"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingQuery renders the SELECT statement described by a query.Query. It
+// returns the sqlStr, the columns selected (the DefaultColumns unless the
+// query Selects others) and the binding arguments, in the same manner as
+// BindingRetrieve. Subqueries are rendered inline, binding their arguments
+// in turn. As MySQL refuses a LIMIT and SQL Server an ORDER BY inside IN
+// (...), a subquery with an order, a limit or an offset is an error.
+func BindingQuery(g *sg.SQLGenerator, sch *schema.Schema, q *query.Query) (string, []string, []interface{}, error) {
+	if err := q.Err(); err != nil {
+		return "", nil, nil, errors.Wrap(err, "BindingQuery")
+	}
+	return renderQuery(g, sch, q, 0)
+}
+
+// renderQuery renders a query whose bind arguments start at index start
+// within the statement.
+func renderQuery(g *sg.SQLGenerator, sch *schema.Schema, q *query.Query, start int) (string, []string, []interface{}, error) {
+	schTable := sch.GetTable(q.Table)
+	if schTable == nil {
+		return "", nil, nil, errors.New("BindingQuery: Table map unavailable for table " + q.Table)
+	}
+	columns := schTable.DefaultColumns()
+	if len(q.Columns) > 0 {
+		columns = make([]string, len(q.Columns))
+		for i, c := range q.Columns {
+			f := schTable.GetColumn(c)
+			if f == nil {
+				return "", nil, nil, errors.New("BindingQuery: unknown selected field " + c + " in table " + q.Table)
+			}
+			columns[i] = f.Name
+		}
+	}
+
+	parts := []string{
+		fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ","), sch.QualifiedTableName(schTable.Name, q.Table, g.QuoteIdentifier)),
+	}
+
+	var bindArgs []interface{}
+	if len(q.Conditions) > 0 {
+		preds := make([]sg.Predicate, len(q.Conditions))
+		for i, c := range q.Conditions {
+			f := schTable.GetColumn(c.Column)
+			if f == nil {
+				return "", nil, nil, errors.New("BindingQuery: unknown field " + c.Column + " in table " + q.Table)
+			}
+			preds[i] = sg.Predicate{Column: f.Name, Operator: c.Operator, Value: c.Value, Or: c.Or}
+		}
+		where := sg.NewWhereBuilder(g)
+		where.NumberBinds = true
+		where.Start = start
+		where.Subquery = func(sub *query.Query, start int) (string, []interface{}, error) {
+			if len(sub.Orders) > 0 || sub.LimitRows > 0 || sub.OffsetRows > 0 {
+				return "", nil, errors.New("subquery on " + sub.Table + " may not be ordered or limited")
+			}
+			sqlStr, _, args, err := renderQuery(g, sch, sub, start)
+			return sqlStr, args, err
+		}
+		whereClause, whereArgs, err := where.Render(preds)
+		if err != nil {
+			return "", nil, nil, errors.Wrap(err, "BindingQuery")
+		}
+		bindArgs = whereArgs
+		parts = append(parts, "WHERE "+whereClause)
+	}
+
+	if len(q.Orders) > 0 {
+		orders := make([]string, len(q.Orders))
+		for i, ord := range q.Orders {
+			f := schTable.GetColumn(ord.Column)
+			if f == nil {
+				return "", nil, nil, errors.New("BindingQuery: unknown order field " + ord.Column + " in table " + q.Table)
+			}
+			if ord.Descending {
+				orders[i] = f.Name + " DESC"
+			} else {
+				orders[i] = f.Name + " ASC"
+			}
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(orders, ","))
+	}
+
+	if limit := g.RenderLimit(len(q.Orders) > 0, q.LimitRows, q.OffsetRows); limit != "" {
+		parts = append(parts, limit)
+	}
+
+	return strings.Join(parts, " "), columns, bindArgs, nil
+}
+
+// RenderLimit renders a LIMIT / OFFSET clause, or the empty string if neither
+// is set.
+func RenderLimit(hasOrderBy bool, limit int64, offset int64) string {
+	switch {
+	case limit > 0 && offset > 0:
+		return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+	case limit > 0:
+		return fmt.Sprintf("LIMIT %d", limit)
+	case offset > 0:
+		// LIMIT is required before OFFSET in MySQL and SQLite; -1 is not
+		// accepted by MySQL, so use the largest unsigned value instead.
+		return fmt.Sprintf("LIMIT 18446744073709551615 OFFSET %d", offset)
+	}
+	return ""
+}
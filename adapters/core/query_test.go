@@ -0,0 +1,128 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/query"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func TestBindingQuery(t *testing.T) {
+	g := New()
+	sch := mock.WidgetSchema()
+
+	q := query.New().From("widgets").Where("Color", "=", "red").And("Age", ">", 18).OrderBy("Color").Limit(10)
+	sqlStr, columns, bindArgs, err := BindingQuery(g, sch, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT WidgetID,Age,Color,Created,Active,NextAge FROM widgets WHERE Color = ? AND Age > ? ORDER BY Color ASC LIMIT 10"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if !reflect.DeepEqual(columns, sch.GetTable("widgets").EssentialColumns) {
+		t.Fatal("unexpected columns", columns)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{"red", 18}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	q = query.New().From("widgets").Where("Age", "<", 1).Or("Age", ">", 99).OrderByDesc("Age").Offset(5)
+	sqlStr, _, _, err = BindingQuery(g, sch, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT WidgetID,Age,Color,Created,Active,NextAge FROM widgets WHERE Age < ? OR Age > ? ORDER BY Age DESC LIMIT 18446744073709551615 OFFSET 5"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+
+	q = query.New().From("widgets").Where("Color", "IN", []string{"red", "blue"}).And("Created", "=", nil)
+	sqlStr, _, bindArgs, err = BindingQuery(g, sch, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT WidgetID,Age,Color,Created,Active,NextAge FROM widgets WHERE Color IN (?,?) AND Created IS NULL"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{"red", "blue"}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	if _, _, _, err = BindingQuery(g, sch, query.New().From("widgets").Where("Nope", "=", 1)); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
+
+func TestBindingQuerySubquery(t *testing.T) {
+	g := New()
+	g.Placeholder = dollarPlaceholder
+	sch := mock.NestedSchema()
+
+	inCA := query.New().Select("PersonID").From("addresses").Where("State", "=", "CA").And("City", "<>", "Fresno")
+	q := query.New().From("people").Where("Name", "LIKE", "R%").And("PersonID", "IN", inCA).And("NullInt", ">", 1)
+	sqlStr, columns, bindArgs, err := BindingQuery(g, sch, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT PersonID,Name,NullText,NullInt,NullVarchar,NullBlob FROM people WHERE Name LIKE $1 AND " +
+		"PersonID IN (SELECT PersonID FROM addresses WHERE State = $2 AND City <> $3) AND NullInt > $4"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if len(columns) != 6 {
+		t.Fatal("expected the outer query's columns, got", columns)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{"R%", "CA", "Fresno", 1}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	q = query.New().From("people").Where("PersonID", "NOT IN", query.New().Select("PersonID").From("addresses").Where("Nope", "=", 1))
+	if _, _, _, err := BindingQuery(g, sch, q); err == nil {
+		t.Fatal("expected an error for an unknown field in the subquery")
+	}
+
+	// Subqueries can't be ordered or limited
+	for _, sub := range []*query.Query{
+		query.New().Select("PersonID").From("addresses").OrderBy("City"),
+		query.New().Select("PersonID").From("addresses").Limit(5),
+		query.New().Select("PersonID").From("addresses").Offset(5),
+	} {
+		q = query.New().From("people").Where("PersonID", "IN", sub)
+		if _, _, _, err := BindingQuery(g, sch, q); err == nil || !strings.Contains(err.Error(), "ordered or limited") {
+			t.Fatal("expected an error for an ordered or limited subquery, got", err)
+		}
+	}
+}
+
+func TestBindingAggregate(t *testing.T) {
+	g := New()
+	sch := mock.NestedSchema()
+
+	aggs := []query.Aggregate{query.Count("*"), query.Max("City").As("LastCity")}
+	having := []query.Having{{Aggregate: query.Count("*"), Operator: ">", Value: 1}}
+	sqlStr, names, bindArgs, err := BindingAggregate(g, sch, "addresses", []string{"PersonID"}, aggs, having)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `SELECT "PersonID",COUNT(*) AS "count",MAX("City") AS "LastCity" FROM "addresses" GROUP BY "PersonID" HAVING COUNT(*) > ?`
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if !reflect.DeepEqual(names, []string{"PersonID", "count", "LastCity"}) {
+		t.Fatal("unexpected names", names)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{1}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	if _, _, _, err = BindingAggregate(g, sch, "addresses", nil, []query.Aggregate{query.Sum("*")}, nil); err == nil {
+		t.Fatal("expected an error for SUM(*)")
+	}
+	if _, _, _, err = BindingAggregate(g, sch, "addresses", []string{"Nope"}, aggs, nil); err == nil {
+		t.Fatal("expected an error for an unknown group column")
+	}
+}
@@ -0,0 +1,18 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/rbastic/dyndao/adapters/common"
+)
+
+// QuoteIdentifier quotes a table or column name with ANSI double quotes.
+func QuoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// QuoteLiteral renders s as a standard SQL string literal, doubling any
+// single quotes.
+func QuoteLiteral(s string) string {
+	return common.QuoteString(s)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -12,95 +13,74 @@ import (
 )
 
 func RenderUpdateWhereClause(g *sg.SQLGenerator, schTable *schema.Table, fieldsMap map[string]*schema.Column, obj *object.Object) (string, []interface{}, error) {
-	var bindArgs []interface{}
-	var whereClause string
-
 	if len(obj.KV) == 0 {
 		return "", nil, nil
 	}
 
-	if !schTable.MultiKey {
-		f := fieldsMap[schTable.Primary]
-		sqlName := f.Name
-		whereClause = fmt.Sprintf("%s = %s", sqlName, g.RenderBindingValue(f))
-		bindArgs = make([]interface{}, 1)
-		bindVal := obj.Get(schTable.Primary)
+	// MultiKey means that there could be more than just a single primary key
+	// on a table. In this case, we definitely care about involving the entire
+	// composite key in the index, so every part of it must be set: a missing
+	// part would otherwise render as IS NULL and update nothing.
+	keys := schTable.PrimaryKeyColumns()
+	preds := make([]sg.Predicate, len(keys))
+	for i, pk := range keys {
+		f := fieldsMap[pk]
+		bindVal := obj.Get(pk)
 		if bindVal == nil {
-			return "", nil, errors.New("dyndao: RenderUpdateWhereClause: missing primary key " + schTable.Primary)
-		}
-		bindArgs[0] = bindVal
-	} else {
-		// MultiKey means that there could be more than just a single primary key
-		// on a table. In this case, we definitely care about involving the entire
-		// composite key in the index.
-		foreignKeyLen := 0
-		if schTable.ForeignKeys != nil {
-			foreignKeyLen = len(schTable.ForeignKeys)
+			return "", nil, errors.New("dyndao: RenderUpdateWhereClause: missing primary key " + pk)
 		}
-
-		bindArgsLen := 1 + foreignKeyLen
-
-		whereKeys := make([]string, bindArgsLen)
-		bindArgs = make([]interface{}, bindArgsLen)
-
-		i := 0
-		{
-			pk := schTable.Primary
-			f := fieldsMap[schTable.Primary]
-			whereKeys[i] = fmt.Sprintf("%s = %s", f.Name, g.RenderBindingValue(f))
-
-			bindVal := obj.Get(pk)
-			if bindVal == nil {
-				return "", nil, errors.New("dyndao: RenderUpdateWhereClause: missing primary key " + pk)
-			}
-			bindArgs[i] = bindVal
-			i++
-		}
-
-		if foreignKeyLen > 0 {
-			for _, pk := range schTable.ForeignKeys {
-				f := fieldsMap[pk]
-				whereKeys[i] = fmt.Sprintf("%s = %s", f.Name, g.RenderBindingValue(f))
-				bindArgs[i] = obj.Get(pk)
-				i++
-			}
-		}
-
-		whereClause = strings.Join(whereKeys, " AND ")
+		preds[i] = sg.Predicate{Column: f.Name, Value: bindVal}
 	}
-	return whereClause, bindArgs, nil
+	return sg.NewWhereBuilder(g).Render(preds)
 }
 
 func RenderWhereClause(g *sg.SQLGenerator, schTable *schema.Table, obj *object.Object) (string, []interface{}, error) {
-	var whereClause string
-
 	if len(obj.KV) == 0 {
 		return "", nil, nil
 	}
 
-	whereKeys := make([]string, len(obj.KV))
-	bindArgs := make([]interface{}, len(obj.KV))
+	// Sort the keys so that the rendered SQL and bind order are deterministic
+	keys := make([]string, 0, len(obj.KV))
+	for k := range obj.KV {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 
-	i := 0
-	for k, v := range obj.KV {
+	preds := make([]sg.Predicate, len(keys))
+	for i, k := range keys {
 		f := schTable.GetColumn(k)
 		if f == nil {
 			return "", nil, errors.New("dyndao: RenderWhereClause: unknown field " + k + " in table " + obj.Type)
 		}
-		sqlName := f.Name
-		whereKeys[i] = fmt.Sprintf("%s = %s", sqlName, g.RenderBindingValue(f))
-		bindArgs[i] = v
-
-		i++
+		preds[i] = sg.Predicate{Column: f.Name, Value: obj.KV[k]}
 	}
-	whereClause = strings.Join(whereKeys, " AND ")
-	return whereClause, bindArgs, nil
+	return sg.NewWhereBuilder(g).Render(preds)
 }
 
-func RenderBindingValue(f *schema.Column) string {
+// Placeholder renders a positional ? placeholder; the index and name of the
+// bind argument are ignored.
+func Placeholder(index int, name string) string {
 	return "?"
 }
 
+// placeholderName returns the bind variable name of a named placeholder,
+// such as Age for :Age or @Age.
+func placeholderName(placeholder string) string {
+	return strings.TrimLeft(placeholder, ":@")
+}
+
+// numberedBindName names the index'th bind argument for a column, for
+// statements where the same column may be bound more than once.
+func numberedBindName(name string, index int) string {
+	return fmt.Sprintf("%s%d", name, index)
+}
+
+// RenderBindingValue is deprecated, use the generator's Placeholder.
+func RenderBindingValue(f *schema.Column) string {
+	return Placeholder(0, f.Name)
+}
+
+// RenderBindingValueWithInt is deprecated, use the generator's Placeholder.
 func RenderBindingValueWithInt(f *schema.Column, i int64) string {
-	return "?"
+	return Placeholder(int(i), numberedBindName(f.Name, int(i)))
 }
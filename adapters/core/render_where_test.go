@@ -0,0 +1,160 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func TestRenderWhereClauseIsDeterministic(t *testing.T) {
+	g := New()
+	schTable := mock.WidgetSchema().GetTable(mock.PartsObjectType)
+
+	obj := object.New(mock.PartsObjectType)
+	obj.Set("Revision", int64(2))
+	obj.Set("PartNo", int64(7))
+	obj.Set("Description", "x")
+
+	for i := 0; i < 10; i++ {
+		whereClause, bindArgs, err := RenderWhereClause(g, schTable, obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if whereClause != "Description = ? AND PartNo = ? AND Revision = ?" {
+			t.Fatal("unexpected where clause", whereClause)
+		}
+		if !reflect.DeepEqual(bindArgs, []interface{}{"x", int64(7), int64(2)}) {
+			t.Fatal("unexpected bind args", bindArgs)
+		}
+	}
+}
+
+func TestBindingRetrieveColumns(t *testing.T) {
+	g := New()
+	sch := mock.NestedSchema()
+
+	obj := object.New("people")
+	obj.Set("PersonID", int64(1))
+	sqlStr, columns, bindArgs, err := BindingRetrieveColumns(g, sch, obj, []string{"Name", "PersonID"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT Name,PersonID FROM people WHERE PersonID = ?"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if len(columns) != 2 || columns[0] != "Name" || columns[1] != "PersonID" || len(bindArgs) != 1 {
+		t.Fatal("unexpected columns or bind args", columns, bindArgs)
+	}
+
+	if _, _, _, err = BindingRetrieveColumns(g, sch, obj, []string{"Nope"}); err == nil {
+		t.Fatal("expected an error for an unknown column")
+	}
+	if _, _, _, err = BindingRetrieveColumns(g, sch, obj, nil); err == nil {
+		t.Fatal("expected an error for an empty column list")
+	}
+}
+
+func TestBindingExists(t *testing.T) {
+	g := New()
+	sch := mock.NestedSchema()
+
+	obj := object.New("people")
+	obj.Set("Name", "Joe")
+	sqlStr, bindArgs, err := BindingExists(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT 1 FROM people WHERE Name = ? LIMIT 1"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if len(bindArgs) != 1 || bindArgs[0] != "Joe" {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+}
+
+func TestBindingRetrieveJoined(t *testing.T) {
+	g := New()
+	sch := mock.NestedSchema()
+
+	obj := object.New("people")
+	obj.Set("PersonID", int64(1))
+	sqlStr, joinColumns, bindArgs, err := BindingRetrieveJoined(g, sch, obj, "addresses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT t0.PersonID AS t0_PersonID,t0.Name AS t0_Name,t0.NullText AS t0_NullText,t0.NullInt AS t0_NullInt," +
+		"t0.NullVarchar AS t0_NullVarchar,t0.NullBlob AS t0_NullBlob," +
+		"t1.AddressID AS t1_AddressID,t1.PersonID AS t1_PersonID,t1.Address1 AS t1_Address1,t1.Address2 AS t1_Address2," +
+		"t1.City AS t1_City,t1.State AS t1_State,t1.Zip AS t1_Zip " +
+		"FROM people t0 LEFT JOIN addresses t1 ON t1.PersonID = t0.PersonID WHERE t0.PersonID = ?"
+	if sqlStr != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, sqlStr)
+	}
+	if len(joinColumns) != 13 || joinColumns[7].Table != "addresses" || joinColumns[7].Column != "PersonID" || joinColumns[7].Alias != "t1_PersonID" || !joinColumns[7].Child || joinColumns[0].Child {
+		t.Fatal("unexpected join columns", joinColumns)
+	}
+	if len(bindArgs) != 1 || bindArgs[0] != int64(1) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	// Without a child, the objects are selected alone
+	sqlStr, joinColumns, _, err = BindingRetrieveJoined(g, sch, obj, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sqlStr, "JOIN") || len(joinColumns) != 6 {
+		t.Fatal("expected no join, got", sqlStr)
+	}
+
+	if _, _, _, err = BindingRetrieveJoined(g, sch, obj, "widgets"); err == nil {
+		t.Fatal("expected an error joining a table that is not a child")
+	}
+}
+
+func TestRenderUpdateWhereClauseMissingKey(t *testing.T) {
+	g := New()
+	schTable := mock.WidgetSchema().GetTable(mock.PartsObjectType)
+
+	obj := object.New(mock.PartsObjectType)
+	obj.Set("PartNo", int64(7))
+	obj.Set("Description", "bolt")
+	_, _, err := RenderUpdateWhereClause(g, schTable, schTable.Columns, obj)
+	if err == nil || !strings.Contains(err.Error(), "missing primary key Revision") {
+		t.Fatal("expected the missing Revision to be reported, got", err)
+	}
+
+	obj.Set("Revision", int64(2))
+	whereClause, bindArgs, err := RenderUpdateWhereClause(g, schTable, schTable.Columns, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if whereClause != "PartNo = ? AND Revision = ?" || !reflect.DeepEqual(bindArgs, []interface{}{int64(7), int64(2)}) {
+		t.Fatal("unexpected where clause", whereClause, bindArgs)
+	}
+}
+
+func TestRenderWhereClauseNULL(t *testing.T) {
+	g := New()
+	schTable := mock.NestedSchema().GetTable(mock.PeopleObjectType)
+
+	obj := object.New(mock.PeopleObjectType)
+	obj.Set("Name", "Joe")
+	obj.Set("NullInt", object.NewNULLValue())
+	obj.Set("NullText", nil)
+
+	whereClause, bindArgs, err := RenderWhereClause(g, schTable, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if whereClause != "Name = ? AND NullInt IS NULL AND NullText IS NULL" {
+		t.Fatal("unexpected where clause", whereClause)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{"Joe"}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+}
@@ -12,8 +12,8 @@ import (
 )
 
 // BindingRetrieve accepts a schema and an object, constructing the appropriate SELECT
-// statement to retrieve the object. It will return sqlStr, the EssentialColumns used, and the
-// binding where clause.
+// statement to retrieve the object. It selects the table's DefaultColumns and will
+// return sqlStr, the columns used, and the binding where clause.
 // DEBUG mode may be turned on by setting an environment parameter, "DEBUG".
 func BindingRetrieve(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object) (string, []string, []interface{}, error) {
 	table := obj.Type
@@ -21,23 +21,43 @@ func BindingRetrieve(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object)
 	if schTable == nil {
 		return "", nil, nil, errors.New("BindingRetrieve: Table map unavailable for table " + table)
 	}
+	return g.BindingRetrieveColumns(g, sch, obj, schTable.DefaultColumns())
+}
 
-	whereClause, bindWhere, err := g.RenderWhereClause(g, schTable, obj)
-	if err != nil {
-		return "", nil, nil, errors.Wrap(err, "BindingRetrieve")
+// BindingRetrieveColumns is BindingRetrieve for an explicit list of columns,
+// which may be given by their aliases. Every column must exist in the schema
+// table. The returned column names are the real column names, in the order
+// requested.
+func BindingRetrieveColumns(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object, columns []string) (string, []string, []interface{}, error) {
+	table := obj.Type
+	schTable := sch.GetTable(table)
+	if schTable == nil {
+		return "", nil, nil, errors.New("BindingRetrieveColumns: Table map unavailable for table " + table)
+	}
+	if len(columns) == 0 {
+		return "", nil, nil, errors.New("BindingRetrieveColumns: no columns given for table " + table)
+	}
+
+	columnNames := make([]string, len(columns))
+	for i, c := range columns {
+		f := schTable.GetColumn(c)
+		if f == nil {
+			return "", nil, nil, errors.New("BindingRetrieveColumns: unknown column " + c + " in table " + table)
+		}
+		columnNames[i] = f.Name
 	}
 
-	if schTable.EssentialColumns == nil || len(schTable.EssentialColumns) == 0 {
-		return "", nil, nil, errors.New("BindingRetrieve: EssentialColumns is empty for table " + table)
+	whereClause, bindWhere, err := g.RenderWhereClause(g, schTable, obj)
+	if err != nil {
+		return "", nil, nil, errors.Wrap(err, "BindingRetrieveColumns")
 	}
-	columns := strings.Join(schTable.EssentialColumns, ",")
 
 	whereStr := ""
 	if whereClause != "" {
 		whereStr = "WHERE"
 	}
-	tableName := schema.GetTableName(schTable.Name, table)
+	tableName := sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)
 
-	sqlStr := fmt.Sprintf("SELECT %s FROM %s %s %s", columns, tableName, whereStr, whereClause)
-	return sqlStr, schTable.EssentialColumns, bindWhere, nil
+	sqlStr := fmt.Sprintf("SELECT %s FROM %s %s %s", strings.Join(columnNames, ","), tableName, whereStr, whereClause)
+	return sqlStr, columnNames, bindWhere, nil
 }
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingRetrieveJoined renders a single SELECT that retrieves the objects
+// matching obj together with their children of table child, by LEFT JOINing
+// that one child table. Joining a single child keeps the result to a row per
+// child, rather than the product of every child table's rows. An empty child
+// selects the matching objects alone. Tables are aliased t0 (the root) and t1
+// (the child) and every column is selected as <alias>_<column> so that names
+// cannot collide. The returned JoinColumns describe the selected columns in
+// order.
+func BindingRetrieveJoined(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object, child string) (string, []sg.JoinColumn, []interface{}, error) {
+	table := obj.Type
+	schTable := sch.GetTable(table)
+	if schTable == nil {
+		return "", nil, nil, errors.New("BindingRetrieveJoined: Table map unavailable for table " + table)
+	}
+
+	var joinColumns []sg.JoinColumn
+	var selects []string
+	addColumns := func(tableKey string, alias string, child bool, columns []string) {
+		for _, c := range columns {
+			jc := sg.JoinColumn{Table: tableKey, Column: c, Alias: alias + "_" + c, Child: child}
+			joinColumns = append(joinColumns, jc)
+			selects = append(selects, fmt.Sprintf("%s.%s AS %s", alias, c, jc.Alias))
+		}
+	}
+	addColumns(table, "t0", false, schTable.DefaultColumns())
+
+	var joins []string
+	if child != "" {
+		childSchema, ok := schTable.Children[child]
+		if !ok {
+			return "", nil, nil, errors.New("BindingRetrieveJoined: " + child + " is not a child of table " + table)
+		}
+		childTable := sch.GetTable(child)
+		if childTable == nil {
+			return "", nil, nil, errors.New("BindingRetrieveJoined: unknown child table " + child + " of table " + table)
+		}
+		localCols, foreignCols, err := childSchema.KeyColumns()
+		if err != nil {
+			return "", nil, nil, errors.Wrap(err, "BindingRetrieveJoined")
+		}
+		if len(localCols) == 0 {
+			// Without explicit key columns, children reference the parent's
+			// primary key by name, as in FleshenChildren.
+			localCols = []string{schTable.Primary}
+			foreignCols = []string{schTable.Primary}
+		}
+
+		conds := make([]string, len(localCols))
+		for j := range localCols {
+			conds[j] = fmt.Sprintf("t1.%s = t0.%s", childTable.GetColumnName(localCols[j]), schTable.GetColumnName(foreignCols[j]))
+		}
+		joins = append(joins, fmt.Sprintf("LEFT JOIN %s t1 ON %s", sch.QualifiedTableName(childTable.Name, child, g.QuoteIdentifier), strings.Join(conds, " AND ")))
+		addColumns(child, "t1", true, childTable.DefaultColumns())
+	}
+
+	// The where clause applies to the root table only, qualified so that it
+	// cannot be ambiguous with the children's columns.
+	keys := make([]string, 0, len(obj.KV))
+	for k := range obj.KV {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	preds := make([]sg.Predicate, len(keys))
+	for i, k := range keys {
+		f := schTable.GetColumn(k)
+		if f == nil {
+			return "", nil, nil, errors.New("BindingRetrieveJoined: unknown field " + k + " in table " + table)
+		}
+		preds[i] = sg.Predicate{Column: f.Name, Value: obj.KV[k]}
+	}
+	where := sg.NewWhereBuilder(g)
+	where.Prefix = "t0."
+	where.NumberBinds = true
+	whereClause, bindArgs, err := where.Render(preds)
+	if err != nil {
+		return "", nil, nil, errors.Wrap(err, "BindingRetrieveJoined")
+	}
+
+	parts := []string{
+		fmt.Sprintf("SELECT %s FROM %s t0", strings.Join(selects, ","), sch.QualifiedTableName(schTable.Name, table, g.QuoteIdentifier)),
+	}
+	parts = append(parts, joins...)
+	if whereClause != "" {
+		parts = append(parts, "WHERE "+whereClause)
+	}
+	return strings.Join(parts, " "), joinColumns, bindArgs, nil
+}
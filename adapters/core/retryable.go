@@ -0,0 +1,25 @@
+package core
+
+import (
+	"errors"
+	"strings"
+)
+
+// IsRetryable reports whether err is a transient conflict that running the
+// transaction again may resolve: a PostgreSQL serialization failure (40001)
+// or deadlock (40P01), from drivers whose errors report their SQLSTATE or
+// quote it in their message.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var se interface{ SQLState() string }
+	if errors.As(err, &se) {
+		switch se.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "SQLSTATE 40001") || strings.Contains(msg, "SQLSTATE 40P01")
+}
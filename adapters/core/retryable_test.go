@@ -0,0 +1,32 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// pgError is an error reporting its SQLSTATE, as PostgreSQL drivers do.
+type pgError struct{ code string }
+
+func (e pgError) Error() string    { return "pq: error " + e.code }
+func (e pgError) SQLState() string { return e.code }
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err       error
+		retryable bool
+	}{
+		{pgError{"40001"}, true},
+		{pgError{"40P01"}, true},
+		{fmt.Errorf("Save: %w", pgError{"40001"}), true},
+		{errors.New("ERROR: deadlock detected (SQLSTATE 40P01)"), true},
+		{pgError{"23505"}, false},
+		{errors.New("ERROR: relation does not exist (SQLSTATE 42P01)"), false},
+		{nil, false},
+	} {
+		if IsRetryable(tc.err) != tc.retryable {
+			t.Errorf("expected IsRetryable(%v) to be %v", tc.err, tc.retryable)
+		}
+	}
+}
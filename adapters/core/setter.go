@@ -3,6 +3,7 @@ package core
 import (
 	"database/sql"
 	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/adapters/common"
 	"github.com/rbastic/dyndao/object"
 	sg "github.com/rbastic/dyndao/sqlgen"
 	"time"
@@ -19,15 +20,51 @@ func DynamicObjectSetter(s *sg.SQLGenerator, columnNames []string, columnPointer
 
 		typeName := ct.DatabaseTypeName()
 
-		if s.IsTimestampType(typeName) {
-			val := v.(*time.Time)
-			obj.Set(columnNames[i], *val)
+		if common.IsDecimalColumn(s, ct) {
+			val := v.(*sql.NullString)
+			if val.Valid {
+				obj.Set(columnNames[i], val.String)
+			} else {
+				obj.SetNull(columnNames[i])
+			}
+			continue
+		} else if s.IsBooleanType(typeName) {
+			nullable, _ := ct.Nullable()
+			if nullable {
+				val := v.(*sql.NullBool)
+				if val.Valid {
+					obj.Set(columnNames[i], val.Bool)
+				} else {
+					obj.SetNull(columnNames[i])
+				}
+			} else {
+				val := v.(*bool)
+				obj.Set(columnNames[i], *val)
+			}
+			continue
+		} else if s.IsTimestampType(typeName) {
+			nullable, _ := ct.Nullable()
+			if nullable {
+				val := v.(*sql.NullTime)
+				if val.Valid {
+					obj.Set(columnNames[i], val.Time)
+				} else {
+					obj.SetNull(columnNames[i])
+				}
+			} else {
+				val := v.(*time.Time)
+				obj.Set(columnNames[i], *val)
+			}
 			continue
 		} else if s.IsStringType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
 				val := v.(*sql.NullString)
-				obj.Set(columnNames[i], *val)
+				if val.Valid {
+					obj.Set(columnNames[i], val.String)
+				} else {
+					obj.SetNull(columnNames[i])
+				}
 			} else {
 				val := v.(*string)
 				obj.Set(columnNames[i], *val)
@@ -40,6 +77,8 @@ func DynamicObjectSetter(s *sg.SQLGenerator, columnNames []string, columnPointer
 				val := v.(*sql.NullInt64)
 				if val.Valid {
 					obj.Set(columnNames[i], val.Int64)
+				} else {
+					obj.SetNull(columnNames[i])
 				}
 			} else {
 				val := v.(*int64)
@@ -52,17 +91,32 @@ func DynamicObjectSetter(s *sg.SQLGenerator, columnNames []string, columnPointer
 				val := v.(*sql.NullFloat64)
 				if val.Valid {
 					obj.Set(columnNames[i], val.Float64)
+				} else {
+					obj.SetNull(columnNames[i])
 				}
 			} else {
 				val := v.(*float64)
 				obj.Set(columnNames[i], *val)
 			}
 			continue
+		} else if s.IsBinaryType(typeName) {
+			// NULL scans into a nil slice
+			val := v.(*[]byte)
+			if *val != nil {
+				obj.Set(columnNames[i], *val)
+			} else {
+				obj.SetNull(columnNames[i])
+			}
+			continue
 		} else if s.IsLOBType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
 				val := v.(*sql.NullString)
-				obj.Set(columnNames[i], *val)
+				if val.Valid {
+					obj.Set(columnNames[i], val.String)
+				} else {
+					obj.SetNull(columnNames[i])
+				}
 			} else {
 				val := v.(*string)
 				obj.Set(columnNames[i], *val)
@@ -81,7 +135,19 @@ func MakeColumnPointers(s *sg.SQLGenerator, sliceLen int, columnTypes []*sql.Col
 		ct := columnTypes[i]
 		typeName := ct.DatabaseTypeName()
 
-		if s.IsNumberType(typeName) {
+		if common.IsDecimalColumn(s, ct) {
+			var d sql.NullString
+			columnPointers[i] = &d
+		} else if s.IsBooleanType(typeName) {
+			nullable, _ := ct.Nullable()
+			if nullable {
+				var b sql.NullBool
+				columnPointers[i] = &b
+			} else {
+				var b bool
+				columnPointers[i] = &b
+			}
+		} else if s.IsNumberType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
 				var j sql.NullInt64
@@ -94,13 +160,16 @@ func MakeColumnPointers(s *sg.SQLGenerator, sliceLen int, columnTypes []*sql.Col
 		} else if s.IsTimestampType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
-				var j time.Time
+				var j sql.NullTime
 				columnPointers[i] = &j
 			} else {
 				var j time.Time
 				columnPointers[i] = &j
 
 			}
+		} else if s.IsBinaryType(typeName) {
+			var b []byte
+			columnPointers[i] = &b
 		} else if s.IsLOBType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
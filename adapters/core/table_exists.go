@@ -0,0 +1,11 @@
+package core
+
+import (
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingTableExists renders a query against the standard
+// information_schema that returns a row if the named table exists.
+func BindingTableExists(g *sg.SQLGenerator, name string) (string, []interface{}) {
+	return "SELECT 1 FROM information_schema.tables WHERE table_name = " + g.Placeholder(0, "name"), []interface{}{name}
+}
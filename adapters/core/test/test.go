@@ -17,17 +17,22 @@
 package test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
 	"os"
 	"reflect"
+	"strings"
 	"time"
-	//"fmt"
 
 	"testing"
 
 	"github.com/rbastic/dyndao/object"
 	"github.com/rbastic/dyndao/orm"
+	"github.com/rbastic/dyndao/query"
 	"github.com/rbastic/dyndao/schema"
 	"github.com/rbastic/dyndao/schema/test/mock"
 
@@ -60,6 +65,25 @@ func PingCheck(t *testing.T, db *sql.DB) {
 	fatalIf(err)
 }
 
+func testORMPing(t *testing.T, db *sql.DB) {
+	o := orm.New(getSQLGen(), mock.NestedSchema(), db)
+	ctx, cancel := getDefaultContext()
+	err := o.Ping(ctx)
+	healthy := o.Healthy(ctx)
+	cancel()
+	fatalIf(err)
+	if !healthy {
+		t.Fatal("expected the ORM to be healthy")
+	}
+
+	// A context that is already done must fail the check
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if o.Healthy(ctx) {
+		t.Fatal("expected Healthy to fail with a cancelled context")
+	}
+}
+
 func dirtyTest(obj * object.Object) {
 	if obj.IsDirty() {
 		panic("system claims object is not saved")
@@ -85,21 +109,2815 @@ func Test(t *testing.T, getDBFn FnGetDB, getSGFN FnGetSG) {
 		PingCheck(t, db)
 	})
 
+	t.Run("TestORMPing", func(t *testing.T) {
+		testORMPing(t, db)
+	})
+
+	t.Run("TestORMClose", func(t *testing.T) {
+		testORMClose(t)
+	})
+
+	t.Run("TestConfigurePool", func(t *testing.T) {
+		testConfigurePool(t)
+	})
+
+	t.Run("TestTablePrefix", func(t *testing.T) {
+		testTablePrefix(t, db)
+	})
+
+	t.Run("TestExportDDL", func(t *testing.T) {
+		testExportDDL(t, db)
+	})
+
 	if os.Getenv("DROP_TABLES") != "" {
 		t.Run("TestDropTables", func(t *testing.T) {
 			TestDropTables(t, db)
 		})
 	}
 
-	t.Run("TestCreateTables", func(t *testing.T) {
-		TestCreateTables(t, db)
-	})
+	t.Run("TestCreateTables", func(t *testing.T) {
+		TestCreateTables(t, db)
+	})
+
+	TestSuiteNested(t, db)
+
+	t.Run("TestDropTables", func(t *testing.T) {
+		TestDropTables(t, db)
+	})
+
+	t.Run("TestWidgets", func(t *testing.T) {
+		TestSuiteWidgets(t, db)
+	})
+
+	t.Run("TestLibrary", func(t *testing.T) {
+		TestSuiteLibrary(t, db)
+	})
+
+	t.Run("TestDiamond", func(t *testing.T) {
+		TestSuiteDiamond(t, db)
+	})
+}
+
+// TestSuiteLibrary exercises multi-level fleshening using the mock library
+// schema. It creates and drops its own tables.
+func TestSuiteLibrary(t *testing.T, db *sql.DB) {
+	sch := mock.LibrarySchema()
+	o := orm.New(getSQLGen(), sch, db)
+
+	{
+		ctx, cancel := getDefaultContext()
+		err := o.CreateTables(ctx)
+		cancel()
+		fatalIf(err)
+	}
+	defer func() {
+		ctx, cancel := getDefaultContext()
+		err := o.DropTablesIfExists(ctx)
+		cancel()
+		fatalIf(err)
+	}()
+
+	t.Run("CreateTablesIfNotExists", func(t *testing.T) {
+		testCreateTablesIfNotExists(&o, t)
+	})
+
+	t.Run("CreateTable", func(t *testing.T) {
+		testCreateTable(&o, t)
+	})
+
+	library := makeLibrary(&o, t)
+
+	t.Run("FleshenChildrenDepth", func(t *testing.T) {
+		testFleshenChildrenDepth(&o, t, library)
+	})
+
+	t.Run("FleshenChildrenFor", func(t *testing.T) {
+		testFleshenChildrenFor(&o, t, library)
+	})
+
+	t.Run("FleshenCycle", func(t *testing.T) {
+		testFleshenCycle(&o, t, library)
+	})
+
+	t.Run("SaveAllPartial", func(t *testing.T) {
+		testSaveAllPartial(&o, t)
+	})
+
+	t.Run("DropTablesIfExists", func(t *testing.T) {
+		testDropTablesIfExists(&o, t)
+	})
+}
+
+// testDropTablesIfExists drops the library tables while they still hold
+// rows referencing each other.
+func testDropTablesIfExists(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	fatalIf(o.DropTables(ctx))
+	for _, table := range []string{mock.LibrariesObjectType, mock.MembersObjectType, mock.ShelvesObjectType, mock.BooksObjectType} {
+		exists, err := o.TableExists(ctx, table)
+		fatalIf(err)
+		if exists {
+			t.Fatal("expected table", table, "to be dropped")
+		}
+	}
+	if err := o.DropTables(ctx); err == nil {
+		t.Fatal("expected DropTables to fail for tables that don't exist")
+	}
+	fatalIf(o.DropTablesIfExists(ctx))
+}
+
+func testCreateTablesIfNotExists(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	if err := o.CreateTables(ctx); err == nil {
+		t.Fatal("expected CreateTables to fail for tables that already exist")
+	}
+	fatalIf(o.CreateTablesIfNotExists(ctx))
+
+	// A missing table is created alongside the existing ones
+	fatalIf(o.DropTable(ctx, mock.MembersObjectType))
+	exists, err := o.TableExists(ctx, mock.MembersObjectType)
+	fatalIf(err)
+	if exists {
+		t.Fatal("expected members not to exist after DropTable")
+	}
+	fatalIf(o.CreateTablesIfNotExists(ctx))
+	for _, table := range []string{mock.LibrariesObjectType, mock.MembersObjectType, mock.ShelvesObjectType, mock.BooksObjectType} {
+		exists, err := o.TableExists(ctx, table)
+		fatalIf(err)
+		if !exists {
+			t.Fatal("expected table", table, "to exist")
+		}
+	}
+}
+
+// testCreateTable recreates a single dropped table, leaving the others alone.
+func testCreateTable(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	if err := o.CreateTable(ctx, o.GetSchema(), "no_such_table"); err == nil {
+		t.Fatal("expected CreateTable to fail for a table that isn't in the schema")
+	}
+	if err := o.CreateTable(ctx, o.GetSchema(), mock.MembersObjectType); err == nil {
+		t.Fatal("expected CreateTable to fail for a table that already exists")
+	}
+
+	fatalIf(o.DropTable(ctx, mock.MembersObjectType))
+	fatalIf(o.CreateTable(ctx, o.GetSchema(), mock.MembersObjectType))
+	exists, err := o.TableExists(ctx, mock.MembersObjectType)
+	fatalIf(err)
+	if !exists {
+		t.Fatal("expected members to exist after CreateTable")
+	}
+}
+
+// makeLibrary inserts a library with one member and one shelf holding one
+// book, returning the (unfleshened) library.
+func makeLibrary(o *orm.ORM, t *testing.T) *object.Object {
+	insert := func(obj *object.Object) {
+		ctx, cancel := getDefaultContext()
+		_, err := o.Insert(ctx, nil, obj)
+		cancel()
+		fatalIf(err)
+	}
+
+	library := object.New(mock.LibrariesObjectType)
+	library.Set("Name", "Central")
+	insert(library)
+
+	shelf := object.New(mock.ShelvesObjectType)
+	shelf.Set("LibraryID", library.Get("LibraryID"))
+	shelf.Set("Label", "Fiction")
+	insert(shelf)
+
+	book := object.New(mock.BooksObjectType)
+	book.Set("ShelfID", shelf.Get("ShelfID"))
+	book.Set("Title", "Dune")
+	insert(book)
+
+	member := object.New(mock.MembersObjectType)
+	member.Set("LibraryID", library.Get("LibraryID"))
+	member.Set("Name", "Ada")
+	insert(member)
+
+	return library
+}
+
+func testFleshenChildrenFor(o *orm.ORM, t *testing.T, library *object.Object) {
+	ctx, cancel := getDefaultContext()
+	obj, err := o.FleshenChildrenFor(ctx, library.CloneWithoutState(), mock.MembersObjectType)
+	cancel()
+	fatalIf(err)
+	if len(obj.Children) != 1 || len(obj.Children[mock.MembersObjectType]) != 1 {
+		t.Fatal("expected only the members to be loaded, got", obj.Children)
+	}
+
+	ctx, cancel = getDefaultContext()
+	obj, err = o.FleshenChildrenFor(ctx, library.CloneWithoutState())
+	cancel()
+	fatalIf(err)
+	if len(obj.Children[mock.MembersObjectType]) != 1 || len(obj.Children[mock.ShelvesObjectType]) != 1 {
+		t.Fatal("expected every child to be loaded, got", obj.Children)
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.FleshenChildrenFor(ctx, library.CloneWithoutState(), mock.BooksObjectType)
+	cancel()
+	if err == nil {
+		t.Fatal("expected an error for a table that is not a child of libraries")
+	}
+}
+
+// makePartialLibrary builds an unsaved library whose second shelf, which
+// holds a book, violates the NOT NULL constraint on Label.
+func makePartialLibrary(name string) *object.Object {
+	library := object.New(mock.LibrariesObjectType)
+	library.Set("Name", name)
+	for _, label := range []string{"A", "", "C"} {
+		shelf := object.New(mock.ShelvesObjectType)
+		if label == "" {
+			shelf.SetNull("Label")
+			book := object.New(mock.BooksObjectType)
+			book.Set("Title", "Lost")
+			shelf.Children[mock.BooksObjectType] = object.NewArray(book)
+		} else {
+			shelf.Set("Label", label)
+		}
+		library.Children[mock.ShelvesObjectType] = append(library.Children[mock.ShelvesObjectType], shelf)
+	}
+	return library
+}
+
+// TestSuiteDiamond exercises the save order of SaveAll using the mock
+// diamond schema. It creates and drops its own tables.
+func TestSuiteDiamond(t *testing.T, db *sql.DB) {
+	sch := mock.DiamondSchema()
+	o := orm.New(getSQLGen(), sch, db)
+
+	{
+		ctx, cancel := getDefaultContext()
+		err := o.CreateTables(ctx)
+		cancel()
+		fatalIf(err)
+	}
+	defer func() {
+		ctx, cancel := getDefaultContext()
+		err := o.DropTablesIfExists(ctx)
+		cancel()
+		fatalIf(err)
+	}()
+
+	t.Run("SaveAllDiamond", func(t *testing.T) {
+		testSaveAllDiamond(&o, t)
+	})
+
+	t.Run("SaveAllCycle", func(t *testing.T) {
+		testSaveAllCycle(t, db)
+	})
+
+	t.Run("AffectedTables", func(t *testing.T) {
+		testAffectedTables(&o, t)
+	})
+
+	t.Run("RetrieveJoinedDiamond", func(t *testing.T) {
+		testRetrieveJoinedDiamond(&o, t)
+	})
+}
+
+// newDiamond returns a new project, task, milestone and deliverable, not yet
+// related to each other.
+func newDiamond(name string) (project, task, milestone, deliverable *object.Object) {
+	project = object.New(mock.ProjectsObjectType)
+	project.Set("Name", name)
+	task = object.New(mock.TasksObjectType)
+	task.Set("Name", name+" task")
+	milestone = object.New(mock.MilestonesObjectType)
+	milestone.Set("Name", name+" milestone")
+	deliverable = object.New(mock.DeliverablesObjectType)
+	deliverable.Set("Name", name+" deliverable")
+	return
+}
+
+// testSaveAllDiamond saves a deliverable shared by a task and a milestone of
+// the same project, nested both from the project down and from the
+// deliverable up.
+func testSaveAllDiamond(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	check := func(project, task, milestone, deliverable *object.Object) {
+		if task.Get("ProjectID") != project.Get("ProjectID") || milestone.Get("ProjectID") != project.Get("ProjectID") {
+			t.Fatal("expected the task and milestone to hold the project's key", task, milestone)
+		}
+		saved, err := o.Retrieve(ctx, mock.DeliverablesObjectType, map[string]interface{}{"DeliverableID": deliverable.Get("DeliverableID")})
+		fatalIf(err)
+		if saved == nil {
+			t.Fatal("expected the deliverable to be saved once")
+		}
+		taskID, _ := saved.GetIntAlways("TaskID")
+		milestoneID, _ := saved.GetIntAlways("MilestoneID")
+		wantTask, _ := task.GetIntAlways("TaskID")
+		wantMilestone, _ := milestone.GetIntAlways("MilestoneID")
+		if taskID != wantTask || milestoneID != wantMilestone {
+			t.Fatal("expected the deliverable to reference its task and milestone, got", saved)
+		}
+	}
+
+	project, task, milestone, deliverable := newDiamond("Down")
+	project.Children[mock.TasksObjectType] = object.NewArray(task)
+	project.Children[mock.MilestonesObjectType] = object.NewArray(milestone)
+	task.Children[mock.DeliverablesObjectType] = object.NewArray(deliverable)
+	milestone.Children[mock.DeliverablesObjectType] = object.NewArray(deliverable)
+	rowsAff, err := o.SaveAll(ctx, project)
+	fatalIf(err)
+	if rowsAff != 4 {
+		t.Fatal("expected four rows to be saved, got", rowsAff)
+	}
+	check(project, task, milestone, deliverable)
+
+	// The parents are saved first even when nested under their children
+	project, task, milestone, deliverable = newDiamond("Up")
+	deliverable.Children[mock.TasksObjectType] = object.NewArray(task)
+	deliverable.Children[mock.MilestonesObjectType] = object.NewArray(milestone)
+	task.Children[mock.ProjectsObjectType] = object.NewArray(project)
+	milestone.Children[mock.ProjectsObjectType] = object.NewArray(project)
+	rowsAff, err = o.SaveAll(ctx, deliverable)
+	fatalIf(err)
+	if rowsAff != 4 {
+		t.Fatal("expected four rows to be saved, got", rowsAff)
+	}
+	check(project, task, milestone, deliverable)
+}
+
+// testRetrieveJoinedDiamond retrieves a project with several tasks and
+// milestones, expecting each child once rather than once per row of the
+// other child table.
+func testRetrieveJoinedDiamond(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	project := object.New(mock.ProjectsObjectType)
+	project.Set("Name", "Joined")
+	for i := 0; i < 2; i++ {
+		task := object.New(mock.TasksObjectType)
+		task.Set("Name", fmt.Sprintf("Joined task %d", i))
+		project.Children[mock.TasksObjectType] = append(project.Children[mock.TasksObjectType], task)
+	}
+	for i := 0; i < 3; i++ {
+		milestone := object.New(mock.MilestonesObjectType)
+		milestone.Set("Name", fmt.Sprintf("Joined milestone %d", i))
+		project.Children[mock.MilestonesObjectType] = append(project.Children[mock.MilestonesObjectType], milestone)
+	}
+	_, err := o.SaveAll(ctx, project)
+	fatalIf(err)
+
+	projects, err := o.RetrieveJoined(ctx, mock.ProjectsObjectType, map[string]interface{}{"Name": "Joined"})
+	fatalIf(err)
+	if len(projects) != 1 {
+		t.Fatal("expected one project, got", len(projects))
+	}
+	if tasks := projects[0].Children[mock.TasksObjectType]; len(tasks) != 2 {
+		t.Fatal("expected two tasks, got", len(tasks))
+	}
+	if milestones := projects[0].Children[mock.MilestonesObjectType]; len(milestones) != 3 {
+		t.Fatal("expected three milestones, got", len(milestones))
+	}
+}
+
+// testSaveAllCycle checks that SaveAll refuses a schema whose tables depend
+// on each other.
+func testSaveAllCycle(t *testing.T, db *sql.DB) {
+	sch := mock.DiamondSchema()
+	sch.Tables[mock.DeliverablesObjectType].Children[mock.ProjectsObjectType] = schema.DefaultChildTable()
+	o := orm.New(getSQLGen(), sch, db)
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	project, task, _, deliverable := newDiamond("Cycle")
+	project.Children[mock.TasksObjectType] = object.NewArray(task)
+	task.Children[mock.DeliverablesObjectType] = object.NewArray(deliverable)
+	_, err := o.SaveAll(ctx, project)
+	if err == nil || !strings.Contains(err.Error(), "cyclic dependency between tables") {
+		t.Fatal("expected a cycle to be reported, got", err)
+	}
+	projects, err := o.RetrieveMany(ctx, mock.ProjectsObjectType, map[string]interface{}{"Name": "Cycle"})
+	fatalIf(err)
+	if len(projects) != 0 {
+		t.Fatal("expected nothing to be saved, got", projects)
+	}
+}
+
+// testAffectedTables lists the tables of a diamond, however it is nested,
+// before and after saving it.
+func testAffectedTables(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+	expected := "[projects milestones tasks deliverables]"
+
+	project, task, milestone, deliverable := newDiamond("Affected")
+	deliverable.Children[mock.TasksObjectType] = object.NewArray(task)
+	deliverable.Children[mock.MilestonesObjectType] = object.NewArray(milestone)
+	task.Children[mock.ProjectsObjectType] = object.NewArray(project)
+	milestone.Children[mock.ProjectsObjectType] = object.NewArray(project)
+	tables, err := o.AffectedTables(deliverable)
+	fatalIf(err)
+	if fmt.Sprint(tables) != expected {
+		t.Fatalf("expected %s, got %v", expected, tables)
+	}
+	if exists, err := o.Exists(ctx, mock.ProjectsObjectType, map[string]interface{}{"Name": "Affected"}); err != nil || exists {
+		t.Fatal("expected AffectedTables not to save anything", err)
+	}
+
+	_, err = o.SaveAll(ctx, deliverable)
+	fatalIf(err)
+	tables, err = o.AffectedTables(deliverable)
+	fatalIf(err)
+	if fmt.Sprint(tables) != expected {
+		t.Fatalf("expected the same tables once saved, got %v", tables)
+	}
+
+	if tables, err := o.AffectedTables(task); err != nil || fmt.Sprint(tables) != "[projects tasks]" {
+		t.Fatal("expected a task under its project to affect both tables, got", tables, err)
+	}
+}
+
+// testQuerySubquery finds the people with an address in a state through an
+// IN subquery on the addresses table.
+func testQuerySubquery(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	var people object.Array
+	for _, state := range []string{"CA", "NV"} {
+		person := object.New(mock.PeopleObjectType)
+		person.Set("Name", "Subquery "+state)
+		addr := mock.SampleAddressObject()
+		addr.Set("State", state)
+		person.Children[mock.AddressesObjectType] = object.NewArray(addr)
+		_, err := o.SaveAll(ctx, person)
+		fatalIf(err)
+		people = append(people, person)
+	}
+
+	inCA := query.New().Select("PersonID").From(mock.AddressesObjectType).Where("State", "=", "CA")
+	q := query.New().From(mock.PeopleObjectType).Where("Name", "LIKE", "Subquery%").And("PersonID", "IN", inCA)
+	objs, err := o.Query(ctx, q)
+	fatalIf(err)
+	if len(objs) != 1 || objs[0].Get("Name") != "Subquery CA" {
+		t.Fatal("expected the person with an address in CA, got", objs)
+	}
+
+	q = query.New().From(mock.PeopleObjectType).Where("Name", "LIKE", "Subquery%").And("PersonID", "NOT IN", inCA)
+	objs, err = o.Query(ctx, q)
+	fatalIf(err)
+	if len(objs) != 1 || objs[0].Get("Name") != "Subquery NV" {
+		t.Fatal("expected the person without an address in CA, got", objs)
+	}
+
+	for _, person := range people {
+		_, err := o.DeleteMany(ctx, nil, mock.AddressesObjectType, map[string]interface{}{"PersonID": person.Get("PersonID")})
+		fatalIf(err)
+		_, err = o.DeleteMany(ctx, nil, mock.PeopleObjectType, map[string]interface{}{"PersonID": person.Get("PersonID")})
+		fatalIf(err)
+	}
+}
+
+// testCodec stores a map as JSON text in the people table's NullText column.
+func testCodec(t *testing.T, sch *schema.Schema, db *sql.DB) {
+	o := orm.New(getSQLGen(), sch, db)
+	o.RegisterCodec(mock.PeopleObjectType, "NullText", orm.JSONCodec{})
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	prefs := map[string]interface{}{"theme": "dark", "tags": []interface{}{"a", "b"}}
+	person := object.New(mock.PeopleObjectType)
+	person.Set("Name", "Codec")
+	person.Set("NullText", prefs)
+	_, err := o.Insert(ctx, nil, person)
+	fatalIf(err)
+	if !reflect.DeepEqual(person.Get("NullText"), prefs) {
+		t.Fatal("expected the object to keep the unencoded value, got", person.Get("NullText"))
+	}
+	queryVals := map[string]interface{}{"PersonID": person.Get("PersonID")}
+
+	// The column holds the JSON text
+	raw, err := orm.New(getSQLGen(), sch, db).Retrieve(ctx, mock.PeopleObjectType, queryVals)
+	fatalIf(err)
+	text, err := raw.GetStringAlways("NullText")
+	fatalIf(err)
+	if text != `{"tags":["a","b"],"theme":"dark"}` {
+		t.Fatal("expected JSON text in the column, got", text)
+	}
+
+	saved, err := o.Retrieve(ctx, mock.PeopleObjectType, queryVals)
+	fatalIf(err)
+	if !reflect.DeepEqual(saved.Get("NullText"), prefs) {
+		t.Fatal("expected the retrieved value to be decoded, got", saved.Get("NullText"))
+	}
+
+	saved.Set("NullText", map[string]interface{}{"theme": "light"})
+	_, err = o.Update(ctx, nil, saved)
+	fatalIf(err)
+	saved, err = o.Retrieve(ctx, mock.PeopleObjectType, queryVals)
+	fatalIf(err)
+	if !reflect.DeepEqual(saved.Get("NullText"), map[string]interface{}{"theme": "light"}) {
+		t.Fatal("expected the updated value to be encoded, got", saved.Get("NullText"))
+	}
+
+	_, err = o.Delete(ctx, nil, saved)
+	fatalIf(err)
+}
+
+func testSaveAllPartial(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	countShelves := func(library *object.Object) int {
+		shelves, err := o.RetrieveMany(ctx, mock.ShelvesObjectType, map[string]interface{}{"LibraryID": library.Get("LibraryID")})
+		fatalIf(err)
+		return len(shelves)
+	}
+
+	// SaveAll gives up on the whole library
+	library := makePartialLibrary("All or nothing")
+	_, err := o.SaveAll(ctx, library)
+	if err == nil {
+		t.Fatal("expected SaveAll to fail for a shelf without a Label")
+	}
+	if strings.Contains(err.Error(), "already been committed or rolled back") {
+		t.Fatal("expected SaveAll to roll back once, got", err)
+	}
+	libs, err := o.RetrieveMany(ctx, mock.LibrariesObjectType, map[string]interface{}{"Name": "All or nothing"})
+	fatalIf(err)
+	if len(libs) != 0 {
+		t.Fatal("expected SaveAll to save nothing, got", libs)
+	}
+
+	library = makePartialLibrary("Partial")
+	res, err := o.SaveAllPartial(ctx, library)
+	fatalIf(err)
+	if res.RowsAffected != 3 {
+		t.Fatal("expected the library and two shelves to be saved, got", res.RowsAffected)
+	}
+	if len(res.Children) != 4 {
+		t.Fatal("expected three shelves and a book in the result, got", res.Children)
+	}
+	bad := library.Children[mock.ShelvesObjectType][1]
+	failed := res.Failed()
+	if len(failed) != 2 || failed[0].Object != bad || failed[0].Err == nil {
+		t.Fatal("expected the shelf without a Label to fail, got", failed)
+	}
+	if failed[1].Object != bad.Children[mock.BooksObjectType][0] || failed[1].Err != orm.ErrParentNotSaved {
+		t.Fatal("expected the book on the failed shelf to be skipped, got", failed[1])
+	}
+	if n := countShelves(library); n != 2 {
+		t.Fatal("expected the two good shelves to be committed, got", n)
+	}
+	books, err := o.RetrieveMany(ctx, mock.BooksObjectType, map[string]interface{}{"Title": "Lost"})
+	fatalIf(err)
+	if len(books) != 0 {
+		t.Fatal("expected the book on the failed shelf not to be saved, got", books)
+	}
+}
+
+// testFleshenCycle makes the library's members refer each other, so that
+// following the referrals would otherwise loop forever.
+func testFleshenCycle(o *orm.ORM, t *testing.T, library *object.Object) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	ada, err := o.Retrieve(ctx, mock.MembersObjectType, map[string]interface{}{"Name": "Ada"})
+	fatalIf(err)
+	if ada == nil {
+		t.Fatal("expected to retrieve Ada")
+	}
+	bob := object.New(mock.MembersObjectType)
+	bob.Set("LibraryID", library.Get("LibraryID"))
+	bob.Set("Name", "Bob")
+	bob.Set("ReferrerID", ada.Get("MemberID"))
+	_, err = o.Insert(ctx, nil, bob)
+	fatalIf(err)
+	ada.Set("ReferrerID", bob.Get("MemberID"))
+	_, err = o.Save(ctx, nil, ada)
+	fatalIf(err)
+
+	// Ada referred Bob, who referred Ada
+	obj, err := o.FleshenChildrenDepth(ctx, ada, 100)
+	fatalIf(err)
+	referred := obj.Children[mock.MembersObjectType]
+	if len(referred) != 1 || referred[0].Get("Name") != "Bob" {
+		t.Fatal("expected Ada to have referred Bob, got", referred)
+	}
+	if back := referred[0].Children[mock.MembersObjectType]; len(back) != 1 || back[0] != ada {
+		t.Fatal("expected Bob's referral to be the Ada already loaded, got", back)
+	}
+
+	root := library.CloneWithoutState()
+	fatalIf(o.FleshenGraph(ctx, root))
+	var gAda, gBob *object.Object
+	for _, m := range root.Children[mock.MembersObjectType] {
+		switch m.Get("Name") {
+		case "Ada":
+			gAda = m
+		case "Bob":
+			gBob = m
+		}
+	}
+	if gAda == nil || gBob == nil {
+		t.Fatal("expected the library to have Ada and Bob, got", root.Children[mock.MembersObjectType])
+	}
+	if p := gAda.Parents[mock.LibrariesObjectType]; len(p) != 1 || p[0] != root {
+		t.Fatal("expected Ada's library to be the root object, got", p)
+	}
+	if c := gAda.Children[mock.MembersObjectType]; len(c) != 1 || c[0] != gBob {
+		t.Fatal("expected Ada's referral to be the Bob reached through the library, got", c)
+	}
+	if p := gBob.Parents[mock.MembersObjectType]; len(p) != 1 || p[0] != gAda {
+		t.Fatal("expected Bob's referrer to be Ada, got", p)
+	}
+	shelves := root.Children[mock.ShelvesObjectType]
+	if len(shelves) != 1 || len(shelves[0].Children[mock.BooksObjectType]) != 1 {
+		t.Fatal("expected the shelf and its book to be loaded, got", shelves)
+	}
+	if p := shelves[0].Parents[mock.LibrariesObjectType]; len(p) != 1 || p[0] != root {
+		t.Fatal("expected the shelf's library to be the root object, got", p)
+	}
+}
+
+func testFleshenChildrenDepth(o *orm.ORM, t *testing.T, library *object.Object) {
+	ctx, cancel := getDefaultContext()
+	obj, err := o.FleshenChildrenDepth(ctx, library.CloneWithoutState(), 1)
+	cancel()
+	fatalIf(err)
+	shelves := obj.Children[mock.ShelvesObjectType]
+	if len(shelves) != 1 {
+		t.Fatal("expected a single shelf, got", shelves)
+	}
+	if len(shelves[0].Children) != 0 {
+		t.Fatal("expected depth 1 to stop at the shelves, got", shelves[0].Children)
+	}
+
+	ctx, cancel = getDefaultContext()
+	obj, err = o.FleshenChildrenDepth(ctx, library.CloneWithoutState(), 2)
+	cancel()
+	fatalIf(err)
+	books := obj.Children[mock.ShelvesObjectType][0].Children[mock.BooksObjectType]
+	if len(books) != 1 {
+		t.Fatal("expected depth 2 to load the book, got", books)
+	}
+	title, err := books[0].GetStringAlways("Title")
+	fatalIf(err)
+	if title != "Dune" {
+		t.Fatal("unexpected book", title)
+	}
+}
+
+// TestSuiteWidgets exercises column constraints using the mock widgets
+// schema. It creates and drops its own tables.
+func TestSuiteWidgets(t *testing.T, db *sql.DB) {
+	sch := mock.WidgetSchema()
+	o := orm.New(getSQLGen(), sch, db)
+
+	{
+		ctx, cancel := getDefaultContext()
+		err := o.CreateTables(ctx)
+		cancel()
+		fatalIf(err)
+	}
+	defer func() {
+		ctx, cancel := getDefaultContext()
+		err := o.DropTables(ctx)
+		cancel()
+		fatalIf(err)
+	}()
+
+	t.Run("CheckConstraint", func(t *testing.T) {
+		testCheckConstraint(&o, t)
+	})
+
+	t.Run("DefaultValue", func(t *testing.T) {
+		testDefaultValue(&o, t)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		testValidate(&o, t)
+	})
+
+	t.Run("LengthPolicy", func(t *testing.T) {
+		testLengthPolicy(&o, t)
+	})
+
+	t.Run("AddDropColumn", func(t *testing.T) {
+		testAddDropColumn(&o, t)
+	})
+
+	t.Run("Timestamp", func(t *testing.T) {
+		testTimestamp(&o, t)
+	})
+
+	t.Run("Boolean", func(t *testing.T) {
+		testBoolean(&o, t)
+	})
+
+	t.Run("Decimal", func(t *testing.T) {
+		testDecimal(&o, t)
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		testRefresh(&o, t)
+	})
+
+	t.Run("UpdateFields", func(t *testing.T) {
+		testUpdateFields(&o, t)
+	})
+
+	t.Run("RawQuery", func(t *testing.T) {
+		testRawQuery(&o, t)
+	})
+
+	t.Run("DeleteMany", func(t *testing.T) {
+		testDeleteMany(&o, t)
+	})
+
+	t.Run("GeneratedColumn", func(t *testing.T) {
+		testGeneratedColumn(&o, t)
+	})
+
+	t.Run("VerifySchema", func(t *testing.T) {
+		testVerifySchema(t, sch, db)
+	})
+
+	t.Run("Truncate", func(t *testing.T) {
+		testTruncate(&o, t)
+	})
+
+	t.Run("ContextCancel", func(t *testing.T) {
+		testContextCancel(t, sch, db)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		testTimeout(&o, t)
+	})
+
+	t.Run("QuoteLiteral", func(t *testing.T) {
+		testQuoteLiteral(&o, t)
+	})
+
+	t.Run("RunInTx", func(t *testing.T) {
+		testRunInTx(&o, t)
+	})
+
+	t.Run("TransactRollbackError", func(t *testing.T) {
+		testTransactRollbackError(&o, t)
+	})
+
+	t.Run("TransactWithRetry", func(t *testing.T) {
+		testTransactWithRetry(t, db)
+	})
+
+	t.Run("SetNull", func(t *testing.T) {
+		testSetNull(&o, t)
+	})
+
+	t.Run("SQLValueColumns", func(t *testing.T) {
+		testSQLValueColumns(&o, t)
+	})
+
+	t.Run("EssentialColumns", func(t *testing.T) {
+		testEssentialColumns(&o, t)
+	})
+
+	t.Run("SaveResult", func(t *testing.T) {
+		testSaveResult(&o, t)
+	})
+
+	t.Run("BulkInsert", func(t *testing.T) {
+		testBulkInsert(&o, t)
+	})
+
+	t.Run("BulkUpdate", func(t *testing.T) {
+		testBulkUpdate(&o, t)
+	})
+
+	t.Run("ReadReplicas", func(t *testing.T) {
+		testReadReplicas(t, sch, db)
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		testMetrics(o, t)
+	})
+
+	t.Run("Tracing", func(t *testing.T) {
+		testTracing(o, t)
+	})
+
+	t.Run("UUIDPrimaryKey", func(t *testing.T) {
+		testUUIDPrimaryKey(&o, t)
+	})
+
+	t.Run("CompositePrimaryKey", func(t *testing.T) {
+		testCompositePrimaryKey(&o, t)
+	})
+
+	t.Run("CompositeForeignKey", func(t *testing.T) {
+		testCompositeForeignKey(&o, t)
+	})
+
+	t.Run("Aliases", func(t *testing.T) {
+		testAliases(t, db)
+	})
+
+	t.Run("InsertSetsPrimaryKey", func(t *testing.T) {
+		testInsertSetsPrimaryKey(&o, t)
+	})
+
+	t.Run("InsertWithResult", func(t *testing.T) {
+		testInsertWithResult(&o, t)
+	})
+
+	t.Run("Explain", func(t *testing.T) {
+		testExplain(&o, t)
+	})
+
+	t.Run("TxORM", func(t *testing.T) {
+		testTxORM(&o, t)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		testQuery(&o, t)
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+		testAggregate(&o, t)
+	})
+}
+
+// testAggregate relies on the parts inserted by testCompositePrimaryKey and
+// testQuery: part 7 has a single revision, part 20 has five.
+func testAggregate(o *orm.ORM, t *testing.T) {
+	aggs := []orm.Agg{query.Count("*"), query.Max("Revision").As("latest")}
+	ctx, cancel := getDefaultContext()
+	groups, err := o.Aggregate(ctx, mock.PartsObjectType, []string{"PartNo"}, aggs)
+	cancel()
+	fatalIf(err)
+	if len(groups) != 2 {
+		t.Fatal("expected two groups, got", groups)
+	}
+
+	having := orm.Having{Aggregate: query.Count("*"), Operator: ">", Value: 1}
+	ctx, cancel = getDefaultContext()
+	groups, err = o.Aggregate(ctx, mock.PartsObjectType, []string{"PartNo"}, aggs, having)
+	cancel()
+	fatalIf(err)
+	if len(groups) != 1 {
+		t.Fatal("expected a single group with more than one revision, got", groups)
+	}
+	partNo, err := groups[0].GetIntAlways("PartNo")
+	fatalIf(err)
+	count, err := groups[0].GetIntAlways("count")
+	fatalIf(err)
+	latest, err := groups[0].GetIntAlways("latest")
+	fatalIf(err)
+	if partNo != 20 || count != 5 || latest != 5 {
+		t.Fatal("unexpected aggregate results", groups[0].KV)
+	}
+}
+
+// testInsertSetsPrimaryKey checks that every dialect sets the generated
+// identity on the object after an insert, both inside and outside of a
+// transaction.
+func testInsertSetsPrimaryKey(o *orm.ORM, t *testing.T) {
+	check := func(widget *object.Object) {
+		id, err := widget.GetIntAlways("WidgetID")
+		fatalIf(err)
+		if id == 0 {
+			t.Fatal("expected a non-zero WidgetID after insert")
+		}
+
+		ctx, cancel := getDefaultContext()
+		found, err := o.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": id})
+		cancel()
+		fatalIf(err)
+		if !found {
+			t.Fatal("expected WidgetID to identify the inserted row", id)
+		}
+
+		ctx, cancel = getDefaultContext()
+		_, err = o.Delete(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 60+i)
+		ctx, cancel := getDefaultContext()
+		_, err := o.Insert(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+		check(widget)
+	}
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 62)
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+	tx, err := o.Begin(ctx)
+	fatalIf(err)
+	_, err = tx.Insert(ctx, widget)
+	if err != nil {
+		fatalIf(tx.Rollback())
+		t.Fatal(err)
+	}
+	fatalIf(tx.Commit())
+	check(widget)
+}
+
+func testInsertWithResult(o *orm.ORM, t *testing.T) {
+	insert := func(obj *object.Object) orm.InsertResult {
+		ctx, cancel := getDefaultContext()
+		res, err := o.InsertWithResult(ctx, nil, obj)
+		cancel()
+		fatalIf(err)
+		if res.RowsAffected != 1 {
+			t.Fatal("expected a single row affected, got", res.RowsAffected)
+		}
+		if res.PrimaryKey == nil || res.PrimaryKey != obj.Get(o.GetSchema().GetTable(obj.Type).Primary) {
+			t.Fatal("expected the result to report the object's primary key, got", res.PrimaryKey)
+		}
+		return res
+	}
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 3)
+	res := insert(widget)
+	if res.KeySource != orm.KeyFromLastInsertID && res.KeySource != orm.KeyFromReturning && res.KeySource != orm.KeyFromOutput {
+		t.Fatal("expected a generated identity key, got", res.KeySource)
+	}
+
+	gadget := object.New(mock.GadgetsObjectType)
+	gadget.Set("Name", "result")
+	if res = insert(gadget); res.KeySource != orm.KeyFromUUID {
+		t.Fatal("expected a UUID key, got", res.KeySource)
+	}
+
+	part := object.New(mock.PartsObjectType)
+	part.Set("PartNo", int64(30))
+	part.Set("Revision", int64(1))
+	part.Set("Description", "result")
+	if res = insert(part); res.KeySource != orm.KeyFromCaller || res.PrimaryKey != int64(30) {
+		t.Fatal("expected the caller supplied key, got", res)
+	}
+
+	// Later tests count the parts by PartNo
+	ctx, cancel := getDefaultContext()
+	_, err := o.Delete(ctx, nil, part)
+	cancel()
+	fatalIf(err)
+}
+
+func testExplain(o *orm.ORM, t *testing.T) {
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 42)
+	sqlStr, bindArgs, err := o.ExplainSave(widget)
+	fatalIf(err)
+	if !strings.HasPrefix(sqlStr, "INSERT INTO widgets") || len(bindArgs) != 1 {
+		t.Fatal("unexpected insert explanation", sqlStr, bindArgs)
+	}
+	if widget.Get("WidgetID") != nil || !widget.IsDirty() {
+		t.Fatal("ExplainSave must not modify the object")
+	}
+
+	ctx, cancel := getDefaultContext()
+	found, err := o.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 42})
+	cancel()
+	fatalIf(err)
+	if found {
+		t.Fatal("ExplainSave must not insert the object")
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+
+	widget.Set("Age", 43)
+	sqlStr, bindArgs, err = o.ExplainSave(widget)
+	fatalIf(err)
+	if !strings.HasPrefix(sqlStr, "UPDATE widgets") || len(bindArgs) != 2 {
+		t.Fatal("unexpected update explanation", sqlStr, bindArgs)
+	}
+
+	sqlStr, bindArgs, err = o.ExplainDelete(widget)
+	fatalIf(err)
+	if !strings.HasPrefix(sqlStr, "DELETE FROM widgets") || len(bindArgs) == 0 {
+		t.Fatal("unexpected delete explanation", sqlStr, bindArgs)
+	}
+
+	sqlStr, bindArgs, err = o.ExplainRetrieve(mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	fatalIf(err)
+	if !strings.HasPrefix(sqlStr, "SELECT ") || len(bindArgs) != 1 {
+		t.Fatal("unexpected retrieve explanation", sqlStr, bindArgs)
+	}
+
+	ctx, cancel = getDefaultContext()
+	stored, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	cancel()
+	fatalIf(err)
+	if stored == nil || stored.Get("Age") != int64(42) {
+		t.Fatal("expected the stored widget to be untouched by the explanations", stored)
+	}
+}
+
+func testTxORM(o *orm.ORM, t *testing.T) {
+	for _, commit := range []bool{false, true} {
+		ctx, cancel := getDefaultContext()
+		tx, err := o.Begin(ctx)
+		fatalIf(err)
+
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 77)
+		_, err = tx.Save(ctx, widget)
+		fatalIf(err)
+
+		found, err := tx.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+		fatalIf(err)
+		if !found {
+			t.Fatal("expected the widget to be visible inside the transaction")
+		}
+
+		if commit {
+			fatalIf(tx.Commit())
+		} else {
+			fatalIf(tx.Rollback())
+		}
+		widget.Set("Age", 78)
+		if _, err = tx.Save(ctx, widget); err == nil {
+			t.Fatal("expected an error using a finished transaction")
+		}
+
+		found, err = o.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+		cancel()
+		fatalIf(err)
+		if found != commit {
+			t.Fatal("expected the widget to exist only after a commit, commit =", commit)
+		}
+	}
+}
+
+func testQuery(o *orm.ORM, t *testing.T) {
+	for rev := int64(1); rev <= 5; rev++ {
+		obj := object.New(mock.PartsObjectType)
+		obj.Set("PartNo", int64(20))
+		obj.Set("Revision", rev)
+		obj.Set("Description", fmt.Sprintf("query revision %d", rev))
+		ctx, cancel := getDefaultContext()
+		_, err := o.Insert(ctx, nil, obj)
+		cancel()
+		fatalIf(err)
+	}
+
+	q := query.New().From(mock.PartsObjectType).Where("PartNo", "=", int64(20)).And("Revision", ">", int64(1)).OrderByDesc("Revision").Limit(2).Offset(1)
+	ctx, cancel := getDefaultContext()
+	objs, err := o.Query(ctx, q)
+	cancel()
+	fatalIf(err)
+	if len(objs) != 2 || objs[0].Get("Revision") != int64(4) || objs[1].Get("Revision") != int64(3) {
+		t.Fatal("expected revisions 4 and 3", objs)
+	}
+	if objs[0].IsDirty() {
+		t.Fatal("expected queried objects to be clean")
+	}
+
+	q = query.New().From(mock.PartsObjectType).Where("Description", "=", "query revision 1").Or("Description", "LIKE", "%revision 5").OrderBy("Revision")
+	ctx, cancel = getDefaultContext()
+	objs, err = o.Query(ctx, q)
+	cancel()
+	fatalIf(err)
+	if len(objs) != 2 || objs[0].Get("Revision") != int64(1) || objs[1].Get("Revision") != int64(5) {
+		t.Fatal("expected revisions 1 and 5", objs)
+	}
+
+	q = query.New().From(mock.PartsObjectType).Where("NoSuchColumn", "=", 1)
+	ctx, cancel = getDefaultContext()
+	_, err = o.Query(ctx, q)
+	cancel()
+	if err == nil {
+		t.Fatal("expected an error for an unknown column")
+	}
+}
+
+func testCompositePrimaryKey(o *orm.ORM, t *testing.T) {
+	for rev := int64(1); rev <= 2; rev++ {
+		obj := object.New(mock.PartsObjectType)
+		obj.Set("PartNo", int64(7))
+		obj.Set("Revision", rev)
+		obj.Set("Description", fmt.Sprintf("revision %d", rev))
+		ctx, cancel := getDefaultContext()
+		_, err := o.Insert(ctx, nil, obj)
+		cancel()
+		fatalIf(err)
+	}
+
+	key := map[string]interface{}{"PartNo": int64(7), "Revision": int64(2)}
+	ctx, cancel := getDefaultContext()
+	part, err := o.Retrieve(ctx, mock.PartsObjectType, key)
+	cancel()
+	fatalIf(err)
+	if part == nil {
+		t.Fatal("expected to retrieve part 7 revision 2")
+	}
+	desc, err := part.GetStringAlways("Description")
+	fatalIf(err)
+	if desc != "revision 2" {
+		t.Fatal("retrieved the wrong part", desc)
+	}
+
+	// Deleting the retrieved object should only use the composite key
+	ctx, cancel = getDefaultContext()
+	rowsAff, err := o.Delete(ctx, nil, part)
+	cancel()
+	fatalIf(err)
+	if rowsAff != 1 {
+		t.Fatal("expected a single row deleted, got", rowsAff)
+	}
+
+	ctx, cancel = getDefaultContext()
+	remaining, err := o.RetrieveMany(ctx, mock.PartsObjectType, map[string]interface{}{"PartNo": int64(7)})
+	cancel()
+	fatalIf(err)
+	if len(remaining) != 1 || remaining[0].Get("Revision") != int64(1) {
+		t.Fatal("expected only revision 1 to remain", remaining)
+	}
+}
+
+// testCompositeForeignKey fleshens partdocs, which reference parts by both
+// columns of their primary key, in both directions.
+func testCompositeForeignKey(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	// Two revisions of the same part, so that matching on PartNo alone
+	// would mix up their documents
+	var parts object.Array
+	for rev := int64(1); rev <= 2; rev++ {
+		part := object.New(mock.PartsObjectType)
+		part.Set("PartNo", int64(50))
+		part.Set("Revision", rev)
+		part.Set("Description", fmt.Sprintf("documented %d", rev))
+		_, err := o.Insert(ctx, nil, part)
+		fatalIf(err)
+		parts = append(parts, part)
+	}
+	for title, rev := range map[string]int64{"drawing r1": 1, "drawing r2": 2, "manual r2": 2} {
+		doc := object.New(mock.PartDocsObjectType)
+		doc.Set("PartNumber", int64(50))
+		doc.Set("PartRevision", rev)
+		doc.Set("Title", title)
+		_, err := o.Insert(ctx, nil, doc)
+		fatalIf(err)
+	}
+
+	_, err := o.FleshenChildren(ctx, parts[1])
+	fatalIf(err)
+	docs := parts[1].Children[mock.PartDocsObjectType]
+	if len(docs) != 2 {
+		t.Fatal("expected the two documents of revision 2, got", docs)
+	}
+	for _, doc := range docs {
+		if doc.Get("PartRevision") != int64(2) {
+			t.Fatal("expected only documents of revision 2, got", doc)
+		}
+	}
+
+	// A part without a revision has no documents, rather than those with
+	// a NULL one
+	unrevised := object.New(mock.PartsObjectType)
+	unrevised.Set("PartNo", int64(50))
+	_, err = o.FleshenChildren(ctx, unrevised)
+	fatalIf(err)
+	if children, ok := unrevised.Children[mock.PartDocsObjectType]; !ok || len(children) != 0 {
+		t.Fatal("expected no documents for a part without a revision, got", children)
+	}
+
+	fatalIf(o.FleshenChildrenBulk(ctx, parts))
+	if n := len(parts[0].Children[mock.PartDocsObjectType]); n != 1 {
+		t.Fatal("expected FleshenChildrenBulk to find the one document of revision 1, got", n)
+	}
+
+	parents, err := o.GetParentsViaChild(ctx, docs[0])
+	fatalIf(err)
+	if len(parents) != 1 || parents[0].Get("Revision") != int64(2) {
+		t.Fatal("expected revision 2 as the only parent, got", parents)
+	}
+
+	// SaveAllPartial keys the documents by both columns, and keeps the
+	// good one when the other fails
+	part := object.New(mock.PartsObjectType)
+	part.Set("PartNo", int64(50))
+	part.Set("Revision", int64(3))
+	part.Set("Description", "documented 3")
+	good := object.New(mock.PartDocsObjectType)
+	good.Set("Title", "drawing r3")
+	untitled := object.New(mock.PartDocsObjectType)
+	part.Children[mock.PartDocsObjectType] = object.Array{good, untitled}
+	res, err := o.SaveAllPartial(ctx, part)
+	fatalIf(err)
+	if failed := res.Failed(); len(failed) != 1 || failed[0].Object != untitled {
+		t.Fatal("expected only the document without a Title to fail, got", failed)
+	}
+	docs, err = o.RetrieveMany(ctx, mock.PartDocsObjectType, map[string]interface{}{"PartNumber": int64(50), "PartRevision": int64(3)})
+	fatalIf(err)
+	if len(docs) != 1 || docs[0].Get("Title") != "drawing r3" {
+		t.Fatal("expected the good document to be saved under revision 3, got", docs)
+	}
+	parts = append(parts, part)
+
+	_, err = o.DeleteMany(ctx, nil, mock.PartDocsObjectType, map[string]interface{}{"PartNumber": int64(50)})
+	fatalIf(err)
+	for _, part := range parts {
+		_, err := o.Delete(ctx, nil, part)
+		fatalIf(err)
+	}
+}
+
+func testUUIDPrimaryKey(o *orm.ORM, t *testing.T) {
+	obj := object.New(mock.GadgetsObjectType)
+	obj.Set("Name", "sprocket")
+	ctx, cancel := getDefaultContext()
+	rowsAff, err := o.Save(ctx, nil, obj)
+	cancel()
+	fatalIf(err)
+	if rowsAff != 1 {
+		t.Fatal("expected a single row inserted, got", rowsAff)
+	}
+
+	id, ok := obj.Get("GadgetID").(string)
+	if !ok || len(id) != 36 {
+		t.Fatalf("expected a generated UUID for GadgetID, got %v", obj.Get("GadgetID"))
+	}
+
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, mock.GadgetsObjectType, map[string]interface{}{
+		"GadgetID": id,
+	})
+	cancel()
+	fatalIf(err)
+	if retObj == nil {
+		t.Fatal("expected to retrieve the gadget by its UUID")
+	}
+	name, err := retObj.GetStringAlways("Name")
+	fatalIf(err)
+	if name != "sprocket" {
+		t.Fatal("retrieved gadget has the wrong Name", name)
+	}
+}
+
+// testAliases saves and retrieves gadgets through a table alias and a column
+// alias, expecting the SQL to use the real names and the retrieved object to
+// use the aliases.
+func testAliases(t *testing.T, db *sql.DB) {
+	sch := mock.WidgetSchema()
+	sch.TableAliases = map[string]string{"gizmos": mock.GadgetsObjectType}
+	sch.Tables[mock.GadgetsObjectType].ColumnAliases = map[string]string{"Title": "Name"}
+	o := orm.New(getSQLGen(), sch, db)
+
+	obj := object.New("gizmos")
+	obj.Set("Title", "flywheel")
+	ctx, cancel := getDefaultContext()
+	_, err := o.Save(ctx, nil, obj)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, "gizmos", map[string]interface{}{"Title": "flywheel"})
+	cancel()
+	fatalIf(err)
+	if retObj == nil {
+		t.Fatal("expected to retrieve the gizmo by its aliased column")
+	}
+	if _, ok := retObj.KV["Name"]; ok {
+		t.Fatal("expected the real column name to be mapped back to its alias", retObj.KV)
+	}
+	title, err := retObj.GetStringAlways("Title")
+	fatalIf(err)
+	id, err := retObj.GetStringAlways("GadgetID")
+	fatalIf(err)
+	if title != "flywheel" || id != obj.Get("GadgetID") {
+		t.Fatal("unexpected gizmo", retObj.KV)
+	}
+
+	ctx, cancel = getDefaultContext()
+	queried, err := o.Query(ctx, query.New().From("gizmos").Where("Title", "=", "flywheel"))
+	cancel()
+	fatalIf(err)
+	if len(queried) != 1 || queried[0].Get("Title") != "flywheel" {
+		t.Fatal("expected Query to key the gizmo by its aliased column", queried)
+	}
+
+	ctx, cancel = getDefaultContext()
+	joined, err := o.RetrieveJoined(ctx, "gizmos", map[string]interface{}{"Title": "flywheel"})
+	cancel()
+	fatalIf(err)
+	if len(joined) != 1 || joined[0].Get("Title") != "flywheel" {
+		t.Fatal("expected RetrieveJoined to key the gizmo by its aliased column", joined)
+	}
+
+	retObj.Set("Title", "flywheel2")
+	ctx, cancel = getDefaultContext()
+	rowsAff, err := o.Save(ctx, nil, retObj)
+	cancel()
+	fatalIf(err)
+	if rowsAff != 1 {
+		t.Fatal("expected the aliased update to affect one row, got", rowsAff)
+	}
+
+	ctx, cancel = getDefaultContext()
+	gadget, err := o.Retrieve(ctx, mock.GadgetsObjectType, map[string]interface{}{"GadgetID": id})
+	cancel()
+	fatalIf(err)
+	if gadget == nil {
+		t.Fatal("expected to retrieve the gizmo through the real table name")
+	}
+	title, err = gadget.GetStringAlways("Title")
+	fatalIf(err)
+	if title != "flywheel2" {
+		t.Fatal("expected the aliased update to be saved, got", title)
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, gadget)
+	cancel()
+	fatalIf(err)
+}
+
+func testBoolean(o *orm.ORM, t *testing.T) {
+	retrieveActive := func(id interface{}) bool {
+		ctx, cancel := getDefaultContext()
+		retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": id})
+		cancel()
+		fatalIf(err)
+		if retObj == nil {
+			t.Fatal("expected to retrieve widget", id)
+		}
+		active, ok := retObj.GetBool("Active")
+		if !ok {
+			t.Fatalf("expected Active to be retrieved as a bool, got %T", retObj.Get("Active"))
+		}
+		return active
+	}
+
+	for _, active := range []bool{true, false} {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 70)
+		widget.Set("Active", active)
+		ctx, cancel := getDefaultContext()
+		_, err := o.Insert(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+
+		if got := retrieveActive(widget.Get("WidgetID")); got != active {
+			t.Fatal("expected Active to round trip as", active, "got", got)
+		}
+
+		// Flip it with an update
+		widget.Set("Active", !active)
+		ctx, cancel = getDefaultContext()
+		_, err = o.Update(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+		if got := retrieveActive(widget.Get("WidgetID")); got != !active {
+			t.Fatal("expected the updated Active to be", !active, "got", got)
+		}
+
+		ctx, cancel = getDefaultContext()
+		_, err = o.Delete(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+	}
+}
+
+func testDecimal(o *orm.ORM, t *testing.T) {
+	retrievePrice := func(id interface{}) string {
+		ctx, cancel := getDefaultContext()
+		retObj, err := o.RetrieveColumns(ctx, mock.WidgetsObjectType, []string{"WidgetID", "Price"}, map[string]interface{}{"WidgetID": id})
+		cancel()
+		fatalIf(err)
+		if retObj == nil {
+			t.Fatal("expected to retrieve widget", id)
+		}
+		price, ok := retObj.Get("Price").(string)
+		if !ok {
+			t.Fatalf("expected Price to be retrieved as a string, got %T", retObj.Get("Price"))
+		}
+		return price
+	}
+
+	for _, tc := range []struct {
+		price    interface{}
+		expected string
+	}{
+		{19.99, "19.99"},
+		{big.NewRat(1999, 100), "19.99"},
+		{"0.1", "0.10"},
+	} {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 71)
+		widget.Set("Price", tc.price)
+		ctx, cancel := getDefaultContext()
+		_, err := o.Insert(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+
+		if got := retrievePrice(widget.Get("WidgetID")); got != tc.expected {
+			t.Fatalf("expected Price %v to round trip as %s, got %s", tc.price, tc.expected, got)
+		}
+
+		widget.Set("Price", 1234.5)
+		ctx, cancel = getDefaultContext()
+		_, err = o.Update(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+		if got := retrievePrice(widget.Get("WidgetID")); got != "1234.50" {
+			t.Fatal("expected the updated Price to be 1234.50, got", got)
+		}
+
+		ctx, cancel = getDefaultContext()
+		_, err = o.Delete(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+	}
+}
+
+func testORMClose(t *testing.T) {
+	db := GetDB()
+	o := orm.New(getSQLGen(), mock.NestedSchema(), db)
+	o.KeepDBOpen = true
+	fatalIf(o.Close())
+	ctx, cancel := getDefaultContext()
+	err := db.PingContext(ctx)
+	cancel()
+	fatalIf(err)
+
+	reader := GetDB()
+	o = orm.NewWithReaders(getSQLGen(), mock.NestedSchema(), db, reader, db)
+	fatalIf(o.Close())
+	for _, closed := range []*sql.DB{db, reader} {
+		ctx, cancel := getDefaultContext()
+		err := closed.PingContext(ctx)
+		cancel()
+		if err == nil {
+			t.Fatal("expected Close to close the writer and the readers")
+		}
+	}
+}
+
+// testExportDDL runs the statements written by ExportDDL by hand, and
+// checks that they created the schema's tables.
+func testExportDDL(t *testing.T, db *sql.DB) {
+	sch := mock.LibrarySchema()
+	sch.TablePrefix = "ddl_"
+	o := orm.New(getSQLGen(), sch, db)
+
+	var buf bytes.Buffer
+	fatalIf(o.ExportDDL(&buf))
+	stmts, err := o.GetSQLGenerator().RenderSchemaDDL(sch)
+	fatalIf(err)
+	if got := strings.Count(buf.String(), ";\n\n"); got != len(stmts) {
+		t.Fatal("expected every statement of RenderSchemaDDL to be exported, got", buf.String())
+	}
+
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+	for _, sqlStr := range strings.Split(strings.TrimSpace(buf.String()), ";\n\n") {
+		_, err := db.ExecContext(ctx, strings.TrimSuffix(sqlStr, ";"))
+		fatalIf(err)
+	}
+	defer func() {
+		fatalIf(o.DropTables(ctx))
+	}()
+	for table := range sch.Tables {
+		exists, err := o.TableExists(ctx, table)
+		fatalIf(err)
+		if !exists {
+			t.Fatal("expected the exported DDL to create table", table)
+		}
+	}
+}
+
+func testTablePrefix(t *testing.T, db *sql.DB) {
+	sch := mock.WidgetSchema()
+	sch.TablePrefix = "pfx_"
+	o := orm.New(getSQLGen(), sch, db)
+
+	ctx, cancel := getDefaultContext()
+	err := o.CreateTables(ctx)
+	cancel()
+	fatalIf(err)
+	defer func() {
+		ctx, cancel := getDefaultContext()
+		defer cancel()
+		fatalIf(o.DropTables(ctx))
+	}()
+
+	ctx, cancel = getDefaultContext()
+	exists, err := o.TableExists(ctx, mock.WidgetsObjectType)
+	cancel()
+	fatalIf(err)
+	if !exists {
+		t.Fatal("expected the prefixed widgets table to exist")
+	}
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 5)
+	ctx, cancel = getDefaultContext()
+	_, err = o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	objs, err := o.RetrieveMany(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 5})
+	cancel()
+	fatalIf(err)
+	if len(objs) != 1 {
+		t.Fatal("expected the widget in the prefixed table, got", objs)
+	}
+}
+
+func testConfigurePool(t *testing.T) {
+	db := GetDB()
+	defer func() {
+		fatalIf(db.Close())
+	}()
+
+	orm.ConfigurePool(db, orm.PoolConfig{MaxOpenConns: 3, ConnMaxLifetime: time.Minute})
+	if n := db.Stats().MaxOpenConnections; n != 3 {
+		t.Fatal("expected MaxOpenConnections to be 3, got", n)
+	}
+	// Zero fields leave the settings alone
+	orm.ConfigurePool(db, orm.PoolConfig{MaxIdleConns: 1})
+	if n := db.Stats().MaxOpenConnections; n != 3 {
+		t.Fatal("expected MaxOpenConnections to be left at 3, got", n)
+	}
+
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+	fatalIf(db.PingContext(ctx))
+}
+
+func testReadReplicas(t *testing.T, sch *schema.Schema, db *sql.DB) {
+	// A closed reader makes every read that is routed to it fail.
+	closed := GetDB()
+	fatalIf(closed.Close())
+	o := orm.NewWithReaders(getSQLGen(), sch, db, db, closed)
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 75)
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+	queryVals := map[string]interface{}{"WidgetID": widget.Get("WidgetID")}
+
+	// Reads alternate between the readers
+	var errs int
+	for i := 0; i < 4; i++ {
+		ctx, cancel = getDefaultContext()
+		_, err = o.Retrieve(ctx, mock.WidgetsObjectType, queryVals)
+		cancel()
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 2 {
+		t.Fatal("expected half of the reads to go to the closed reader, got", errs, "errors")
+	}
+
+	// Transactions always use the writer, and don't take a reader's turn
+	ctx, cancel = getDefaultContext()
+	defer cancel()
+	tx, err := o.Begin(ctx)
+	fatalIf(err)
+	for i := 0; i < 3; i++ {
+		retObj, err := tx.Retrieve(ctx, mock.WidgetsObjectType, queryVals)
+		fatalIf(err)
+		if retObj == nil {
+			t.Fatal("expected to retrieve the widget inside a transaction")
+		}
+	}
+	fatalIf(tx.Commit())
+	_, err = o.Retrieve(ctx, mock.WidgetsObjectType, queryVals)
+	if err != nil {
+		t.Fatal("expected the transaction's reads to leave the readers' turns alone, got", err)
+	}
+
+	// Reads which decide a write, or must see one, use the writer
+	wo := orm.NewWithReaders(getSQLGen(), sch, db, closed)
+	fatalIf(wo.Refresh(ctx, widget))
+	_, retObj, err := wo.FindOrCreate(ctx, widget)
+	fatalIf(err)
+	if retObj == nil || retObj.Get("WidgetID") == nil {
+		t.Fatal("expected FindOrCreate to find the widget on the writer")
+	}
+	_, _, err = wo.FindOrCreateKV(ctx, mock.WidgetsObjectType, queryVals, widget.KV)
+	fatalIf(err)
+	updateKV := map[string]interface{}{"WidgetID": widget.Get("WidgetID"), "Age": 93}
+	numRows, _, err := wo.CreateOrUpdateKV(ctx, mock.WidgetsObjectType, queryVals, updateKV)
+	fatalIf(err)
+	if numRows != 1 {
+		t.Fatal("expected CreateOrUpdateKV to update the widget, got", numRows, "rows")
+	}
+	objs, err := orm.New(getSQLGen(), sch, db).RetrieveMany(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 93})
+	fatalIf(err)
+	if len(objs) != 1 {
+		t.Fatal("expected the widget not to be inserted twice, got", len(objs), "rows")
+	}
+
+	_, err = o.Delete(ctx, nil, widget)
+	fatalIf(err)
+}
+
+// recordingMetrics remembers every operation it observes.
+type recordingMetrics struct {
+	ops  []string
+	errs []error
+}
+
+func (m *recordingMetrics) ObserveQuery(op string, table string, d time.Duration, err error) {
+	m.ops = append(m.ops, strings.TrimSpace(op+" "+table))
+	m.errs = append(m.errs, err)
+}
+
+func testMetrics(o orm.ORM, t *testing.T) {
+	m := &recordingMetrics{}
+	o.Metrics = m
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 76)
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+	_, err := o.Insert(ctx, nil, widget)
+	fatalIf(err)
+	queryVals := map[string]interface{}{"WidgetID": widget.Get("WidgetID")}
+	_, err = o.Retrieve(ctx, mock.WidgetsObjectType, queryVals)
+	fatalIf(err)
+	widget.Set("Age", 77)
+	_, err = o.Update(ctx, nil, widget)
+	fatalIf(err)
+	_, err = o.Exists(ctx, mock.WidgetsObjectType, queryVals)
+	fatalIf(err)
+	_, err = o.Delete(ctx, nil, widget)
+	fatalIf(err)
+	g := getSQLGen()
+	_, err = o.RawExec(ctx, nil, fmt.Sprintf("DELETE FROM widgets WHERE Age = %s", g.Placeholder(0, "Age")), -1)
+	fatalIf(err)
+	_, err = o.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"Nope": 1})
+	if err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+
+	expected := []string{"Insert widgets", "Retrieve widgets", "Update widgets", "Exists widgets", "Delete widgets", "Exec", "Exists widgets"}
+	if !reflect.DeepEqual(m.ops, expected) {
+		t.Fatal("unexpected observed operations", m.ops)
+	}
+	for i, err := range m.errs {
+		if (err != nil) != (i == len(m.errs)-1) {
+			t.Fatal("unexpected observed errors", m.errs)
+		}
+	}
+}
+
+// recordingSpan is a span started by recordingTracer.
+type recordingSpan struct {
+	name   string
+	parent string
+	sql    []string
+	ended  bool
+	err    error
+}
+
+func (s *recordingSpan) SetSQL(sqlStr string) { s.sql = append(s.sql, sqlStr) }
+func (s *recordingSpan) End(err error)        { s.ended, s.err = true, err }
+
+type spanNameKey struct{}
+
+// recordingTracer remembers every span it starts, and the span it was
+// started within.
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, op string, table string) (context.Context, orm.Span) {
+	parent, _ := ctx.Value(spanNameKey{}).(string)
+	span := &recordingSpan{name: strings.TrimSpace(op + " " + table), parent: parent}
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, spanNameKey{}, span.name), span
+}
+
+func testTracing(o orm.ORM, t *testing.T) {
+	tracer := &recordingTracer{}
+	o.Tracer = tracer
+	o.TraceSQL = true
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 78)
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+	_, err := o.Save(ctx, nil, widget)
+	fatalIf(err)
+	_, err = o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	fatalIf(err)
+	_, err = o.Delete(ctx, nil, widget)
+	fatalIf(err)
+	_ = o.Transact(ctx, func(tx *sql.Tx) error {
+		panic("boom")
+	}, nil)
+	err = o.RunInTxContext(ctx, func(ctx context.Context, tx *orm.TxORM) error {
+		_, err := tx.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 78})
+		if err != nil {
+			return err
+		}
+		_, err = tx.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 78})
+		return err
+	})
+	fatalIf(err)
+
+	var names []string
+	for _, s := range tracer.spans {
+		names = append(names, s.name)
+		if !s.ended {
+			t.Fatal("expected span", s.name, "to be ended")
+		}
+	}
+	expected := []string{"Save widgets", "Insert widgets", "Retrieve widgets", "Delete widgets", "Transact", "Transact", "Retrieve widgets"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatal("unexpected spans", names)
+	}
+	if insert := tracer.spans[1]; insert.parent != "Save widgets" || len(insert.sql) != 1 || !strings.HasPrefix(insert.sql[0], "INSERT") {
+		t.Fatal("expected the Insert span to be a child of Save and to carry its SQL", insert)
+	}
+	if transact := tracer.spans[4]; transact.err == nil {
+		t.Fatal("expected the Transact span to end with the panic")
+	}
+	if retrieve := tracer.spans[6]; retrieve.parent != "Transact" {
+		t.Fatal("expected the Retrieve run with the transaction's context to be a child of Transact", retrieve)
+	}
+}
+
+func testRefresh(o *orm.ORM, t *testing.T) {
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 72)
+	widget.Set("Color", "red")
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+
+	// Change the row behind the object's back
+	ctx, cancel = getDefaultContext()
+	other, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	cancel()
+	fatalIf(err)
+	other.Set("Color", "blue")
+	ctx, cancel = getDefaultContext()
+	_, err = o.Update(ctx, nil, other)
+	cancel()
+	fatalIf(err)
+
+	widget.Set("Age", 1)
+	ctx, cancel = getDefaultContext()
+	err = o.Refresh(ctx, widget)
+	cancel()
+	fatalIf(err)
+	if color, _ := widget.GetStringAlways("Color"); color != "blue" {
+		t.Fatal("expected the refreshed Color to be blue, got", color)
+	}
+	if age, _ := widget.GetIntAlways("Age"); age != 72 {
+		t.Fatal("expected the unsaved Age to be overwritten, got", age)
+	}
+	if widget.IsDirty() || len(widget.ChangedColumns) != 0 {
+		t.Fatal("expected a refreshed object to be clean")
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+	ctx, cancel = getDefaultContext()
+	err = o.Refresh(ctx, widget)
+	cancel()
+	if err != orm.ErrRowMissing {
+		t.Fatal("expected ErrRowMissing for a deleted row, got", err)
+	}
+}
+
+func testUpdateFields(o *orm.ORM, t *testing.T) {
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 73)
+	widget.Set("Color", "red")
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+
+	// Only Age should be written, Color keeps its saved value
+	widget.Set("Age", 74)
+	widget.Set("Color", "green")
+	ctx, cancel = getDefaultContext()
+	rowsAff, err := o.UpdateFields(ctx, nil, widget, "Age")
+	cancel()
+	fatalIf(err)
+	if rowsAff != 1 {
+		t.Fatal("expected UpdateFields to update one row, got", rowsAff)
+	}
+	if widget.IsDirty() || len(widget.ChangedColumns) != 0 {
+		t.Fatal("expected the object to be clean after UpdateFields")
+	}
+
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	cancel()
+	fatalIf(err)
+	if age, _ := retObj.GetIntAlways("Age"); age != 74 {
+		t.Fatal("expected Age to be updated to 74, got", age)
+	}
+	if color, _ := retObj.GetStringAlways("Color"); color != "red" {
+		t.Fatal("expected Color to be left as red, got", color)
+	}
+
+	for _, fields := range [][]string{nil, {"Nope"}, {"WidgetID"}, {"Price"}} {
+		ctx, cancel = getDefaultContext()
+		_, err = o.UpdateFields(ctx, nil, widget, fields...)
+		cancel()
+		if err == nil {
+			t.Fatal("expected an error for fields", fields)
+		}
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+}
+
+func testRawQuery(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	g := getSQLGen()
+	insertSQL := fmt.Sprintf("INSERT INTO widgets (Age, Color) VALUES (%s, %s)", g.Placeholder(0, "Age"), g.Placeholder(1, "Color"))
+	for _, color := range []string{"teal", "teal"} {
+		rowsAff, err := o.RawExec(ctx, nil, insertSQL, 65, color)
+		fatalIf(err)
+		if rowsAff != 1 {
+			t.Fatal("expected RawExec to insert one row, got", rowsAff)
+		}
+	}
+
+	selectSQL := fmt.Sprintf("SELECT Color, COUNT(*) AS N FROM widgets WHERE Age = %s GROUP BY Color", g.Placeholder(0, "Age"))
+	objs, err := o.RawQuery(ctx, selectSQL, 65)
+	fatalIf(err)
+	if len(objs) != 1 || objs[0].Type != "" {
+		t.Fatal("expected a single untyped object, got", objs)
+	}
+	color, err := objs[0].GetStringAlways("Color")
+	fatalIf(err)
+	n, err := objs[0].GetIntAlways("N")
+	fatalIf(err)
+	if color != "teal" || n != 2 {
+		t.Fatal("unexpected values", objs[0].KV)
+	}
+
+	objs, err = o.RawQueryAs(ctx, "counts", selectSQL, 65)
+	fatalIf(err)
+	if len(objs) != 1 || objs[0].Type != "counts" {
+		t.Fatal("expected a single object of type counts, got", objs)
+	}
+
+	_, err = o.DeleteMany(ctx, nil, mock.WidgetsObjectType, map[string]interface{}{"Age": 65})
+	fatalIf(err)
+}
+
+func testDeleteMany(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	insert := func(age int, color string) *object.Object {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", age)
+		widget.Set("Color", color)
+		_, err := o.Insert(ctx, nil, widget)
+		fatalIf(err)
+		return widget
+	}
+	insert(60, "red")
+	insert(60, "red")
+	kept := insert(60, "blue")
+
+	deleted, err := o.DeleteManyReturning(ctx, nil, mock.WidgetsObjectType, map[string]interface{}{"Age": 60, "Color": "red"})
+	fatalIf(err)
+	if len(deleted) != 2 {
+		t.Fatal("expected two deleted widgets to be returned, got", len(deleted))
+	}
+	for _, obj := range deleted {
+		if color, _ := obj.GetStringAlways("Color"); color != "red" || obj.Get("WidgetID") == nil {
+			t.Fatal("expected the deleted widgets to carry their values", obj)
+		}
+	}
+
+	// The rows are deleted by their keys even when the table's essential
+	// columns leave them out
+	widgets := o.GetSchema().GetTable(mock.WidgetsObjectType)
+	essential := widgets.EssentialColumns
+	widgets.EssentialColumns = []string{"Age", "Color"}
+	insert(61, "red")
+	deleted, err = o.DeleteManyReturning(ctx, nil, mock.WidgetsObjectType, map[string]interface{}{"Age": 61})
+	widgets.EssentialColumns = essential
+	fatalIf(err)
+	if len(deleted) != 1 || deleted[0].Get("WidgetID") == nil {
+		t.Fatal("expected the deleted widget to carry its primary key, got", deleted)
+	}
+	exists, err := o.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 61})
+	fatalIf(err)
+	if exists {
+		t.Fatal("expected the widget to be deleted")
+	}
+
+	// Inside a failed transaction, nothing is deleted
+	errStop := errors.New("stop")
+	err = o.RunInTx(ctx, func(tx *orm.TxORM) error {
+		deleted, err := tx.DeleteManyReturning(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 60})
+		if err != nil {
+			return err
+		}
+		if len(deleted) != 1 {
+			t.Fatal("expected the remaining widget to be deleted, got", len(deleted))
+		}
+		return errStop
+	})
+	if err != errStop {
+		t.Fatal("expected the transaction to fail with the error from fn, got", err)
+	}
+
+	rowsAff, err := o.DeleteMany(ctx, nil, mock.WidgetsObjectType, map[string]interface{}{"Age": 60})
+	fatalIf(err)
+	if rowsAff != 1 {
+		t.Fatal("expected DeleteMany to delete the remaining widget, got", rowsAff)
+	}
+	exists, err = o.Exists(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": kept.Get("WidgetID")})
+	fatalIf(err)
+	if exists {
+		t.Fatal("expected the widget to be deleted")
+	}
+
+	if _, err := o.DeleteMany(ctx, nil, mock.WidgetsObjectType, nil); err == nil {
+		t.Fatal("expected DeleteMany to refuse to delete every row")
+	}
+}
+
+func testGeneratedColumn(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	// NextAge is computed by the database, so the value set here is never
+	// bound.
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 41)
+	widget.Set("NextAge", 1000)
+	_, err := o.Save(ctx, nil, widget)
+	fatalIf(err)
+
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	fatalIf(err)
+	if next, _ := retObj.GetIntAlways("NextAge"); next != 42 {
+		t.Fatal("expected NextAge to be computed as 42, got", next)
+	}
+
+	retObj.Set("Age", 50)
+	_, err = o.Save(ctx, nil, retObj)
+	fatalIf(err)
+	fatalIf(o.Refresh(ctx, retObj))
+	if next, _ := retObj.GetIntAlways("NextAge"); next != 51 {
+		t.Fatal("expected NextAge to follow the update to 51, got", next)
+	}
+
+	_, err = o.Delete(ctx, nil, widget)
+	fatalIf(err)
+}
+
+func testVerifySchema(t *testing.T, sch *schema.Schema, db *sql.DB) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	o := orm.New(getSQLGen(), sch, db)
+	fatalIf(o.VerifySchema(ctx))
+
+	// A schema which has drifted from the database
+	drifted := mock.WidgetSchema()
+	missing := schema.DefaultColumn()
+	missing.Name = "Weight"
+	missing.LogicalType = schema.LogicalInt
+	drifted.Tables[mock.WidgetsObjectType].Columns["Weight"] = missing
+	drifted.Tables[mock.WidgetsObjectType].Columns["Color"].DBType = ""
+	drifted.Tables[mock.WidgetsObjectType].Columns["Color"].LogicalType = schema.LogicalTimestamp
+	nope := schema.DefaultTable()
+	nope.Name = "nope"
+	drifted.Tables["nope"] = nope
+
+	o = orm.New(getSQLGen(), drifted, db)
+	err := o.VerifySchema(ctx)
+	var mismatchErr *orm.SchemaMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatal("expected a SchemaMismatchError, got", err)
+	}
+	var problems []string
+	for _, m := range mismatchErr.Mismatches {
+		problems = append(problems, strings.ToLower(m.Table+"."+m.Column))
+	}
+	expected := []string{"nope.", "widgets.color", "widgets.weight"}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Fatal("unexpected mismatches", mismatchErr)
+	}
+}
+
+func testTruncate(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 95)
+		_, err := o.Insert(ctx, nil, widget)
+		fatalIf(err)
+	}
+	fatalIf(o.Truncate(ctx, mock.WidgetsObjectType))
+
+	objs, err := o.Query(ctx, query.New().From(mock.WidgetsObjectType))
+	fatalIf(err)
+	if len(objs) != 0 {
+		t.Fatal("expected Truncate to delete every widget, found", len(objs))
+	}
+	if err := o.Truncate(ctx, "nope"); err == nil {
+		t.Fatal("expected an error for an unknown table")
+	}
+}
+
+func testQuoteLiteral(o *orm.ORM, t *testing.T) {
+	color := `it's a "quoted" \ color`
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 91)
+	widget.Set("Color", object.NewSQLValue(o.GetSQLGenerator().QuoteLiteral(color)))
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	saved, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"Age": 91})
+	cancel()
+	fatalIf(err)
+	if saved == nil {
+		t.Fatal("expected the widget to be saved")
+	}
+	if got, _ := saved.GetStringAlways("Color"); got != color {
+		t.Fatalf("expected the literal to round-trip as %q, got %q", color, got)
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, saved)
+	cancel()
+	fatalIf(err)
+}
+
+func testTimeout(o *orm.ORM, t *testing.T) {
+	queryVals := map[string]interface{}{"Age": 90}
+	short := o.WithTimeout(time.Nanosecond)
+	if _, err := short.RetrieveMany(context.Background(), mock.WidgetsObjectType, queryVals); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected RetrieveMany to stop with context.DeadlineExceeded, got", err)
+	}
+	if o.Timeout != 0 {
+		t.Fatal("expected WithTimeout to leave the ORM alone")
+	}
+	// Operations running others are bounded as a whole
+	if _, err := short.Exists(context.Background(), mock.WidgetsObjectType, queryVals); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected Exists to stop with context.DeadlineExceeded, got", err)
+	}
+	if _, err := short.OpenCursor(context.Background(), mock.WidgetsObjectType, queryVals); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected OpenCursor to stop with context.DeadlineExceeded, got", err)
+	}
+	unsaved := object.New(mock.WidgetsObjectType)
+	unsaved.Set("Age", 90)
+	if _, err := short.SaveAll(context.Background(), unsaved); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected SaveAll to stop with context.DeadlineExceeded, got", err)
+	}
+
+	// A shorter timeout nested within a longer one still applies
+	long := o.WithTimeout(time.Minute)
+	err := long.RunInTxContext(context.Background(), func(ctx context.Context, _ *orm.TxORM) error {
+		_, err := short.RetrieveMany(ctx, mock.WidgetsObjectType, queryVals)
+		return err
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected the nested RetrieveMany to stop with context.DeadlineExceeded, got", err)
+	}
+
+	// The override applies on top of the ORM's own Timeout
+	withDefault := *o
+	withDefault.Timeout = time.Nanosecond
+	_, err = withDefault.WithTimeout(time.Minute).RetrieveMany(context.Background(), mock.WidgetsObjectType, queryVals)
+	fatalIf(err)
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 90)
+	_, err = o.WithTimeout(time.Minute).Save(context.Background(), nil, widget)
+	fatalIf(err)
+	ctx, cancel := getDefaultContext()
+	_, err = o.Delete(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+}
+
+func testContextCancel(t *testing.T, sch *schema.Schema, db *sql.DB) {
+	// cancelRetrieve is cancelled by the generator below as soon as the
+	// first row has been scanned.
+	var cancelRetrieve context.CancelFunc
+	gen := *getSQLGen()
+	setter := gen.DynamicObjectSetter
+	gen.DynamicObjectSetter = func(g *sg.SQLGenerator, columnNames []string, columnPointers []interface{}, columnTypes []*sql.ColumnType, obj *object.Object) error {
+		if cancelRetrieve != nil {
+			cancelRetrieve()
+		}
+		return setter(g, columnNames, columnPointers, columnTypes, obj)
+	}
+	o := orm.New(&gen, sch, db)
+
+	var widgets object.Array
+	for i := 0; i < 3; i++ {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 90)
+		ctx, cancel := getDefaultContext()
+		_, err := o.Insert(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+		widgets = append(widgets, widget)
+	}
+	queryVals := map[string]interface{}{"Age": 90}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelRetrieve = cancel
+	objs, err := o.RetrieveMany(ctx, mock.WidgetsObjectType, queryVals)
+	cancelRetrieve = nil
+	if !errors.Is(err, context.Canceled) || objs != nil {
+		t.Fatal("expected RetrieveMany to stop with context.Canceled, got", len(objs), "objects and", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	var seen int
+	err = o.RetrieveEach(ctx, mock.WidgetsObjectType, queryVals, func(*object.Object) error {
+		seen++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || seen != 1 {
+		t.Fatal("expected RetrieveEach to stop with context.Canceled after one object, saw", seen, "and got", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	c, err := o.OpenCursor(ctx, mock.WidgetsObjectType, queryVals)
+	fatalIf(err)
+	if !c.Next() {
+		t.Fatal("expected the cursor to have rows")
+	}
+	cancel()
+	if c.Next() || !errors.Is(c.Err(), context.Canceled) {
+		t.Fatal("expected the cursor to stop with context.Canceled, got", c.Err())
+	}
+
+	for _, widget := range widgets {
+		ctx, cancel = getDefaultContext()
+		_, err = o.Delete(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+	}
+}
+
+func testRunInTx(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	committed := object.New(mock.WidgetsObjectType)
+	committed.Set("Age", 80)
+	err := o.RunInTx(ctx, func(tx *orm.TxORM) error {
+		_, err := tx.Insert(ctx, committed)
+		return err
+	})
+	fatalIf(err)
+
+	errStop := errors.New("stop")
+	rolledBack := object.New(mock.WidgetsObjectType)
+	rolledBack.Set("Age", 81)
+	err = o.RunInTx(ctx, func(tx *orm.TxORM) error {
+		if _, err := tx.Insert(ctx, rolledBack); err != nil {
+			return err
+		}
+		return errStop
+	})
+	if err != errStop {
+		t.Fatal("expected RunInTx to return the error from fn, got", err)
+	}
+
+	err = o.RunInTx(ctx, func(tx *orm.TxORM) error {
+		panic("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatal("expected RunInTx to return the panic as an error, got", err)
+	}
+
+	objs, err := o.Query(ctx, query.New().From(mock.WidgetsObjectType).Where("Age", "IN", []interface{}{80, 81}))
+	fatalIf(err)
+	if n := len(objs); n != 1 {
+		t.Fatal("expected only the committed widget to be saved, found", n)
+	}
+
+	_, err = o.Delete(ctx, nil, committed)
+	fatalIf(err)
+}
+
+func testTransactRollbackError(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	// Rolling back inside txFunc makes Transact's own rollback fail too
+	errStop := errors.New("stop")
+	for _, transact := range []func(context.Context, orm.TxFuncType, *sql.TxOptions) error{o.Transact, o.TransactRethrow} {
+		err := transact(ctx, func(tx *sql.Tx) error {
+			fatalIf(tx.Rollback())
+			return errStop
+		}, nil)
+		var rbErr *orm.RollbackError
+		if !errors.As(err, &rbErr) {
+			t.Fatal("expected a RollbackError, got", err)
+		}
+		if !errors.Is(err, errStop) || !errors.Is(err, sql.ErrTxDone) {
+			t.Fatal("expected the error to carry both the cause and the rollback failure, got", err)
+		}
+	}
+
+	// A successful rollback returns the error unchanged
+	err := o.Transact(ctx, func(tx *sql.Tx) error {
+		return errStop
+	}, nil)
+	if err != errStop {
+		t.Fatal("expected Transact to return the error from txFunc, got", err)
+	}
+}
+
+// testTransactWithRetry runs a transaction that fails with a conflict the
+// generator classifies as retryable.
+func testTransactWithRetry(t *testing.T, db *sql.DB) {
+	errConflict := errors.New("conflict")
+	g := getSQLGen()
+	g.IsRetryable = func(err error) bool { return err == errConflict }
+	o := orm.New(g, mock.WidgetSchema(), db)
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	runs := 0
+	conflictTwice := func(tx *sql.Tx) error {
+		runs++
+		if runs <= 2 {
+			return errConflict
+		}
+		return nil
+	}
+	if err := o.TransactWithRetry(ctx, 3, conflictTwice, nil); err != nil || runs != 3 {
+		t.Fatal("expected the transaction to succeed on its third run, got", err, runs)
+	}
+
+	runs = 0
+	if err := o.TransactWithRetry(ctx, 2, conflictTwice, nil); err != errConflict || runs != 2 {
+		t.Fatal("expected the conflict once the attempts are used up, got", err, runs)
+	}
+
+	runs = 0
+	errStop := errors.New("stop")
+	err := o.TransactWithRetry(ctx, 3, func(tx *sql.Tx) error {
+		runs++
+		return errStop
+	}, nil)
+	if err != errStop || runs != 1 {
+		t.Fatal("expected an error that isn't retryable to be returned at once, got", err, runs)
+	}
+}
+
+// testSQLValueColumns inserts and updates with SQL expressions as the values
+// of ordinary columns.
+func testSQLValueColumns(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 40)
+	widget.Set("Created", object.NewSQLValue("CURRENT_TIMESTAMP"))
+	_, err := o.Insert(ctx, nil, widget)
+	fatalIf(err)
+	key := map[string]interface{}{"WidgetID": widget.Get("WidgetID")}
+
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, key)
+	fatalIf(err)
+	created, err := retObj.GetTimeAlways("Created")
+	if err != nil || created.IsZero() {
+		t.Fatal("expected Created to be set by the database, got", retObj.Get("Created"), err)
+	}
+
+	retObj.Set("Age", object.NewSQLValue("Age + 1"))
+	_, err = o.Update(ctx, nil, retObj)
+	fatalIf(err)
+	retObj, err = o.Retrieve(ctx, mock.WidgetsObjectType, key)
+	fatalIf(err)
+	if age, _ := retObj.GetIntAlways("Age"); age != 41 {
+		t.Fatal("expected Age to be incremented by the database, got", retObj.Get("Age"))
+	}
+
+	_, err = o.Delete(ctx, nil, retObj)
+	fatalIf(err)
+}
+
+func testSetNull(o *orm.ORM, t *testing.T) {
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 75)
+	widget.Set("Created", time.Date(2017, 6, 1, 12, 30, 15, 0, time.UTC))
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	cancel()
+	fatalIf(err)
+
+	// Created is written as NULL alongside the other changed column
+	retObj.Set("Age", 76)
+	retObj.SetNull("Created")
+	ctx, cancel = getDefaultContext()
+	_, err = o.Save(ctx, nil, retObj)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	retObj, err = o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+	cancel()
+	fatalIf(err)
+	if age, _ := retObj.GetIntAlways("Age"); age != 76 {
+		t.Fatal("expected Age to be updated to 76, got", age)
+	}
+	if v := retObj.Get("Created"); v != nil && !retObj.ValueIsNULL(v) {
+		t.Fatal("expected Created to be NULL, got", v)
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+}
+
+func testSaveResult(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 30)
+	res, err := o.SaveResult(ctx, nil, widget)
+	fatalIf(err)
+	if res.Action != orm.Inserted || res.RowsAffected != 1 {
+		t.Fatal("expected a new widget to be inserted, got", res)
+	}
+	if len(res.Changed) != 1 || res.Changed["Age"] != 30 || len(widget.ChangedFields()) != 0 {
+		t.Fatal("expected the changed fields from before the insert, got", res.Changed)
+	}
+
+	res, err = o.SaveResult(ctx, nil, widget)
+	fatalIf(err)
+	if res.Action != orm.Unchanged || res.RowsAffected != 0 || res.Changed != nil {
+		t.Fatal("expected a clean widget to be left unchanged, got", res)
+	}
+
+	widget.Set("Age", 31)
+	res, err = o.SaveResult(ctx, nil, widget)
+	fatalIf(err)
+	if res.Action != orm.Updated || res.RowsAffected != 1 {
+		t.Fatal("expected a changed widget to be updated, got", res)
+	}
+	if len(res.Changed) != 1 || res.Changed["Age"] != 31 {
+		t.Fatal("expected the changed fields from before the update, got", res.Changed)
+	}
+
+	_, err = o.Delete(ctx, nil, widget)
+	fatalIf(err)
+}
+
+func testBulkInsert(o *orm.ORM, t *testing.T) {
+	var widgets object.Array
+	for i := 0; i < 5; i++ {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 300+i)
+		if i == 2 {
+			// A different set of columns starts a new statement
+			widget.Set("Color", "teal")
+		}
+		widgets = append(widgets, widget)
+	}
+
+	ctx, cancel := getDefaultContext()
+	rowsAff, err := o.BulkInsert(ctx, nil, widgets)
+	cancel()
+	fatalIf(err)
+	if rowsAff != int64(len(widgets)) {
+		t.Fatal("expected every widget to be inserted, got", rowsAff)
+	}
+
+	seen := make(map[int64]bool)
+	for i, widget := range widgets {
+		id, err := widget.GetIntAlways("WidgetID")
+		fatalIf(err)
+		if id == 0 || seen[id] {
+			t.Fatal("expected a distinct, non-zero key, got", id)
+		}
+		seen[id] = true
+		if widget.IsDirty() {
+			t.Fatal("expected the inserted widget to be clean")
+		}
+
+		// The keys are handed out in the order of the objects
+		ctx, cancel := getDefaultContext()
+		saved, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": id})
+		cancel()
+		fatalIf(err)
+		if age, _ := saved.GetIntAlways("Age"); age != int64(300+i) {
+			t.Fatalf("expected widget %d to have Age %d, got %d", id, 300+i, age)
+		}
+
+		ctx, cancel = getDefaultContext()
+		_, err = o.Delete(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+	}
+}
+
+func testBulkUpdate(o *orm.ORM, t *testing.T) {
+	var widgets object.Array
+	for i := 0; i < 4; i++ {
+		widget := object.New(mock.WidgetsObjectType)
+		widget.Set("Age", 400+i)
+		widget.Set("Color", "white")
+		widgets = append(widgets, widget)
+	}
+	ctx, cancel := getDefaultContext()
+	_, err := o.BulkInsert(ctx, nil, widgets)
+	cancel()
+	fatalIf(err)
+
+	colors := []string{"red", "green", "blue", "black"}
+	for i, widget := range widgets {
+		widget.Set("Color", colors[i])
+		if i == 3 {
+			// A different set of columns is updated on its own
+			widget.Set("Age", 500)
+		}
+	}
+	ctx, cancel = getDefaultContext()
+	rowsAff, err := o.BulkUpdate(ctx, nil, widgets)
+	cancel()
+	fatalIf(err)
+	if rowsAff != int64(len(widgets)) {
+		t.Fatal("expected every widget to be updated, got", rowsAff)
+	}
+
+	for i, widget := range widgets {
+		if widget.IsDirty() {
+			t.Fatal("expected the updated widget to be clean")
+		}
+		ctx, cancel := getDefaultContext()
+		saved, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": widget.Get("WidgetID")})
+		cancel()
+		fatalIf(err)
+		expectedAge := int64(400 + i)
+		if i == 3 {
+			expectedAge = 500
+		}
+		if age, _ := saved.GetIntAlways("Age"); age != expectedAge || saved.Get("Color") != colors[i] {
+			t.Fatalf("expected widget %d to have Age %d and Color %s, got %v", i, expectedAge, colors[i], saved)
+		}
+
+		ctx, cancel = getDefaultContext()
+		_, err = o.Delete(ctx, nil, widget)
+		cancel()
+		fatalIf(err)
+	}
+
+	missingKey := object.New(mock.WidgetsObjectType)
+	missingKey.Set("Color", "red")
+	ctx, cancel = getDefaultContext()
+	_, err = o.BulkUpdate(ctx, nil, object.Array{missingKey, missingKey})
+	cancel()
+	if err == nil {
+		t.Fatal("expected objects without a key to be refused")
+	}
+}
+
+func testEssentialColumns(o *orm.ORM, t *testing.T) {
+	widget := object.New(mock.WidgetsObjectType)
+	widget.Set("Age", 12)
+	widget.Set("Price", "5.25")
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+	queryVals := map[string]interface{}{"WidgetID": widget.Get("WidgetID")}
+
+	// Price is not one of the EssentialColumns, so it is left out by default
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, queryVals)
+	cancel()
+	fatalIf(err)
+	if retObj == nil {
+		t.Fatal("expected to retrieve the widget")
+	}
+	if _, ok := retObj.KV["Price"]; ok {
+		t.Fatal("expected Price to be absent by default, got", retObj.Get("Price"))
+	}
+	if age, _ := retObj.GetIntAlways("Age"); age != 12 {
+		t.Fatal("expected Age 12, got", retObj.Get("Age"))
+	}
+
+	ctx, cancel = getDefaultContext()
+	retObj, err = o.Retrieve(ctx, mock.WidgetsObjectType, queryVals, orm.WithAllColumns())
+	cancel()
+	fatalIf(err)
+	if retObj == nil {
+		t.Fatal("expected to retrieve the widget")
+	}
+	if price := retObj.Get("Price"); price != "5.25" {
+		t.Fatal("expected Price 5.25 with WithAllColumns, got", price)
+	}
+
+	ctx, cancel = getDefaultContext()
+	objs, err := o.RetrieveMany(ctx, mock.WidgetsObjectType, queryVals, orm.WithAllColumns())
+	cancel()
+	fatalIf(err)
+	if len(objs) != 1 || objs[0].Get("Price") != "5.25" {
+		t.Fatal("expected RetrieveMany with WithAllColumns to select Price, got", objs)
+	}
+	ctx, cancel = getDefaultContext()
+	objs, err = o.RetrieveMany(ctx, mock.WidgetsObjectType, queryVals)
+	cancel()
+	fatalIf(err)
+	if len(objs) != 1 {
+		t.Fatal("expected one widget, got", objs)
+	}
+	if _, ok := objs[0].KV["Price"]; ok {
+		t.Fatal("expected RetrieveMany to leave Price out by default")
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, widget)
+	cancel()
+	fatalIf(err)
+}
+
+func testTimestamp(o *orm.ORM, t *testing.T) {
+	// Seconds are the smallest precision every supported database keeps
+	// by default.
+	created := time.Date(2017, 6, 1, 12, 30, 15, 0, time.UTC)
+
+	obj := object.New(mock.WidgetsObjectType)
+	obj.Set("Age", int64(4))
+	obj.Set("Created", created)
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, obj)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{
+		"WidgetID": obj.Get("WidgetID"),
+	})
+	cancel()
+	fatalIf(err)
+	if retObj == nil {
+		t.Fatal("expected to retrieve the inserted widget")
+	}
+
+	retCreated, ok := retObj.Get("Created").(time.Time)
+	if !ok {
+		t.Fatalf("expected Created to be retrieved as a time.Time, got %v", retObj.Get("Created"))
+	}
+	if !retCreated.Equal(created) {
+		t.Fatalf("expected Created to be %v, got %v", created, retCreated)
+	}
+}
+
+func testValidate(o *orm.ORM, t *testing.T) {
+	obj := object.New(mock.WidgetsObjectType)
+	obj.Set("Age", int64(3))
+	fatalIf(o.Validate(obj))
+
+	obj = object.New(mock.WidgetsObjectType)
+	obj.Set("Age", "abc")
+	obj.Set("Color", "a color name which is far too long for the column")
+	obj.Set("Bogus", 1)
+	err := o.Validate(obj)
+	verrs, ok := err.(orm.ValidationErrors)
+	if !ok || len(verrs) != 3 {
+		t.Fatal("expected three validation errors, got", err)
+	}
+
+	// sql.Null* values are checked by the value they bind, and an invalid
+	// one is NULL
+	obj = object.New(mock.WidgetsObjectType)
+	obj.Set("Age", sql.NullInt64{Int64: 3, Valid: true})
+	obj.Set("Color", sql.NullString{String: "red", Valid: true})
+	obj.Set("Price", int32(5))
+	fatalIf(o.Validate(obj))
+	obj.Set("Price", sql.NullString{String: "19.99", Valid: true})
+	fatalIf(o.Validate(obj))
+	obj.Set("Age", sql.NullInt64{})
+	obj.Set("Color", sql.NullString{String: strings.Repeat("x", 31), Valid: true})
+	err = o.Validate(obj)
+	if verrs, ok := err.(orm.ValidationErrors); !ok || len(verrs) != 2 || !strings.Contains(err.Error(), "NULL value for NOT NULL column Age") || !strings.Contains(err.Error(), "exceeds length") {
+		t.Fatal("expected an invalid sql.NullInt64 and an over-long sql.NullString to fail validation, got", err)
+	}
+
+	// Values outside a column's AllowedValues are rejected
+	color := o.GetSchema().GetTable(mock.WidgetsObjectType).GetColumn("Color")
+	color.AllowedValues = []string{"red", "blue"}
+	obj = object.New(mock.WidgetsObjectType)
+	obj.Set("Age", int64(3))
+	obj.Set("Color", "red")
+	okErr := o.Validate(obj)
+	obj.Set("Color", "green")
+	err = o.Validate(obj)
+	color.AllowedValues = nil
+	fatalIf(okErr)
+	if verrs, ok := err.(orm.ValidationErrors); !ok || len(verrs) != 1 || !strings.Contains(err.Error(), "allowed values") {
+		t.Fatal("expected a value outside AllowedValues to fail validation, got", err)
+	}
+
+	// A missing NOT NULL column should also stop Save when validating
+	obj = object.New(mock.WidgetsObjectType)
+	obj.Set("Color", "red")
+	o.ValidateBeforeSave = true
+	ctx, cancel := getDefaultContext()
+	_, err = o.Save(ctx, nil, obj)
+	cancel()
+	o.ValidateBeforeSave = false
+	if _, ok := err.(orm.ValidationErrors); !ok {
+		t.Fatal("expected Save to fail validation, got", err)
+	}
+}
+
+func testLengthPolicy(o *orm.ORM, t *testing.T) {
+	color := o.GetSchema().GetTable(mock.WidgetsObjectType).GetColumn("Color")
+	prevLength := color.Length
+	color.Length = 10
+	defer func() {
+		color.Length = prevLength
+		o.LengthPolicy = orm.LengthUnchecked
+	}()
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	// Over-long strings are refused before reaching the database
+	o.LengthPolicy = orm.LengthError
+	obj := object.New(mock.WidgetsObjectType)
+	obj.Set("Age", int64(5))
+	obj.Set("Color", "ultramarine")
+	_, err := o.Insert(ctx, nil, obj)
+	if verrs, ok := err.(orm.ValidationErrors); !ok || len(verrs) != 1 || !strings.Contains(err.Error(), "exceeds length 10 of column Color") {
+		t.Fatal("expected an over-long Color to fail the insert, got", err)
+	}
+	if obj.Get("WidgetID") != nil {
+		t.Fatal("expected nothing to be inserted")
+	}
+
+	// or trimmed to the column's Length
+	o.LengthPolicy = orm.LengthTruncate
+	_, err = o.Insert(ctx, nil, obj)
+	fatalIf(err)
+	if obj.Get("Color") != "ultramarin" {
+		t.Fatal("expected Color to be truncated in the object, got", obj.Get("Color"))
+	}
+	obj.Set("Color", "aquamarine blue")
+	_, err = o.Update(ctx, nil, obj)
+	fatalIf(err)
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": obj.Get("WidgetID")})
+	fatalIf(err)
+	if retObj.Get("Color") != "aquamarine" {
+		t.Fatal("expected Color to be stored truncated to 10 characters, got", retObj.Get("Color"))
+	}
+
+	_, err = o.Delete(ctx, nil, retObj)
+	fatalIf(err)
+}
+
+// testAddDropColumn adds a nullable column to widgets, writes and reads it
+// back, and drops it again.
+func testAddDropColumn(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	defer cancel()
+
+	col := schema.DefaultColumn()
+	col.Name = "Nickname"
+	col.LogicalType = schema.LogicalString
+	col.Length = 20
+	col.AllowNull = true
+	fatalIf(o.AddColumn(ctx, mock.WidgetsObjectType, col))
+	if err := o.AddColumn(ctx, mock.WidgetsObjectType, col); err == nil {
+		t.Fatal("expected AddColumn to refuse a column the table already has")
+	}
+
+	obj := object.New(mock.WidgetsObjectType)
+	obj.Set("Age", int64(7))
+	obj.Set("Nickname", "sprocket")
+	_, err := o.Insert(ctx, nil, obj)
+	fatalIf(err)
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": obj.Get("WidgetID")})
+	fatalIf(err)
+	if retObj.Get("Nickname") != "sprocket" {
+		t.Fatal("expected the added column to be written and retrieved, got", retObj.Get("Nickname"))
+	}
+
+	fatalIf(o.DropColumn(ctx, mock.WidgetsObjectType, "Nickname"))
+	if o.GetSchema().GetTable(mock.WidgetsObjectType).GetColumn("Nickname") != nil {
+		t.Fatal("expected DropColumn to remove the column from the schema")
+	}
+	if err := o.DropColumn(ctx, mock.WidgetsObjectType, "WidgetID"); err == nil {
+		t.Fatal("expected DropColumn to refuse the primary key")
+	}
+	if err := o.DropColumn(ctx, mock.PartDocsObjectType, "PartNumber"); err == nil {
+		t.Fatal("expected DropColumn to refuse a child key column")
+	}
+	if o.GetSchema().GetTable(mock.PartDocsObjectType).GetColumn("PartNumber") == nil {
+		t.Fatal("expected a refused DropColumn to leave the schema unchanged")
+	}
+	retObj, err = o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{"WidgetID": obj.Get("WidgetID")})
+	fatalIf(err)
+	if _, ok := retObj.KV["Nickname"]; ok {
+		t.Fatal("expected the dropped column not to be retrieved")
+	}
+	_, err = o.Delete(ctx, nil, retObj)
+	fatalIf(err)
+}
+
+func testDefaultValue(o *orm.ORM, t *testing.T) {
+	obj := object.New(mock.WidgetsObjectType)
+	obj.Set("Age", int64(2))
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, obj)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, mock.WidgetsObjectType, map[string]interface{}{
+		"WidgetID": obj.Get("WidgetID"),
+	})
+	cancel()
+	fatalIf(err)
+	if retObj == nil {
+		t.Fatal("expected to retrieve the inserted widget")
+	}
+
+	color, err := retObj.GetStringAlways("Color")
+	fatalIf(err)
+	if color != "blue" {
+		t.Fatalf("expected default Color 'blue', got '%s'", color)
+	}
+}
 
-	TestSuiteNested(t, db)
+func testCheckConstraint(o *orm.ORM, t *testing.T) {
+	bad := object.New(mock.WidgetsObjectType)
+	bad.Set("Age", int64(-1))
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, bad)
+	cancel()
+	if err == nil {
+		t.Fatal("expected CHECK constraint to reject a negative Age")
+	}
 
-	t.Run("TestDropTables", func(t *testing.T) {
-		TestDropTables(t, db)
-	})
+	good := object.New(mock.WidgetsObjectType)
+	good.Set("Age", int64(1))
+	ctx, cancel = getDefaultContext()
+	rowsAff, err := o.Insert(ctx, nil, good)
+	cancel()
+	fatalIf(err)
+	if rowsAff != 1 {
+		t.Fatal("expected a single row inserted, got", rowsAff)
+	}
 }
 
 func TestCreateTables(t *testing.T, db *sql.DB) {
@@ -166,6 +2984,13 @@ func TestSuiteNested(t *testing.T, db *sql.DB) {
 	// Test second additional Save to ensure that we don't save
 	// the object twice needlessly... This caught a silly bug early on.
 	t.Run("TestAdditionalSave", func(t *testing.T) {
+		if o.NeedsSave(obj) {
+			t.Fatal("a saved object should not need saving")
+		}
+		prepared := o.PreparedStatements()
+		if prepared == 0 {
+			t.Fatal("expected the first save to have prepared statements")
+		}
 		ctx, cancel := getDefaultContext()
 		rowsAff, err := o.Save(ctx, nil, obj)
 		cancel()
@@ -173,6 +2998,9 @@ func TestSuiteNested(t *testing.T, db *sql.DB) {
 		if rowsAff != 0 {
 			t.Fatal("rowsAff should be zero the second time")
 		}
+		if n := o.PreparedStatements() - prepared; n != 0 {
+			t.Fatal("expected no statements for a clean object, got", n)
+		}
 	})
 
 	// Now, trigger an update.
@@ -189,20 +3017,168 @@ func TestSuiteNested(t *testing.T, db *sql.DB) {
 		testRetrieve(&o, t, sch)
 	})
 
+	t.Run("RetrieveColumns", func(t *testing.T) {
+		testRetrieveColumns(&o, t)
+	})
+
+	t.Run("RetrieveNULL", func(t *testing.T) {
+		testRetrieveNULL(&o, t)
+	})
+
+	t.Run("NullSemantics", func(t *testing.T) {
+		testNullSemantics(&o, t)
+	})
+
+	t.Run("NULLRoundTrip", func(t *testing.T) {
+		testNULLRoundTrip(&o, t)
+	})
+
+	t.Run("Codec", func(t *testing.T) {
+		testCodec(t, sch, db)
+	})
+
+	t.Run("QuerySubquery", func(t *testing.T) {
+		testQuerySubquery(&o, t)
+	})
+
 	t.Run("RetrieveMany", func(t *testing.T) {
 		// test multiple retrieve
 		testRetrieveMany(&o, t, mock.PeopleObjectType)
 	})
 
+	t.Run("RetrieveEach", func(t *testing.T) {
+		testRetrieveEach(&o, t)
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		testExists(&o, t)
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		testCursor(&o, t)
+	})
+
 	t.Run("FleshenChildren", func(t *testing.T) {
 		// try fleshen children on person id 1
 		testFleshenChildren(&o, t, mock.PeopleObjectType)
 	})
 
+	t.Run("RetrieveJoined", func(t *testing.T) {
+		testRetrieveJoined(&o, t)
+	})
+
 	t.Run("GetParentsViaChild", func(t *testing.T) {
 		// test retrieving multiple parents, given a single child object
 		testGetParentsViaChild(&o, t)
 	})
+
+	t.Run("FleshenParents", func(t *testing.T) {
+		testFleshenParents(&o, t)
+	})
+
+	t.Run("Blob", func(t *testing.T) {
+		testBlob(&o, t)
+	})
+
+	t.Run("FleshenChildrenBulk", func(t *testing.T) {
+		testFleshenChildrenBulk(&o, t)
+	})
+}
+
+func testFleshenChildrenBulk(o *orm.ORM, t *testing.T) {
+	for _, n := range []int{2, 5} {
+		name := fmt.Sprintf("Bulk%d", n)
+		for i := 0; i < n; i++ {
+			person := object.New(mock.PeopleObjectType)
+			person.Set("Name", name)
+			addr := mock.SampleAddressObject()
+			addr.Set("City", fmt.Sprintf("City%d", i))
+			person.Children["addresses"] = object.NewArray(addr)
+			ctx, cancel := getDefaultContext()
+			_, err := o.SaveAll(ctx, person)
+			cancel()
+			fatalIf(err)
+		}
+
+		ctx, cancel := getDefaultContext()
+		people, err := o.RetrieveMany(ctx, mock.PeopleObjectType, map[string]interface{}{"Name": name})
+		cancel()
+		fatalIf(err)
+		if len(people) != n {
+			t.Fatal("expected", n, "people, got", len(people))
+		}
+
+		prepared := o.PreparedStatements()
+		ctx, cancel = getDefaultContext()
+		err = o.FleshenChildrenBulk(ctx, people)
+		cancel()
+		fatalIf(err)
+		if q := o.PreparedStatements() - prepared; q != 1 {
+			t.Fatal("expected a single query for", n, "parents, got", q)
+		}
+
+		for _, person := range people {
+			addrs := person.Children["addresses"]
+			if len(addrs) != 1 {
+				t.Fatal("expected one address per person, got", len(addrs))
+			}
+			personID, _ := person.GetIntAlways("PersonID")
+			addrPersonID, _ := addrs[0].GetIntAlways("PersonID")
+			if personID != addrPersonID {
+				t.Fatal("address of person", addrPersonID, "was given to person", personID)
+			}
+
+			ctx, cancel = getDefaultContext()
+			_, err = o.Delete(ctx, nil, addrs[0])
+			fatalIf(err)
+			_, err = o.Delete(ctx, nil, person)
+			cancel()
+			fatalIf(err)
+		}
+	}
+}
+
+func testBlob(o *orm.ORM, t *testing.T) {
+	payload := []byte{0, 'd', 'y', 'n', 0, 0, 255, 1, 0}
+	person := object.New(mock.PeopleObjectType)
+	person.Set("Name", "Blob")
+	person.Set("NullBlob", payload)
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, person)
+	cancel()
+	fatalIf(err)
+
+	retrieve := func() *object.Object {
+		ctx, cancel := getDefaultContext()
+		retObj, err := o.Retrieve(ctx, mock.PeopleObjectType, map[string]interface{}{"PersonID": person.Get("PersonID")})
+		cancel()
+		fatalIf(err)
+		if retObj == nil {
+			t.Fatal("expected to retrieve the person")
+		}
+		return retObj
+	}
+
+	retObj := retrieve()
+	blob, ok := retObj.GetBytes("NullBlob")
+	if !ok || !bytes.Equal(blob, payload) {
+		t.Fatalf("expected the blob bytes to round trip exactly, got %#v", retObj.Get("NullBlob"))
+	}
+
+	// A NULL blob is retrieved as NULL
+	retObj.Set("NullBlob", object.NewNULLValue())
+	ctx, cancel = getDefaultContext()
+	_, err = o.Update(ctx, nil, retObj)
+	cancel()
+	fatalIf(err)
+	if refetched := retrieve(); !refetched.ValueIsNULL(refetched.Get("NullBlob")) {
+		t.Fatalf("expected a NULL blob, got %#v", refetched.Get("NullBlob"))
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, retObj)
+	cancel()
+	fatalIf(err)
 }
 
 func saveMockObject(t *testing.T, o *orm.ORM, obj *object.Object) {
@@ -277,6 +3253,9 @@ func testSave(o *orm.ORM, t *testing.T, obj *object.Object) {
 	if rowsAff == 0 {
 		t.Fatalf("rowsAff should not be zero")
 	}
+	if len(obj.ChangedFields()) != 0 {
+		t.Fatal("ChangedFields should be empty after Save")
+	}
 
 	dirtyTest(obj)
 }
@@ -303,6 +3282,239 @@ func testRetrieve(o *orm.ORM, t *testing.T, sch *schema.Schema) {
 	}
 }
 
+func testRetrieveColumns(o *orm.ORM, t *testing.T) {
+	queryVals := map[string]interface{}{
+		"PersonID": 1,
+	}
+	ctx, cancel := getDefaultContext()
+	joe, err := o.RetrieveColumns(ctx, mock.PeopleObjectType, []string{"PersonID", "Name"}, queryVals)
+	cancel()
+	fatalIf(err)
+	if joe == nil {
+		t.Fatal("expected to retrieve PersonID 1")
+	}
+	if len(joe.KV) != 2 {
+		t.Fatal("expected only the projected columns, got", joe.KV)
+	}
+	nameStr, err := joe.GetStringAlways("Name")
+	fatalIf(err)
+	if nameStr != "Joe" {
+		t.Fatal("unexpected name", nameStr)
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.RetrieveColumns(ctx, mock.PeopleObjectType, []string{"PersonID", "NoSuchColumn"}, queryVals)
+	cancel()
+	if err == nil {
+		t.Fatal("expected an error for an unknown column")
+	}
+}
+
+func testNULLRoundTrip(o *orm.ORM, t *testing.T) {
+	person := object.New(mock.PeopleObjectType)
+	person.Set("Name", "Nobody")
+	ctx, cancel := getDefaultContext()
+	_, err := o.Insert(ctx, nil, person)
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	retObj, err := o.Retrieve(ctx, mock.PeopleObjectType, map[string]interface{}{"PersonID": person.Get("PersonID")})
+	cancel()
+	fatalIf(err)
+	if retObj == nil {
+		t.Fatal("expected to retrieve the person")
+	}
+	// Every nullable column comes back as NULL, rather than as a zero value
+	// or not at all
+	for _, col := range []string{"NullText", "NullInt", "NullVarchar", "NullBlob"} {
+		if v := retObj.Get(col); !retObj.ValueIsNULL(v) {
+			t.Fatalf("expected %s to be NULL, got %#v", col, v)
+		}
+	}
+	if retObj.IsDirty() {
+		t.Fatal("expected the retrieved person to be clean")
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Delete(ctx, nil, retObj)
+	cancel()
+	fatalIf(err)
+}
+
+func testRetrieveNULL(o *orm.ORM, t *testing.T) {
+	for _, v := range []interface{}{nil, object.NewNULLValue()} {
+		ctx, cancel := getDefaultContext()
+		objs, err := o.RetrieveMany(ctx, mock.PeopleObjectType, map[string]interface{}{
+			"NullText": v,
+		})
+		cancel()
+		fatalIf(err)
+		if len(objs) == 0 {
+			t.Fatalf("expected people with a NULL NullText for query value %v", v)
+		}
+		for _, obj := range objs {
+			if !obj.ValueIsNULL(obj.Get("NullText")) {
+				t.Fatal("expected a NULL NullText, got", obj.Get("NullText"))
+			}
+		}
+	}
+
+	ctx, cancel := getDefaultContext()
+	objs, err := o.RetrieveMany(ctx, mock.PeopleObjectType, map[string]interface{}{
+		"NullText": "not null",
+	})
+	cancel()
+	fatalIf(err)
+	if len(objs) != 0 {
+		t.Fatal("expected no people with a non-NULL NullText, got", objs)
+	}
+}
+
+func testNullSemantics(o *orm.ORM, t *testing.T) {
+	for _, v := range []interface{}{nil, object.NewNULLValue(), sql.NullString{}} {
+		queryVals := map[string]interface{}{"NullText": v}
+
+		ctx, cancel := getDefaultContext()
+		objs, err := o.RetrieveMany(ctx, mock.PeopleObjectType, queryVals, orm.WithNullSemantics(orm.NullMatchesNull))
+		cancel()
+		fatalIf(err)
+		if len(objs) == 0 {
+			t.Fatalf("expected NullMatchesNull to match NULL NullText for query value %v", v)
+		}
+
+		ctx, cancel = getDefaultContext()
+		objs, err = o.RetrieveMany(ctx, mock.PeopleObjectType, queryVals, orm.WithNullSemantics(orm.NullStandard))
+		cancel()
+		fatalIf(err)
+		if len(objs) != 0 {
+			t.Fatalf("expected NullStandard to match nothing for query value %v, got %v", v, objs)
+		}
+
+		ctx, cancel = getDefaultContext()
+		obj, err := o.Retrieve(ctx, mock.PeopleObjectType, queryVals, orm.WithNullSemantics(orm.NullStandard))
+		cancel()
+		fatalIf(err)
+		if obj != nil {
+			t.Fatal("expected Retrieve with NullStandard to match nothing, got", obj)
+		}
+	}
+}
+
+func testRetrieveEach(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	all, err := o.RetrieveMany(ctx, mock.PeopleObjectType, map[string]interface{}{})
+	cancel()
+	fatalIf(err)
+	if len(all) < 2 {
+		t.Fatal("expected at least two people, got", len(all))
+	}
+
+	seen := 0
+	ctx, cancel = getDefaultContext()
+	err = o.RetrieveEach(ctx, mock.PeopleObjectType, map[string]interface{}{}, func(obj *object.Object) error {
+		if obj.Type != mock.PeopleObjectType || obj.IsDirty() {
+			return errors.New("unexpected object state")
+		}
+		seen++
+		return nil
+	})
+	cancel()
+	fatalIf(err)
+	if seen != len(all) {
+		t.Fatal("expected RetrieveEach to visit", len(all), "rows, visited", seen)
+	}
+
+	// Stopping early returns the callback's error and releases the rows
+	errStop := errors.New("stop")
+	seen = 0
+	ctx, cancel = getDefaultContext()
+	err = o.RetrieveEach(ctx, mock.PeopleObjectType, map[string]interface{}{}, func(obj *object.Object) error {
+		seen++
+		return errStop
+	})
+	cancel()
+	if err != errStop || seen != 1 {
+		t.Fatal("expected RetrieveEach to stop after the first row, got", err, seen)
+	}
+
+	ctx, cancel = getDefaultContext()
+	again, err := o.RetrieveMany(ctx, mock.PeopleObjectType, map[string]interface{}{})
+	cancel()
+	fatalIf(err)
+	if len(again) != len(all) {
+		t.Fatal("expected the same rows after an early stop")
+	}
+}
+
+func testExists(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	found, err := o.Exists(ctx, mock.PeopleObjectType, map[string]interface{}{"Name": "Joe"})
+	cancel()
+	fatalIf(err)
+	if !found {
+		t.Fatal("expected a person named Joe to exist")
+	}
+
+	ctx, cancel = getDefaultContext()
+	found, err = o.Exists(ctx, mock.PeopleObjectType, map[string]interface{}{"Name": "Nobody"})
+	cancel()
+	fatalIf(err)
+	if found {
+		t.Fatal("did not expect a person named Nobody to exist")
+	}
+
+	ctx, cancel = getDefaultContext()
+	_, err = o.Exists(ctx, mock.PeopleObjectType, map[string]interface{}{"NoSuchColumn": 1})
+	cancel()
+	if err == nil {
+		t.Fatal("expected an error for an unknown column")
+	}
+}
+
+func testCursor(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	all, err := o.RetrieveMany(ctx, mock.PeopleObjectType, map[string]interface{}{})
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	c, err := o.OpenCursor(ctx, mock.PeopleObjectType, map[string]interface{}{})
+	fatalIf(err)
+	seen := 0
+	for c.Next() {
+		obj := c.Object()
+		if obj == nil || obj.Type != mock.PeopleObjectType || obj.IsDirty() {
+			t.Fatal("unexpected cursor object", obj)
+		}
+		if c.Object() != obj {
+			t.Fatal("expected Object to return the same object until Next")
+		}
+		seen++
+	}
+	fatalIf(c.Err())
+	fatalIf(c.Close())
+	cancel()
+	if seen != len(all) {
+		t.Fatal("expected the cursor to visit", len(all), "rows, visited", seen)
+	}
+
+	// A cancelled context ends the iteration with the context's error
+	ctx, cancel = getDefaultContext()
+	c, err = o.OpenCursor(ctx, mock.PeopleObjectType, map[string]interface{}{})
+	fatalIf(err)
+	if !c.Next() {
+		t.Fatal("expected at least one row")
+	}
+	cancel()
+	for c.Next() {
+	}
+	if c.Err() != context.Canceled {
+		t.Fatal("expected context.Canceled, got", c.Err())
+	}
+	fatalIf(c.Close())
+}
+
 func testRetrieveMany(o *orm.ORM, t *testing.T, rootTable string) {
 	// insert another object
 	nobj := object.New(rootTable)
@@ -348,6 +3560,52 @@ func testRetrieveMany(o *orm.ORM, t *testing.T, rootTable string) {
 	}
 }
 
+func testRetrieveJoined(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	people, err := o.RetrieveJoined(ctx, mock.PeopleObjectType, map[string]interface{}{})
+	cancel()
+	fatalIf(err)
+
+	ctx, cancel = getDefaultContext()
+	all, err := o.RetrieveMany(ctx, mock.PeopleObjectType, map[string]interface{}{})
+	cancel()
+	fatalIf(err)
+	if len(people) != len(all) {
+		t.Fatal("expected", len(all), "people, got", len(people))
+	}
+
+	withAddresses := 0
+	for _, person := range people {
+		ctx, cancel := getDefaultContext()
+		addrs, err := o.RetrieveMany(ctx, mock.AddressesObjectType, map[string]interface{}{"PersonID": person.Get("PersonID")})
+		cancel()
+		fatalIf(err)
+		joined := person.Children[mock.AddressesObjectType]
+		if len(joined) != len(addrs) {
+			t.Fatal("expected", len(addrs), "addresses for person", person.Get("PersonID"), "got", len(joined))
+		}
+		for _, addr := range joined {
+			if addr.Get("PersonID") != person.Get("PersonID") || addr.IsDirty() {
+				t.Fatal("unexpected joined address", addr.KV)
+			}
+		}
+		if len(joined) > 0 {
+			withAddresses++
+		}
+	}
+	if withAddresses == 0 {
+		t.Fatal("expected at least one person with addresses")
+	}
+
+	ctx, cancel = getDefaultContext()
+	people, err = o.RetrieveJoined(ctx, mock.PeopleObjectType, map[string]interface{}{"PersonID": 1})
+	cancel()
+	fatalIf(err)
+	if len(people) != 1 || people[0].Get("PersonID") != int64(1) {
+		t.Fatal("expected only PersonID 1", people)
+	}
+}
+
 func testGetParentsViaChild(o *orm.ORM, t *testing.T) {
 	// Configure our database query
 	queryVals := make(map[string]interface{})
@@ -384,6 +3642,44 @@ func testGetParentsViaChild(o *orm.ORM, t *testing.T) {
 	}
 }
 
+func testFleshenParents(o *orm.ORM, t *testing.T) {
+	ctx, cancel := getDefaultContext()
+	addr, err := o.Retrieve(ctx, "addresses", map[string]interface{}{"PersonID": 1})
+	cancel()
+	fatalIf(err)
+	if addr == nil {
+		t.Fatal("expected to retrieve an address")
+	}
+	if addr.Parents != nil {
+		t.Fatal("expected a retrieved address to have no parents yet")
+	}
+
+	ctx, cancel = getDefaultContext()
+	err = o.FleshenParents(ctx, addr)
+	cancel()
+	fatalIf(err)
+
+	people := addr.Parents[mock.PeopleObjectType]
+	if len(people) != 1 {
+		t.Fatal("expected the address to have one person, got", addr.Parents)
+	}
+	nameStr, err := people[0].GetStringAlways("Name")
+	fatalIf(err)
+	if id, _ := people[0].GetIntAlways("PersonID"); id != 1 || nameStr != "Joe" {
+		t.Fatal("expected person 1 (Joe), got", people[0])
+	}
+
+	// An address without a person has no parent entry
+	orphan := object.New("addresses")
+	ctx, cancel = getDefaultContext()
+	err = o.FleshenParents(ctx, orphan)
+	cancel()
+	fatalIf(err)
+	if _, ok := orphan.Parents[mock.PeopleObjectType]; ok {
+		t.Fatal("expected no people for an address without a PersonID, got", orphan.Parents)
+	}
+}
+
 func testFleshenChildren(o *orm.ORM, t *testing.T, rootTable string) {
 	ctx, cancel := getDefaultContext()
 	obj, err := o.Retrieve(ctx, rootTable, map[string]interface{}{
@@ -13,3 +13,18 @@ func Begin(name string) string {
 func Commit() string {
 	return "COMMIT"
 }
+
+// Savepoint renders a SAVEPOINT statement
+func Savepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// RollbackToSavepoint renders a ROLLBACK TO SAVEPOINT statement
+func RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ReleaseSavepoint renders a RELEASE SAVEPOINT statement
+func ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
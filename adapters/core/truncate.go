@@ -0,0 +1,14 @@
+package core
+
+import (
+	"errors"
+)
+
+// Truncate renders a TRUNCATE TABLE statement. Cascading truncates are not
+// portable, so dialects that support them override this.
+func Truncate(name string, cascade bool) (string, error) {
+	if cascade {
+		return "", errors.New("dyndao: TRUNCATE ... CASCADE is not supported by this database")
+	}
+	return "TRUNCATE TABLE " + name, nil
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func TestBindingInsertUint64(t *testing.T) {
+	g := New()
+	sch := mock.WidgetSchema()
+	large := uint64(math.MaxInt64) + 1
+
+	_, bindArgs, err := BindingInsert(g, sch, "widgets", map[string]interface{}{"Age": uint64(42)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := bindArgs[0].(int64); !ok || v != 42 {
+		t.Fatalf("expected a uint64 within range to be bound as an int64, got %#v", bindArgs[0])
+	}
+
+	_, _, err = BindingInsert(g, sch, "widgets", map[string]interface{}{"Age": large})
+	if err == nil || !strings.Contains(err.Error(), "exceeds the range") {
+		t.Fatal("expected an error for a uint64 above math.MaxInt64 in an integer column, got", err)
+	}
+	rows := []map[string]interface{}{{"Age": 1}, {"Age": large}}
+	if _, _, err := BindingBulkInsert(g, sch, "widgets", rows); err == nil {
+		t.Fatal("expected BindingBulkInsert to refuse a uint64 above math.MaxInt64")
+	}
+
+	_, bindArgs, err = BindingInsert(g, sch, "widgets", map[string]interface{}{"Price": uint64(math.MaxUint64)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bindArgs[0] != "18446744073709551615.00" {
+		t.Fatalf("expected a decimal string for a decimal column, got %#v", bindArgs[0])
+	}
+
+	sch.GetTable("widgets").Columns["Age"].DBType = "BIGINT UNSIGNED"
+	_, bindArgs, err = BindingInsert(g, sch, "widgets", map[string]interface{}{"Age": large})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := bindArgs[0].(uint64); !ok || v != large {
+		t.Fatalf("expected a uint64 to be bound as is in an unsigned column, got %#v", bindArgs[0])
+	}
+}
+
+func TestBindingUpdateUint64(t *testing.T) {
+	g := New()
+	g.IsTimestampType = func(string) bool { return false }
+	sch := mock.WidgetSchema()
+	large := uint64(math.MaxInt64) + 1
+
+	obj := object.New("widgets")
+	obj.Set("WidgetID", 1)
+	obj.Set("Age", large)
+	if _, _, _, err := BindingUpdate(g, sch, obj); err == nil || !strings.Contains(err.Error(), "exceeds the range") {
+		t.Fatal("expected an error for a uint64 above math.MaxInt64 in an integer column, got", err)
+	}
+
+	sch.GetTable("widgets").Columns["Age"].DBType = "BIGINT UNSIGNED"
+	_, bindArgs, _, err := BindingUpdate(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := bindArgs[0].(uint64); !ok || v != large {
+		t.Fatalf("expected a uint64 to be bound as is in an unsigned column, got %#v", bindArgs[0])
+	}
+}
@@ -40,6 +40,19 @@ func sqlValueConvert(arg interface{}) (string, bool) {
 	}
 }
 
+// decimalConvert renders a value for a decimal column as an exact decimal
+// string, so that it is not rounded through a float on its way to the database.
+func decimalConvert(f *schema.Column, arg interface{}) (interface{}, error) {
+	d, ok, err := f.DecimalString(arg)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return arg, nil
+	}
+	return d, nil
+}
+
 func safeConvert(arg interface{}) time.Time {
 	switch t := arg.(type) {
 	case string:
@@ -69,12 +82,11 @@ func BindingUpdate(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object) (
 		return "", nil, nil, errors.New("BindingUpdate: Column map unavailable for table " + obj.Type)
 	}
 
-	whereClause, bindWhere, err := g.RenderUpdateWhereClause(g, schTbl, fieldsMap, obj)
-	if err != nil {
-		return "", nil, nil, err
-	}
-
+	var err error
 	i := 0
+	// bound counts the values actually bound, NULLs and SQLValues are
+	// rendered inline.
+	bound := 0
 
 	var bindArgs []interface{}
 	var newValuesAry []string
@@ -91,7 +103,7 @@ func BindingUpdate(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object) (
 			if f == nil {
 				return "", nil, nil, errors.New("BindingUpdate: field config unavailable for object Type: " + obj.Type + ", key: " + k)
 			}
-			if f.IsIdentity {
+			if f.IsIdentity || f.IsReadOnly() {
 				continue
 			}
 			v := obj.KV[k]
@@ -101,15 +113,26 @@ func BindingUpdate(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object) (
 				newValuesAry[i] = fmt.Sprintf("%s = %s", f.Name, vStr)
 				bindArgs[i] = nil
 			} else {
-				if g.IsTimestampType(schTbl.GetColumn(k).DBType) {
+				if v != nil && g.IsTimestampType(g.ColumnDBType(g, f)) {
 					v = safeConvert(v)
 				}
+				if v != nil && f.IsDecimal() {
+					if v, err = decimalConvert(f, v); err != nil {
+						return "", nil, nil, err
+					}
+				}
+				if n, ok := v.(uint64); ok {
+					if v, err = f.Uint64Value(n); err != nil {
+						return "", nil, nil, errors.New("BindingUpdate: " + err.Error())
+					}
+				}
 				if v == nil || zeroTime(v) {
 					newValuesAry[i] = fmt.Sprintf("%s = NULL", f.Name)
 					bindArgs[i] = nil
 				} else {
-					newValuesAry[i] = fmt.Sprintf("%s = %s", f.Name, g.RenderBindingValueWithInt(f, int64(i)))
+					newValuesAry[i] = fmt.Sprintf("%s = %s", f.Name, g.Placeholder(bound, numberedBindName(f.Name, i)))
 					bindArgs[i] = v
+					bound++
 				}
 			}
 			i++
@@ -118,16 +141,15 @@ func BindingUpdate(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object) (
 		// An update where it's not explicitly clear that anything has changed should
 		// just set every field we have available.
 
-		// - 1 because we expect to provide the identity field separately
-		bindArgs = make([]interface{}, len(obj.KV)-1)
-		newValuesAry = make([]string, len(obj.KV)-1)
+		bindArgs = make([]interface{}, len(obj.KV))
+		newValuesAry = make([]string, len(obj.KV))
 
 		for k, v := range obj.KV {
 			f := schTbl.GetColumn(k)
 			if f == nil {
 				return "", nil, nil, errors.New("BindingUpdate: field config unavailable for object Type: " + obj.Type + ", key: " + k)
 			}
-			if f.IsIdentity {
+			if f.IsIdentity || f.IsReadOnly() {
 				continue
 			}
 
@@ -136,24 +158,51 @@ func BindingUpdate(g *sg.SQLGenerator, sch *schema.Schema, obj *object.Object) (
 				newValuesAry[i] = fmt.Sprintf("%s = %s", f.Name, vStr)
 				bindArgs[i] = nil
 			} else {
-				if g.IsTimestampType(schTbl.GetColumn(k).DBType) {
+				if v != nil && g.IsTimestampType(g.ColumnDBType(g, f)) {
 					v = safeConvert(v)
 				}
+				if v != nil && f.IsDecimal() {
+					if v, err = decimalConvert(f, v); err != nil {
+						return "", nil, nil, err
+					}
+				}
+				if n, ok := v.(uint64); ok {
+					if v, err = f.Uint64Value(n); err != nil {
+						return "", nil, nil, errors.New("BindingUpdate: " + err.Error())
+					}
+				}
 				if v == nil || zeroTime(v) {
 					newValuesAry[i] = fmt.Sprintf("%s = NULL", f.Name)
 					bindArgs[i] = nil
 				} else {
-					newValuesAry[i] = fmt.Sprintf("%s = %s%d", f.Name, g.RenderBindingValue(f), i)
+					newValuesAry[i] = fmt.Sprintf("%s = %s", f.Name, g.Placeholder(bound, numberedBindName(f.Name, i)))
 					bindArgs[i] = v
+					bound++
 				}
 			}
 
 			i++
 		}
 	}
-	bindArgs = nils.RemoveNilsIfNeeded(bindArgs)
+	// Skipped columns leave unused entries at the end
+	newValuesAry = newValuesAry[:i]
+	if len(newValuesAry) == 0 {
+		return "", nil, nil, errors.New("BindingUpdate: no writable columns to update for object Type: " + obj.Type)
+	}
+	bindArgs = nils.RemoveNilsIfNeeded(bindArgs[:i])
+
+	// Number the WHERE clause's placeholders on from the SET clause's, so
+	// that positional bind names don't repeat
+	whereGen := *g
+	whereGen.Placeholder = func(index int, name string) string {
+		return g.Placeholder(bound+index, name)
+	}
+	whereClause, bindWhere, err := g.RenderUpdateWhereClause(&whereGen, schTbl, fieldsMap, obj)
+	if err != nil {
+		return "", nil, nil, err
+	}
 
-	tableName := schema.GetTableName(schTbl.Name, obj.Type)
+	tableName := sch.QualifiedTableName(schTbl.Name, obj.Type, g.QuoteIdentifier)
 	sqlStr := fmt.Sprintf("UPDATE %s SET %s WHERE %s", tableName, strings.Join(newValuesAry, ","), whereClause)
 	return sqlStr, bindArgs, bindWhere, nil
 }
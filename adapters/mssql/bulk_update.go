@@ -0,0 +1,35 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbastic/dyndao/adapters/common"
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BulkUpdateSQL renders a bulk update through a #temporary table, created
+// with the columns' types rather than with SELECT INTO, which would copy the
+// IDENTITY property of the key and refuse the keys loaded into it.
+func BulkUpdateSQL(g *sg.SQLGenerator, schTable *schema.Table, tableName string, tempName string, keyCols []string, setCols []string) *sg.BulkUpdate {
+	tempName = "#" + tempName
+	cols := append(append([]string{}, keyCols...), setCols...)
+
+	defs := make([]string, len(cols))
+	for i, c := range cols {
+		defs[i] = c + " " + common.RenderColumnType(g, schTable.GetColumn(c), mapType)
+	}
+	sets := make([]string, len(setCols))
+	for i, c := range setCols {
+		sets[i] = fmt.Sprintf("%s.%s = %s.%s", tableName, c, tempName, c)
+	}
+	return &sg.BulkUpdate{
+		TempTable: tempName,
+		Create:    fmt.Sprintf("CREATE TABLE %s (%s)", tempName, strings.Join(defs, ", ")),
+		Update: fmt.Sprintf("UPDATE %s SET %s FROM %s INNER JOIN %s ON %s",
+			tableName, strings.Join(sets, ","), tableName, tempName, core.RenderKeyJoin(tableName, tempName, keyCols)),
+		Drop: "DROP TABLE " + tempName,
+	}
+}
@@ -7,6 +7,10 @@ import (
 )
 
 func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column) string {
+	if f.Generated != "" {
+		// SQL Server computed columns take their type from the expression
+		return f.Name + " AS (" + f.Generated + ")"
+	}
 	return common.RenderCreateColumn(sg, f, "IDENTITY", mapType)
 }
 
@@ -23,3 +27,15 @@ func mapType(s string) string {
 	}
 	return s
 }
+
+// LogicalTypes maps schema logical types to SQL Server column types.
+var LogicalTypes = map[string]string{
+	schema.LogicalString:    "VARCHAR",
+	schema.LogicalText:      "TEXT",
+	schema.LogicalInt:       "INT",
+	schema.LogicalFloat:     "FLOAT",
+	schema.LogicalBlob:      "IMAGE",
+	schema.LogicalTimestamp: "DATETIME2",
+	schema.LogicalBool:      "BIT",
+	schema.LogicalDecimal:   "DECIMAL",
+}
@@ -0,0 +1,19 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeleteReturningSQL renders a DELETE which outputs the deleted rows.
+func DeleteReturningSQL(tableName string, whereClause string, columnNames []string) string {
+	output := make([]string, len(columnNames))
+	for i, c := range columnNames {
+		output[i] = "DELETED." + c
+	}
+	whereString := "WHERE"
+	if whereClause == "" {
+		whereString = ""
+	}
+	return fmt.Sprintf("DELETE FROM %s OUTPUT %s %s %s", tableName, strings.Join(output, ","), whereString, whereClause)
+}
@@ -0,0 +1,38 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// BindingInsertSQL renders an INSERT which outputs the generated identity as a
+// single row result, since the SQL Server driver doesn't support
+// LastInsertId.
+func BindingInsertSQL(schTable *schema.Table, tableName string, colNames []string, bindNames []string, identityCol string) string {
+	if !schTable.UsesLastInsertID() {
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			tableName,
+			strings.Join(colNames, ","),
+			strings.Join(bindNames, ","))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) OUTPUT INSERTED.%s VALUES (%s)",
+		tableName,
+		strings.Join(colNames, ","),
+		identityCol,
+		strings.Join(bindNames, ","))
+}
+
+// BindingBulkInsertSQL renders a multi-row INSERT for tables whose keys are
+// known up front. SQL Server does not promise to allocate identities, nor to
+// OUTPUT them, in the order of the VALUES list, so the generated keys could
+// not be matched back to their rows: the rows of other tables are inserted
+// one at a time.
+func BindingBulkInsertSQL(schTable *schema.Table, tableName string, colNames []string, rowBindNames [][]string, identityCol string) string {
+	if schTable.UsesLastInsertID() {
+		return ""
+	}
+	return core.BindingBulkInsertSQL(schTable, tableName, colNames, rowBindNames, identityCol)
+}
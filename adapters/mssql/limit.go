@@ -0,0 +1,21 @@
+package mssql
+
+import (
+	"fmt"
+)
+
+// RenderLimit renders an OFFSET / FETCH clause. SQL Server only accepts it
+// after an ORDER BY, so one is supplied when the query has none.
+func RenderLimit(hasOrderBy bool, limit int64, offset int64) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	s := fmt.Sprintf("OFFSET %d ROWS", offset)
+	if !hasOrderBy {
+		s = "ORDER BY (SELECT NULL) " + s
+	}
+	if limit > 0 {
+		s += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return s
+}
@@ -11,11 +11,30 @@ import (
 // or hardly any.
 func New(g *sg.SQLGenerator) *sg.SQLGenerator {
 	g.FixLastInsertIDbug = false
+	g.InsertOutputsPK = true
+	g.BindingInsertSQL = sg.FnBindingInsertSQL(BindingInsertSQL)
+	g.BindingBulkInsertSQL = sg.FnBindingBulkInsertSQL(BindingBulkInsertSQL)
+	g.DeleteReturningSQL = sg.FnDeleteReturningSQL(DeleteReturningSQL)
+	g.BulkUpdateSQL = sg.FnBulkUpdateSQL(BulkUpdateSQL)
 	g.IsStringType = sg.FnIsStringType(IsStringType)
 	g.IsNumberType = sg.FnIsNumberType(IsNumberType)
 	g.IsFloatingType = sg.FnIsFloatingType(IsFloatingType)
 	g.IsTimestampType = sg.FnIsTimestampType(IsTimestampType)
 	g.IsLOBType = sg.FnIsLOBType(IsLOBType)
+	g.IsBooleanType = sg.FnIsBooleanType(IsBooleanType)
+	g.IsBinaryType = sg.FnIsBinaryType(IsBinaryType)
+	g.IsDecimalType = sg.FnIsDecimalType(IsDecimalType)
 	g.RenderCreateColumn = sg.FnRenderCreateColumn(RenderCreateColumn)
+	g.AddColumn = sg.FnAddColumn(AddColumn)
+	g.LogicalTypes = LogicalTypes
+	g.RenderLimit = sg.FnRenderLimit(RenderLimit)
+	g.QuoteIdentifier = sg.FnQuoteIdentifier(QuoteIdentifier)
+	g.Savepoint = sg.FnSavepoint(Savepoint)
+	g.RollbackToSavepoint = sg.FnSavepoint(RollbackToSavepoint)
+	g.ReleaseSavepoint = sg.FnSavepoint(ReleaseSavepoint)
+	g.IsRetryable = sg.FnIsRetryable(IsRetryable)
+	if err := g.RegisterDBTypes(DBTypes); err != nil {
+		panic(err)
+	}
 	return g
 }
@@ -0,0 +1,10 @@
+package mssql
+
+import (
+	"strings"
+)
+
+// QuoteIdentifier quotes a table or column name with square brackets.
+func QuoteIdentifier(name string) string {
+	return "[" + strings.Replace(name, "]", "]]", -1) + "]"
+}
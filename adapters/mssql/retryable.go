@@ -0,0 +1,24 @@
+package mssql
+
+import (
+	"errors"
+	"strings"
+)
+
+// IsRetryable reports whether err chose the transaction as a deadlock victim
+// (1205) or is a snapshot isolation update conflict (3960), from the
+// driver's error number or, failing that, its message.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var ne interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &ne) {
+		switch ne.SQLErrorNumber() {
+		case 1205, 3960:
+			return true
+		}
+		return false
+	}
+	return strings.Contains(err.Error(), "chosen as the deadlock victim")
+}
@@ -0,0 +1,33 @@
+package mssql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// numberedError is an error reporting its error number, as the driver's
+// do.
+type numberedError struct{ number int32 }
+
+func (e numberedError) Error() string         { return fmt.Sprintf("mssql: error %d", e.number) }
+func (e numberedError) SQLErrorNumber() int32 { return e.number }
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err       error
+		retryable bool
+	}{
+		{numberedError{1205}, true},
+		{numberedError{3960}, true},
+		{fmt.Errorf("Update: %w", numberedError{1205}), true},
+		{errors.New("mssql: Transaction (Process ID 52) was deadlocked on lock resources with another process and has been chosen as the deadlock victim. Rerun the transaction."), true},
+		{numberedError{2627}, false},
+		{errors.New("mssql: Invalid object name 'widgets'."), false},
+		{nil, false},
+	} {
+		if IsRetryable(tc.err) != tc.retryable {
+			t.Errorf("expected IsRetryable(%v) to be %v", tc.err, tc.retryable)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package mssql
+
+// Savepoint renders a SAVE TRANSACTION statement, SQL Server's savepoint
+func Savepoint(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+// RollbackToSavepoint renders a ROLLBACK TRANSACTION statement for the named
+// savepoint
+func RollbackToSavepoint(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+// ReleaseSavepoint renders nothing: SQL Server has no way to release a
+// savepoint, which lasts until the transaction ends.
+func ReleaseSavepoint(name string) string {
+	return ""
+}
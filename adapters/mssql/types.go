@@ -19,6 +19,12 @@ float, etc., we may need to get even more complex.
 	See NOTES file for a link to one of the resources used.
 */
 
+import (
+	"strings"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
 // TODO: Some of these are unicode types. Do we need to use and support runes instead
 // of string here?
 var stringTypes = map[string]bool{
@@ -65,7 +71,14 @@ var timestampTypes = map[string]bool{
 	"timestamp": true,
 	"TIMESTAMP": true,
 
-	// date, datetime, datetime2, datetimeoffset, smalldatetime, time
+	"datetime":  true,
+	"DATETIME":  true,
+	"datetime2": true,
+	"DATETIME2": true,
+	"date":      true,
+	"DATE":      true,
+
+	// datetimeoffset, smalldatetime, time
 }
 
 var lobTypes = map[string]bool{
@@ -112,3 +125,39 @@ func IsTimestampType(k string) bool {
 func IsLOBType(k string) bool {
 	return lobTypes[k]
 }
+
+// IsBooleanType can be used to help determine whether a certain data type is a boolean type.
+func IsBooleanType(k string) bool {
+	return k == "BIT" || k == "bit"
+}
+
+var binaryTypes = map[string]bool{
+	"IMAGE":     true,
+	"image":     true,
+	"BINARY":    true,
+	"binary":    true,
+	"VARBINARY": true,
+	"varbinary": true,
+}
+
+// IsBinaryType can be used to help determine whether a certain data type holds
+// binary data, which is scanned into a []byte.
+func IsBinaryType(k string) bool {
+	return binaryTypes[k]
+}
+
+// IsDecimalType can be used to help determine whether a certain data type is a
+// fixed precision decimal type, which is scanned into a string.
+func IsDecimalType(k string) bool {
+	k = strings.ToUpper(k)
+	return strings.HasPrefix(k, "DECIMAL") || strings.HasPrefix(k, "NUMERIC") || strings.HasPrefix(k, "MONEY") || strings.HasPrefix(k, "SMALLMONEY")
+}
+
+// DBTypes are the SQL Server column types registered with the generator in
+// New, on top of those the Is*Type functions know, see
+// SQLGenerator.RegisterDBType.
+var DBTypes = map[string]string{
+	"REAL":           schema.LogicalFloat,
+	"SMALLDATETIME":  schema.LogicalTimestamp,
+	"DATETIMEOFFSET": schema.LogicalTimestamp,
+}
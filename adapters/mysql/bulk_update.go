@@ -0,0 +1,25 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BulkUpdateSQL renders a bulk update as a multi-table UPDATE joining the
+// temporary table, which MySQL has in place of UPDATE ... FROM.
+func BulkUpdateSQL(g *sg.SQLGenerator, schTable *schema.Table, tableName string, tempName string, keyCols []string, setCols []string) *sg.BulkUpdate {
+	bu := core.BulkUpdateSQL(g, schTable, tableName, tempName, keyCols, setCols)
+
+	sets := make([]string, len(setCols))
+	for i, c := range setCols {
+		sets[i] = fmt.Sprintf("%s.%s = %s.%s", tableName, c, tempName, c)
+	}
+	bu.Update = fmt.Sprintf("UPDATE %s JOIN %s ON %s SET %s",
+		tableName, tempName, core.RenderKeyJoin(tableName, tempName, keyCols), strings.Join(sets, ","))
+	bu.Drop = "DROP TEMPORARY TABLE " + tempName
+	return bu
+}
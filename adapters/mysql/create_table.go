@@ -12,6 +12,13 @@ func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column) string {
 	if f.IsIdentity {
 		f.AllowNull = false
 	}
+	if len(f.AllowedValues) > 0 {
+		// A native ENUM enforces the values, so no CHECK is needed
+		enum := *f
+		enum.AllowedValues = nil
+		dataType := "ENUM(" + common.RenderValueList(sg, f.AllowedValues) + ")"
+		return common.RenderCreateColumnWithType(sg, &enum, "PRIMARY KEY AUTO_INCREMENT", dataType)
+	}
 
 	return common.RenderCreateColumn(sg, f, "PRIMARY KEY AUTO_INCREMENT", mapType)
 }
@@ -24,3 +31,15 @@ func mapType(s string) string {
 	// no need to map text type
 	return s
 }
+
+// LogicalTypes maps schema logical types to MySQL column types.
+var LogicalTypes = map[string]string{
+	schema.LogicalString:    "VARCHAR",
+	schema.LogicalText:      "TEXT",
+	schema.LogicalInt:       "INTEGER",
+	schema.LogicalFloat:     "DOUBLE",
+	schema.LogicalBlob:      "BLOB",
+	schema.LogicalTimestamp: "DATETIME",
+	schema.LogicalBool:      "TINYINT(1)",
+	schema.LogicalDecimal:   "DECIMAL",
+}
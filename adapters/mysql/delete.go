@@ -0,0 +1,9 @@
+package mysql
+
+// DeleteReturningSQL renders nothing: MySQL has no DELETE ... RETURNING, so
+// the rows are retrieved before they are deleted. MariaDB does have it, and
+// generators for MariaDB may set DeleteReturningSQL to
+// core.DeleteReturningSQL.
+func DeleteReturningSQL(tableName string, whereClause string, columnNames []string) string {
+	return ""
+}
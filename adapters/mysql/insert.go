@@ -0,0 +1,16 @@
+package mysql
+
+import (
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// BindingBulkInsertSQL renders a multi-row INSERT for tables whose keys are
+// known up front. MySQL cannot return the keys generated by a multi-row
+// INSERT, so the rows of other tables are inserted one at a time.
+func BindingBulkInsertSQL(schTable *schema.Table, tableName string, colNames []string, rowBindNames [][]string, identityCol string) string {
+	if schTable.UsesLastInsertID() {
+		return ""
+	}
+	return core.BindingBulkInsertSQL(schTable, tableName, colNames, rowBindNames, identityCol)
+}
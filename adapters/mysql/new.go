@@ -14,6 +14,20 @@ func New(g *sg.SQLGenerator) *sg.SQLGenerator {
 	g.IsFloatingType = sg.FnIsFloatingType(IsFloatingType)
 	g.IsTimestampType = sg.FnIsTimestampType(IsTimestampType)
 	g.IsLOBType = sg.FnIsLOBType(IsLOBType)
+	g.IsBooleanType = sg.FnIsBooleanType(IsBooleanType)
+	g.IsBinaryType = sg.FnIsBinaryType(IsBinaryType)
+	g.IsDecimalType = sg.FnIsDecimalType(IsDecimalType)
 	g.RenderCreateColumn = sg.FnRenderCreateColumn(RenderCreateColumn)
+	g.LogicalTypes = LogicalTypes
+	g.BindingTableExists = sg.FnBindingTableExists(BindingTableExists)
+	g.QuoteIdentifier = sg.FnQuoteIdentifier(QuoteIdentifier)
+	g.QuoteLiteral = sg.FnQuoteLiteral(QuoteLiteral)
+	g.BindingBulkInsertSQL = sg.FnBindingBulkInsertSQL(BindingBulkInsertSQL)
+	g.DeleteReturningSQL = sg.FnDeleteReturningSQL(DeleteReturningSQL)
+	g.BulkUpdateSQL = sg.FnBulkUpdateSQL(BulkUpdateSQL)
+	g.IsRetryable = sg.FnIsRetryable(IsRetryable)
+	if err := g.RegisterDBTypes(DBTypes); err != nil {
+		panic(err)
+	}
 	return g
 }
@@ -0,0 +1,17 @@
+package mysql
+
+import (
+	"strings"
+)
+
+// QuoteIdentifier quotes a table or column name with backticks.
+func QuoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// QuoteLiteral renders s as a string literal. MySQL treats backslashes in
+// literals as escapes unless NO_BACKSLASH_ESCAPES is set, so they are doubled
+// along with single quotes.
+func QuoteLiteral(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s) + "'"
+}
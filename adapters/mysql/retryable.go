@@ -0,0 +1,26 @@
+package mysql
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// errorNumber matches the error numbers of the driver's messages, such as
+// "Error 1213 (40001): Deadlock found when trying to get lock".
+var errorNumber = regexp.MustCompile(`Error (\d+)`)
+
+// IsRetryable reports whether err is a deadlock (1213) or a lock wait
+// timeout (1205). InnoDB rolls back the deadlocked transaction, which can
+// then be run again.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, m := range errorNumber.FindAllStringSubmatch(err.Error(), -1) {
+		switch n, _ := strconv.Atoi(m[1]); n {
+		case 1205, 1213:
+			return true
+		}
+	}
+	return false
+}
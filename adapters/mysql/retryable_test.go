@@ -0,0 +1,26 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+)
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err       error
+		retryable bool
+	}{
+		{&mysql.MySQLError{Number: 1213, SQLState: [5]byte{'4', '0', '0', '0', '1'}, Message: "Deadlock found when trying to get lock"}, true},
+		{&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, true},
+		{errors.Wrap(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, "Update"), true},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"}, false},
+		{errors.New("invalid connection"), false},
+		{nil, false},
+	} {
+		if IsRetryable(tc.err) != tc.retryable {
+			t.Errorf("expected IsRetryable(%v) to be %v", tc.err, tc.retryable)
+		}
+	}
+}
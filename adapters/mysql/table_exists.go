@@ -0,0 +1,12 @@
+package mysql
+
+import (
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingTableExists renders a query that returns a row if the named table
+// exists in the current database. MySQL's information_schema spans every
+// database on the server.
+func BindingTableExists(g *sg.SQLGenerator, name string) (string, []interface{}) {
+	return "SELECT 1 FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = " + g.Placeholder(0, "name"), []interface{}{name}
+}
@@ -1,5 +1,11 @@
 package mysql
 
+import (
+	"strings"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
 var stringTypes = map[string]bool{
 	"VARSTRING": true,
 	"VARCHAR2":  true,
@@ -25,6 +31,10 @@ var floatTypes = map[string]bool{
 var timestampTypes = map[string]bool{
 	"timestamp": true,
 	"TIMESTAMP": true,
+	"datetime":  true,
+	"DATETIME":  true,
+	"date":      true,
+	"DATE":      true,
 }
 
 var lobTypes = map[string]bool{
@@ -61,3 +71,61 @@ func IsTimestampType(k string) bool {
 func IsLOBType(k string) bool {
 	return lobTypes[k]
 }
+
+// IsBooleanType always returns false, since boolean columns are reported as
+// TINYINT. They are scanned as numbers and converted by the ORM according to
+// the schema.
+func IsBooleanType(k string) bool {
+	return false
+}
+
+var binaryTypes = map[string]bool{
+	"BLOB":       true,
+	"blob":       true,
+	"TINYBLOB":   true,
+	"tinyblob":   true,
+	"MEDIUMBLOB": true,
+	"mediumblob": true,
+	"LONGBLOB":   true,
+	"longblob":   true,
+	"BINARY":     true,
+	"binary":     true,
+	"VARBINARY":  true,
+	"varbinary":  true,
+}
+
+// IsBinaryType can be used to help determine whether a certain data type holds
+// binary data, which is scanned into a []byte.
+func IsBinaryType(k string) bool {
+	return binaryTypes[k]
+}
+
+// IsDecimalType can be used to help determine whether a certain data type is a
+// fixed precision decimal type, which is scanned into a string.
+func IsDecimalType(k string) bool {
+	k = strings.ToUpper(k)
+	return strings.HasPrefix(k, "DECIMAL") || strings.HasPrefix(k, "NUMERIC")
+}
+
+// DBTypes are the MySQL column types registered with the generator in New,
+// on top of those the Is*Type functions know, see
+// SQLGenerator.RegisterDBType.
+var DBTypes = map[string]string{
+	"TINYINT":            schema.LogicalInt,
+	"SMALLINT":           schema.LogicalInt,
+	"MEDIUMINT":          schema.LogicalInt,
+	"INTEGER":            schema.LogicalInt,
+	"BIGINT":             schema.LogicalInt,
+	"YEAR":               schema.LogicalInt,
+	"UNSIGNED TINYINT":   schema.LogicalInt,
+	"UNSIGNED SMALLINT":  schema.LogicalInt,
+	"UNSIGNED MEDIUMINT": schema.LogicalInt,
+	"UNSIGNED INT":       schema.LogicalInt,
+	"DOUBLE":             schema.LogicalFloat,
+	"REAL":               schema.LogicalFloat,
+	"TINYTEXT":           schema.LogicalText,
+	"MEDIUMTEXT":         schema.LogicalText,
+	"LONGTEXT":           schema.LogicalText,
+	"ENUM":               schema.LogicalString,
+	"SET":                schema.LogicalString,
+}
@@ -0,0 +1,13 @@
+package oracle
+
+import (
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// AddColumn renders the ALTER TABLE statement adding the column f. Oracle
+// wants the column definition in parentheses, ADD (...).
+func AddColumn(g *sg.SQLGenerator, s *schema.Schema, table string, f *schema.Column) (string, error) {
+	return core.AlterTable(g, s, table, "ADD ("+g.RenderCreateColumn(g, f)+")")
+}
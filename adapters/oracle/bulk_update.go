@@ -0,0 +1,13 @@
+package oracle
+
+import (
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BulkUpdateSQL renders nothing: Oracle's temporary tables must be created
+// ahead of time, and it has no UPDATE ... FROM, so rows are updated one at a
+// time.
+func BulkUpdateSQL(g *sg.SQLGenerator, schTable *schema.Table, tableName string, tempName string, keyCols []string, setCols []string) *sg.BulkUpdate {
+	return nil
+}
@@ -6,16 +6,17 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rbastic/dyndao/adapters/common"
 	"github.com/rbastic/dyndao/schema"
 	sg "github.com/rbastic/dyndao/sqlgen"
 )
 
 func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column) string {
-	dataType := f.DBType
+	dataType := sg.ColumnDBType(sg, f)
 	notNull := ""
 	identity := ""
 	unique := ""
-	if f.IsIdentity {
+	if f.IsIdentity || f.IsUUID {
 		identity = "PRIMARY KEY"
 	}
 	if f.AllowNull {
@@ -25,7 +26,9 @@ func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column) string {
 	}
 
 	dataType = mapType(dataType)
-	if f.Length > 0 {
+	if f.Precision > 0 {
+		dataType = fmt.Sprintf("%s(%d,%d)", dataType, f.Precision, f.Scale)
+	} else if f.Length > 0 {
 		dataType = fmt.Sprintf("%s(%d)", dataType, f.Length)
 	}
 	if f.IsUnique {
@@ -35,10 +38,13 @@ func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column) string {
 	if dataType == "" {
 		panic("Empty dataType in renderCreateColumn for " + f.Name)
 	}
-	if f.IsIdentity {
+	if f.IsIdentity && !f.IsUUID {
 		return strings.Join([]string{f.Name, dataType, "GENERATED ALWAYS AS IDENTITY"}, " ")
 	}
-	return strings.Join([]string{f.Name, dataType, identity, notNull, unique}, " ")
+	if f.Generated != "" {
+		return strings.Join([]string{f.Name, dataType, common.RenderGenerated(f.Generated), notNull, unique, common.RenderChecks(sg, f)}, " ")
+	}
+	return strings.Join([]string{f.Name, dataType, identity, common.RenderDefault(sg, f), notNull, unique, common.RenderChecks(sg, f)}, " ")
 }
 
 func mapType(s string) string {
@@ -53,5 +59,20 @@ func mapType(s string) string {
 	if s == "varchar" {
 		return "VARCHAR2"
 	}
+	if s == "datetime" {
+		return "TIMESTAMP"
+	}
 	return s
 }
+
+// LogicalTypes maps schema logical types to Oracle column types.
+var LogicalTypes = map[string]string{
+	schema.LogicalString:    "VARCHAR2",
+	schema.LogicalText:      "CLOB",
+	schema.LogicalInt:       "NUMBER",
+	schema.LogicalFloat:     "BINARY_DOUBLE",
+	schema.LogicalBlob:      "BLOB",
+	schema.LogicalTimestamp: "TIMESTAMP",
+	schema.LogicalBool:      "NUMBER(1)",
+	schema.LogicalDecimal:   "NUMBER",
+}
@@ -0,0 +1,7 @@
+package oracle
+
+// DeleteReturningSQL renders nothing: RETURNING ... INTO binds a single row,
+// so the rows are retrieved before they are deleted.
+func DeleteReturningSQL(tableName string, whereClause string, columnNames []string) string {
+	return ""
+}
@@ -6,32 +6,55 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/rbastic/dyndao/object"
 	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
 	"github.com/tidwall/gjson"
 )
 
+// BindingInsertSQL renders an INSERT, returning the generated identity into
+// a :identityCol bind.
 func BindingInsertSQL(schTable *schema.Table, tableName string, colNames []string, bindNames []string, identityCol string) string {
+	return bindingInsertSQL(schTable, tableName, colNames, bindNames, identityCol, ":"+identityCol)
+}
+
+// BindingInsertSQLWith returns a BindingInsertSQL whose RETURNING ... INTO
+// bind is rendered by g's Placeholder, see sqlgen.LastInsertIDBind, so that it
+// is named like the values are, such as :b2 with PositionalPlaceholder.
+func BindingInsertSQLWith(g *sg.SQLGenerator) sg.FnBindingInsertSQL {
+	return func(schTable *schema.Table, tableName string, colNames []string, bindNames []string, identityCol string) string {
+		into, _ := g.LastInsertIDBind(len(colNames), identityCol)
+		return bindingInsertSQL(schTable, tableName, colNames, bindNames, identityCol, into)
+	}
+}
+
+func bindingInsertSQL(schTable *schema.Table, tableName string, colNames []string, bindNames []string, identityCol string, into string) string {
 	var sqlStr string
-	if schTable.CallerSuppliesPK {
+	if !schTable.UsesLastInsertID() {
 		sqlStr = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
 			tableName,
 			strings.Join(colNames, ","),
 			strings.Join(bindNames, ","))
 	} else {
-		sqlStr = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s /*LASTINSERTID*/ INTO :%s",
+		sqlStr = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s /*LASTINSERTID*/ INTO %s",
 			tableName,
 			strings.Join(colNames, ","),
 			strings.Join(bindNames, ","),
 			identityCol,
-			identityCol)
+			into)
 	}
 	return sqlStr
 }
 
 func RenderInsertValue(f *schema.Column, value interface{}) (interface{}, error) {
 	// TODO do we need the schema.Column for more than debugging information?
+	if f.IsDecimal() {
+		if d, ok, err := f.DecimalString(value); ok {
+			return sql.Named(f.Name, d), err
+		}
+	}
 	switch value.(type) {
 	case string:
 		str, ok := value.(string)
@@ -49,8 +72,11 @@ func RenderInsertValue(f *schema.Column, value interface{}) (interface{}, error)
 		num := value.(int64)
 		return sql.Named(f.Name, num), nil
 	case uint64:
-		num := value.(uint64)
-		return sql.Named(f.Name, fmt.Sprintf("%d", num)), nil
+		v, err := f.Uint64Value(value.(uint64))
+		if err != nil {
+			return nil, err
+		}
+		return sql.Named(f.Name, v), nil
 	case float64:
 		num := value.(float64)
 		if f.IsNumber {
@@ -58,6 +84,19 @@ func RenderInsertValue(f *schema.Column, value interface{}) (interface{}, error)
 		}
 		// TODO: when we support more than regular integers, we'll need to care about this more
 		return sql.Named(f.Name, fmt.Sprintf("%f", num)), nil
+	case []byte:
+		return sql.Named(f.Name, value), nil
+	case bool:
+		// Oracle has no boolean column type, booleans are stored as NUMBER(1)
+		if value.(bool) {
+			return sql.Named(f.Name, 1), nil
+		}
+		return sql.Named(f.Name, 0), nil
+	case time.Time:
+		t := value.(time.Time)
+		return sql.Named(f.Name, t), nil
+	case *time.Time:
+		return sql.Named(f.Name, value), nil
 	case *object.SQLValue:
 		val := value.(*object.SQLValue)
 		return sql.Named(f.Name, val.String()), nil
@@ -70,3 +109,10 @@ func RenderInsertValue(f *schema.Column, value interface{}) (interface{}, error)
 		return "", fmt.Errorf("renderInsertValue: unknown type %v for the value of %s", reflect.TypeOf(value), f.Name)
 	}
 }
+
+// BindingBulkInsertSQL renders nothing: Oracle has no multi-row VALUES list,
+// and RETURNING ... INTO binds a single row, so bulk inserts are done one row
+// at a time.
+func BindingBulkInsertSQL(schTable *schema.Table, tableName string, colNames []string, rowBindNames [][]string, identityCol string) string {
+	return ""
+}
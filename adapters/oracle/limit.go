@@ -0,0 +1,17 @@
+package oracle
+
+import (
+	"fmt"
+)
+
+// RenderLimit renders an OFFSET / FETCH clause (Oracle 12c and later).
+func RenderLimit(hasOrderBy bool, limit int64, offset int64) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	s := fmt.Sprintf("OFFSET %d ROWS", offset)
+	if limit > 0 {
+		s += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return s
+}
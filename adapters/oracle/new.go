@@ -18,12 +18,30 @@ func New(g *sg.SQLGenerator) *sg.SQLGenerator {
 	g.IsFloatingType = sg.FnIsFloatingType(IsFloatingType)
 	g.IsTimestampType = sg.FnIsTimestampType(IsTimestampType)
 	g.IsLOBType = sg.FnIsLOBType(IsLOBType)
+	g.IsBooleanType = sg.FnIsBooleanType(IsBooleanType)
+	g.IsBinaryType = sg.FnIsBinaryType(IsBinaryType)
+	g.IsDecimalType = sg.FnIsDecimalType(IsDecimalType)
 	g.DynamicObjectSetter = sg.FnDynamicObjectSetter(DynamicObjectSetter)
 	g.MakeColumnPointers = sg.FnMakeColumnPointers(MakeColumnPointers)
 	g.RenderCreateColumn = sg.FnRenderCreateColumn(RenderCreateColumn)
+	g.AddColumn = sg.FnAddColumn(AddColumn)
+	g.LogicalTypes = LogicalTypes
+	g.BindingTableExists = sg.FnBindingTableExists(BindingTableExists)
+	g.Truncate = sg.FnTruncate(Truncate)
+	g.ReleaseSavepoint = sg.FnSavepoint(ReleaseSavepoint)
 	g.RenderInsertValue = sg.FnRenderInsertValue(RenderInsertValue)
-	g.BindingInsertSQL = sg.FnBindingInsertSQL(BindingInsertSQL)
+	g.BindingInsertSQL = BindingInsertSQLWith(g)
+	g.BindingBulkInsertSQL = sg.FnBindingBulkInsertSQL(BindingBulkInsertSQL)
+	g.DeleteReturningSQL = sg.FnDeleteReturningSQL(DeleteReturningSQL)
+	g.BulkUpdateSQL = sg.FnBulkUpdateSQL(BulkUpdateSQL)
+	g.Placeholder = sg.FnPlaceholder(Placeholder)
 	g.RenderBindingValue = sg.FnRenderBindingValue(RenderBindingValue)
 	g.RenderBindingValueWithInt = sg.FnRenderBindingValueWithInt(RenderBindingValueWithInt)
+	g.RenderLimit = sg.FnRenderLimit(RenderLimit)
+	g.QuoteIdentifier = sg.FnQuoteIdentifier(QuoteIdentifier)
+	g.IsRetryable = sg.FnIsRetryable(IsRetryable)
+	if err := g.RegisterDBTypes(DBTypes); err != nil {
+		panic(err)
+	}
 	return g
 }
@@ -0,0 +1,83 @@
+package oracle
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+	"github.com/rbastic/dyndao/schema/test/mock"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// levelSchema returns the people schema with a Level column, a reserved
+// word in Oracle.
+func levelSchema() *schema.Schema {
+	sch := mock.BasicSchema()
+	f := schema.DefaultColumn()
+	f.Name = "Level"
+	f.DBType = "NUMBER"
+	f.IsNumber = true
+	sch.GetTable("people").Columns["Level"] = f
+	return sch
+}
+
+func TestPositionalPlaceholder(t *testing.T) {
+	g := New(core.New())
+	g.Placeholder = sg.FnPlaceholder(PositionalPlaceholder)
+	sch := levelSchema()
+
+	sqlStr, bindArgs, err := g.BindingInsert(g, sch, "people", map[string]interface{}{"Level": 3, "Name": "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sqlStr, ":Level") || !strings.HasSuffix(sqlStr, "VALUES (:b0,:b1) RETURNING PersonID /*LASTINSERTID*/ INTO :b2") {
+		t.Fatal("expected positional bind names, got", sqlStr)
+	}
+	if len(bindArgs) != 3 {
+		t.Fatalf("expected two values and the out bind, got %#v", bindArgs)
+	}
+	for i, want := range []sql.NamedArg{sql.Named("b0", 3), sql.Named("b1", "Ada"), sql.Named("b2", sql.Out{})} {
+		if bindArgs[i] != want {
+			t.Fatalf("expected bind argument %d to be %#v, got %#v", i, want, bindArgs[i])
+		}
+	}
+
+	// The generated key is returned into a positional bind too, even when
+	// its column is a reserved word
+	people := sch.GetTable("people")
+	people.Columns["Level"].IsIdentity = true
+	people.Columns["PersonID"].IsIdentity = false
+	people.Primary = "Level"
+	sqlStr, bindArgs, err = g.BindingInsert(g, sch, "people", map[string]interface{}{"Name": "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(sqlStr, "VALUES (:b0) RETURNING Level /*LASTINSERTID*/ INTO :b1") {
+		t.Fatal("expected the RETURNING bind to follow the values, got", sqlStr)
+	}
+	if len(bindArgs) != 2 || bindArgs[1] != sql.Named("b1", sql.Out{}) {
+		t.Fatalf("expected the out bind to be named after its placeholder, got %#v", bindArgs)
+	}
+	people.Primary = "PersonID"
+	people.Columns["PersonID"].IsIdentity = true
+	people.Columns["Level"].IsIdentity = false
+
+	obj := object.New("people")
+	obj.Set("PersonID", 1)
+	obj.Set("Level", 4)
+	obj.ResetChangedColumns()
+	obj.Set("Level", 5)
+	sqlStr, bindArgs, bindWhere, err := g.BindingUpdate(g, sch, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sqlStr, "SET Level = :b0 WHERE PersonID = :b1") {
+		t.Fatal("expected the WHERE clause to be numbered after the SET clause, got", sqlStr)
+	}
+	if len(bindArgs) != 1 || bindArgs[0] != 5 || len(bindWhere) != 1 || bindWhere[0] != 1 {
+		t.Fatalf("expected the bind arguments in placeholder order, got %#v %#v", bindArgs, bindWhere)
+	}
+}
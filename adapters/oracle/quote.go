@@ -0,0 +1,8 @@
+package oracle
+
+// QuoteIdentifier returns name unchanged. Tables are created with unquoted
+// identifiers, which Oracle folds to upper case, so quoting the mixed case
+// schema names would no longer match them.
+func QuoteIdentifier(name string) string {
+	return name
+}
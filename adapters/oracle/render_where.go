@@ -6,10 +6,28 @@ import (
 	"github.com/rbastic/dyndao/schema"
 )
 
+// Placeholder renders a :name bind variable. Oracle binds by name, so the
+// index of the bind argument is ignored.
+func Placeholder(index int, name string) string {
+	return ":" + name
+}
+
+// PositionalPlaceholder renders :b0, :b1 and so on by the index of the bind
+// argument, rather than by column. Column names that are reserved words,
+// such as Level or Date, make for invalid bind variables (ORA-01745), so a
+// schema with such columns should use it:
+//
+//	g.Placeholder = sg.FnPlaceholder(oracle.PositionalPlaceholder)
+func PositionalPlaceholder(index int, name string) string {
+	return fmt.Sprintf(":b%d", index)
+}
+
+// RenderBindingValue is deprecated, use the generator's Placeholder.
 func RenderBindingValue(f *schema.Column) string {
-	return ":" + f.Name
+	return Placeholder(0, f.Name)
 }
 
+// RenderBindingValueWithInt is deprecated, use the generator's Placeholder.
 func RenderBindingValueWithInt(f *schema.Column, i int64) string {
-	return fmt.Sprintf(":%s%d", f.Name, i)
+	return Placeholder(int(i), fmt.Sprintf("%s%d", f.Name, i))
 }
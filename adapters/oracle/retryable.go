@@ -0,0 +1,13 @@
+package oracle
+
+import "strings"
+
+// IsRetryable reports whether err is a deadlock (ORA-00060) or a
+// serialization failure (ORA-08177).
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "ORA-00060") || strings.Contains(msg, "ORA-08177")
+}
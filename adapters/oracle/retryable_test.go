@@ -0,0 +1,25 @@
+package oracle
+
+import (
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err       error
+		retryable bool
+	}{
+		{errors.New("ORA-00060: deadlock detected while waiting for resource"), true},
+		{errors.New("ORA-08177: can't serialize access for this transaction"), true},
+		{pkgerrors.Wrap(errors.New("ORA-00060: deadlock detected while waiting for resource"), "Update"), true},
+		{errors.New("ORA-00001: unique constraint (HR.PK_WIDGETS) violated"), false},
+		{nil, false},
+	} {
+		if IsRetryable(tc.err) != tc.retryable {
+			t.Errorf("expected IsRetryable(%v) to be %v", tc.err, tc.retryable)
+		}
+	}
+}
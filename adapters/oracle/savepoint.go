@@ -0,0 +1,7 @@
+package oracle
+
+// ReleaseSavepoint renders nothing: Oracle has no RELEASE SAVEPOINT, and
+// savepoints simply last until the transaction ends.
+func ReleaseSavepoint(name string) string {
+	return ""
+}
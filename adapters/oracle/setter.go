@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/adapters/common"
 	"github.com/rbastic/dyndao/object"
 	sg "github.com/rbastic/dyndao/sqlgen"
 	"gopkg.in/goracle.v2"
@@ -22,6 +23,11 @@ func (l *LobDST) Scan(src interface{}) error {
 		return nil
 	}
 
+	if b, ok := src.([]byte); ok {
+		*l = LobDST(b)
+		return nil
+	}
+
 	lob, ok := src.(*goracle.Lob)
 	if !ok {
 		return fmt.Errorf("LobDST can only be used with goracle.Lib, type was %v", reflect.TypeOf(src))
@@ -44,9 +50,26 @@ func DynamicObjectSetter(s *sg.SQLGenerator, columnNames []string, columnPointer
 		ct := columnTypes[i]
 
 		typeName := ct.DatabaseTypeName()
-		if s.IsTimestampType(typeName) {
-			val := v.(*time.Time)
-			obj.Set(columnNames[i], *val)
+		if common.IsDecimalColumn(s, ct) {
+			val := v.(*sql.NullString)
+			if val.Valid {
+				obj.Set(columnNames[i], val.String)
+			} else {
+				obj.SetNull(columnNames[i])
+			}
+		} else if s.IsTimestampType(typeName) {
+			nullable, _ := ct.Nullable()
+			if nullable {
+				val := v.(*sql.NullTime)
+				if val.Valid {
+					obj.Set(columnNames[i], val.Time)
+				} else {
+					obj.SetNull(columnNames[i])
+				}
+			} else {
+				val := v.(*time.Time)
+				obj.Set(columnNames[i], *val)
+			}
 		} else if s.IsStringType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
@@ -63,6 +86,8 @@ func DynamicObjectSetter(s *sg.SQLGenerator, columnNames []string, columnPointer
 				val := v.(*sql.NullInt64)
 				if val.Valid {
 					obj.Set(columnNames[i], val.Int64)
+				} else {
+					obj.SetNull(columnNames[i])
 				}
 			} else {
 				val := v.(*int64)
@@ -74,6 +99,8 @@ func DynamicObjectSetter(s *sg.SQLGenerator, columnNames []string, columnPointer
 				val := v.(*sql.NullFloat64)
 				if val.Valid {
 					obj.Set(columnNames[i], val.Float64)
+				} else {
+					obj.SetNull(columnNames[i])
 				}
 			} else {
 				val := v.(*float64)
@@ -81,10 +108,21 @@ func DynamicObjectSetter(s *sg.SQLGenerator, columnNames []string, columnPointer
 			}
 		} else if s.IsLOBType(typeName) {
 			if v == nil {
-				obj.Set(columnNames[i], object.NewSQLValue("NULL"))
+				obj.SetNull(columnNames[i])
 			} else {
 				val := v.(*LobDST)
-				obj.Set(columnNames[i], string(*val))
+				if s.IsBinaryType(typeName) {
+					obj.Set(columnNames[i], []byte(*val))
+				} else {
+					obj.Set(columnNames[i], string(*val))
+				}
+			}
+		} else if s.IsBinaryType(typeName) {
+			val := v.(*[]byte)
+			if *val != nil {
+				obj.Set(columnNames[i], *val)
+			} else {
+				obj.SetNull(columnNames[i])
 			}
 		} else {
 			return errors.New("dynamicObjectSetter: Unrecognized type: " + typeName)
@@ -98,7 +136,10 @@ func MakeColumnPointers(s *sg.SQLGenerator, sliceLen int, columnTypes []*sql.Col
 	for i := 0; i < sliceLen; i++ {
 		ct := columnTypes[i]
 		typeName := ct.DatabaseTypeName()
-		if s.IsStringType(typeName) {
+		if common.IsDecimalColumn(s, ct) {
+			var d sql.NullString
+			columnPointers[i] = &d
+		} else if s.IsStringType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
 				var s string
@@ -120,7 +161,7 @@ func MakeColumnPointers(s *sg.SQLGenerator, sliceLen int, columnTypes []*sql.Col
 		} else if s.IsTimestampType(typeName) {
 			nullable, _ := ct.Nullable()
 			if nullable {
-				var j time.Time
+				var j sql.NullTime
 				columnPointers[i] = &j
 			} else {
 				var j time.Time
@@ -136,6 +177,9 @@ func MakeColumnPointers(s *sg.SQLGenerator, sliceLen int, columnTypes []*sql.Col
 				s := new(LobDST)
 				columnPointers[i] = s
 			}
+		} else if s.IsBinaryType(typeName) {
+			var b []byte
+			columnPointers[i] = &b
 		} else {
 			return nil, errors.New("makeColumnPointers: Unrecognized type: " + typeName)
 		}
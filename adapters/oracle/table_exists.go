@@ -0,0 +1,11 @@
+package oracle
+
+import (
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingTableExists renders a query against user_tables that returns a row
+// if the named table exists. Unquoted names are stored upper cased.
+func BindingTableExists(g *sg.SQLGenerator, name string) (string, []interface{}) {
+	return "SELECT 1 FROM user_tables WHERE table_name = UPPER(" + g.Placeholder(0, "name") + ")", []interface{}{name}
+}
@@ -0,0 +1,11 @@
+package oracle
+
+// Truncate renders a TRUNCATE TABLE statement. CASCADE, available since
+// Oracle 12c, also truncates the child tables whose foreign keys are declared
+// ON DELETE CASCADE.
+func Truncate(name string, cascade bool) (string, error) {
+	if cascade {
+		return "TRUNCATE TABLE " + name + " CASCADE", nil
+	}
+	return "TRUNCATE TABLE " + name, nil
+}
@@ -1,5 +1,7 @@
 package oracle
 
+import "github.com/rbastic/dyndao/schema"
+
 var stringTypes = map[string]bool{
 	"VARCHAR2": true,
 	"varchar2": true,
@@ -19,6 +21,8 @@ var floatTypes = map[string]bool{
 var timestampTypes = map[string]bool{
 	"timestamp": true,
 	"TIMESTAMP": true,
+	"date":      true,
+	"DATE":      true,
 }
 
 var lobTypes = map[string]bool{
@@ -55,3 +59,46 @@ func IsTimestampType(k string) bool {
 func IsLOBType(k string) bool {
 	return lobTypes[k]
 }
+
+// IsBooleanType always returns false, since boolean columns are reported as
+// NUMBER. They are scanned as numbers and converted by the ORM according to
+// the schema.
+func IsBooleanType(k string) bool {
+	return false
+}
+
+var binaryTypes = map[string]bool{
+	"BLOB":     true,
+	"blob":     true,
+	"RAW":      true,
+	"raw":      true,
+	"LONG RAW": true,
+	"long raw": true,
+}
+
+// IsBinaryType can be used to help determine whether a certain data type holds
+// binary data, which is scanned into a []byte.
+func IsBinaryType(k string) bool {
+	return binaryTypes[k]
+}
+
+// IsDecimalType always returns false, since decimal columns are reported as
+// NUMBER. They are told apart from integers by their scale.
+func IsDecimalType(k string) bool {
+	return false
+}
+
+// DBTypes are the Oracle column types registered with the generator in New,
+// on top of those the Is*Type functions know, see
+// SQLGenerator.RegisterDBType.
+var DBTypes = map[string]string{
+	"CHAR":                           schema.LogicalString,
+	"NCHAR":                          schema.LogicalString,
+	"NVARCHAR2":                      schema.LogicalString,
+	"LONG":                           schema.LogicalText,
+	"NCLOB":                          schema.LogicalText,
+	"BINARY_FLOAT":                   schema.LogicalFloat,
+	"BINARY_DOUBLE":                  schema.LogicalFloat,
+	"TIMESTAMP WITH TIME ZONE":       schema.LogicalTimestamp,
+	"TIMESTAMP WITH LOCAL TIME ZONE": schema.LogicalTimestamp,
+}
@@ -14,3 +14,15 @@ import (
 func RenderCreateColumn(sg *sg.SQLGenerator, f *schema.Column) string {
 	return common.RenderCreateColumn(sg, f, "PRIMARY KEY", nil)
 }
+
+// LogicalTypes maps schema logical types to SQLite column types.
+var LogicalTypes = map[string]string{
+	schema.LogicalString:    "VARCHAR",
+	schema.LogicalText:      "TEXT",
+	schema.LogicalInt:       "INTEGER",
+	schema.LogicalFloat:     "REAL",
+	schema.LogicalBlob:      "BLOB",
+	schema.LogicalTimestamp: "DATETIME",
+	schema.LogicalBool:      "BOOLEAN",
+	schema.LogicalDecimal:   "DECIMAL",
+}
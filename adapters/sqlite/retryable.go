@@ -0,0 +1,13 @@
+package sqlite
+
+import "strings"
+
+// IsRetryable reports whether err is SQLITE_BUSY or SQLITE_LOCKED, raised
+// when another connection holds a conflicting lock.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked")
+}
@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err       error
+		retryable bool
+	}{
+		{sqlite3.Error{Code: sqlite3.ErrBusy}, true},
+		{sqlite3.Error{Code: sqlite3.ErrLocked}, true},
+		{pkgerrors.Wrap(sqlite3.Error{Code: sqlite3.ErrBusy}, "Insert"), true},
+		{sqlite3.Error{Code: sqlite3.ErrConstraint}, false},
+		{errors.New("no such table: widgets"), false},
+		{nil, false},
+	} {
+		if IsRetryable(tc.err) != tc.retryable {
+			t.Errorf("expected IsRetryable(%v) to be %v", tc.err, tc.retryable)
+		}
+	}
+}
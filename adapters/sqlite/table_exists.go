@@ -0,0 +1,11 @@
+package sqlite
+
+import (
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BindingTableExists renders a query against sqlite_master that returns a
+// row if the named table exists, since SQLite has no information_schema.
+func BindingTableExists(g *sg.SQLGenerator, name string) (string, []interface{}) {
+	return "SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = " + g.Placeholder(0, "name"), []interface{}{name}
+}
@@ -0,0 +1,15 @@
+package sqlite
+
+import (
+	"errors"
+)
+
+// Truncate renders a DELETE FROM statement, since SQLite has no TRUNCATE.
+// Without a WHERE clause SQLite deletes every row at once rather than row by
+// row.
+func Truncate(name string, cascade bool) (string, error) {
+	if cascade {
+		return "", errors.New("dyndao: sqlite cannot cascade a truncate, use ON DELETE CASCADE instead")
+	}
+	return "DELETE FROM " + name, nil
+}
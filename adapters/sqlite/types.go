@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"strings"
+
+	"github.com/rbastic/dyndao/schema"
 )
 
 // Derived from: www.sqlite.org/datatype3.html
@@ -59,8 +61,19 @@ var floatTypes = map[string]bool{
 }
 
 var timestampTypes = map[string]bool{
-	"datetime": true,
-	"DATETIME": true,
+	"datetime":  true,
+	"DATETIME":  true,
+	"timestamp": true,
+	"TIMESTAMP": true,
+	"date":      true,
+	"DATE":      true,
+}
+
+var booleanTypes = map[string]bool{
+	"BOOLEAN": true,
+	"boolean": true,
+	"BOOL":    true,
+	"bool":    true,
 }
 
 var lobTypes = map[string]bool{
@@ -96,3 +109,35 @@ func IsTimestampType(k string) bool {
 func IsLOBType(k string) bool {
 	return lobTypes[k]
 }
+
+// IsBooleanType can be used to help determine whether a certain data type is a boolean type.
+func IsBooleanType(k string) bool {
+	return booleanTypes[k]
+}
+
+var binaryTypes = map[string]bool{
+	"BLOB": true,
+	"blob": true,
+}
+
+// IsBinaryType can be used to help determine whether a certain data type holds
+// binary data, which is scanned into a []byte.
+func IsBinaryType(k string) bool {
+	return binaryTypes[k]
+}
+
+// IsDecimalType can be used to help determine whether a certain data type is a
+// fixed precision decimal type, which is scanned into a string.
+func IsDecimalType(k string) bool {
+	k = strings.ToUpper(k)
+	return strings.HasPrefix(k, "DECIMAL") || strings.HasPrefix(k, "NUMERIC")
+}
+
+// DBTypes are the SQLite column types registered with the generator in New,
+// on top of those the Is*Type functions know, see
+// SQLGenerator.RegisterDBType.
+var DBTypes = map[string]string{
+	"CHAR":             schema.LogicalString,
+	"NCHAR VARYING":    schema.LogicalString,
+	"UNSIGNED BIG INT": schema.LogicalInt,
+}
@@ -0,0 +1,108 @@
+package object
+
+import (
+	"time"
+)
+
+// Clone returns a deep copy of the object, including HiddenKV, ChangedColumns,
+// the dirty state and (recursively) all of the children and parents. SQLValue
+// pointers and other reference values are copied, so calling Set on the clone
+// never affects the original object. An object reachable along several paths,
+// such as a parent which also holds its child in Children, is copied once, so
+// the clone has the same shape as the original.
+func (o *Object) Clone() *Object {
+	return o.cloneCore(true, make(map[*Object]*Object))
+}
+
+// CloneWithoutState returns a deep copy of the object that has the same values
+// and relations, but is marked dirty and has no ChangedColumns, as if it had
+// just been constructed with New and populated with SetCore.
+func (o *Object) CloneWithoutState() *Object {
+	return o.cloneCore(false, make(map[*Object]*Object))
+}
+
+func (o *Object) cloneCore(keepState bool, seen map[*Object]*Object) *Object {
+	if c, ok := seen[o]; ok {
+		return c
+	}
+	c := New(o.Type)
+	seen[o] = c
+	c.KV = cloneMap(o.KV)
+	if o.HiddenKV != nil {
+		c.HiddenKV = cloneMap(o.HiddenKV)
+	}
+	if keepState {
+		c.ChangedColumns = cloneMap(o.ChangedColumns)
+		c.dirty = o.dirty
+	}
+	for name, children := range o.Children {
+		c.Children[name] = cloneArray(children, keepState, seen)
+	}
+	if o.Parents != nil {
+		c.Parents = make(map[string]Array, len(o.Parents))
+		for name, parents := range o.Parents {
+			c.Parents[name] = cloneArray(parents, keepState, seen)
+		}
+	}
+	return c
+}
+
+func cloneArray(objs Array, keepState bool, seen map[*Object]*Object) Array {
+	if objs == nil {
+		return nil
+	}
+	ary := MakeArray(len(objs))
+	for i, obj := range objs {
+		if obj != nil {
+			ary[i] = obj.cloneCore(keepState, seen)
+		}
+	}
+	return ary
+}
+
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = cloneValue(v)
+	}
+	return c
+}
+
+func cloneValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case *SQLValue:
+		if t == nil {
+			return t
+		}
+		return &SQLValue{Value: t.Value}
+	case *time.Time:
+		if t == nil {
+			return t
+		}
+		tt := *t
+		return &tt
+	case []byte:
+		if t == nil {
+			return t
+		}
+		b := make([]byte, len(t))
+		copy(b, t)
+		return b
+	case map[string]interface{}:
+		if t == nil {
+			return t
+		}
+		return cloneMap(t)
+	case []interface{}:
+		if t == nil {
+			return t
+		}
+		s := make([]interface{}, len(t))
+		for i, e := range t {
+			s[i] = cloneValue(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,63 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	obj := New("people")
+	obj.Set("Name", "Ryan")
+	obj.Set("NullText", NewNULLValue())
+	addr := New("addresses")
+	addr.Set("City", "Nowhere")
+	obj.Children["addresses"] = NewArray(addr)
+	obj.MarkDirty(false)
+
+	clone := obj.Clone()
+	if !reflect.DeepEqual(obj, clone) {
+		t.Fatal("clone should initially equal the original")
+	}
+
+	clone.Set("Name", "Joe")
+	clone.Get("NullText").(*SQLValue).Value = "CURRENT_TIMESTAMP"
+	clone.Children["addresses"][0].Set("City", "Lincoln")
+	clone.Children["addresses"] = append(clone.Children["addresses"], New("addresses"))
+
+	if obj.Get("Name") != "Ryan" || obj.IsDirty() || len(obj.ChangedColumns) != 0 {
+		t.Fatal("mutating the clone changed the original's fields or state")
+	}
+	if !obj.ValueIsNULL(obj.Get("NullText")) {
+		t.Fatal("mutating the clone's SQLValue changed the original")
+	}
+	if len(obj.Children["addresses"]) != 1 || obj.Children["addresses"][0].Get("City") != "Nowhere" {
+		t.Fatal("mutating the clone's children changed the original")
+	}
+
+	fresh := obj.CloneWithoutState()
+	if !fresh.IsDirty() || len(fresh.ChangedColumns) != 0 || !fresh.Children["addresses"][0].IsDirty() {
+		t.Fatal("CloneWithoutState should return dirty objects without ChangedColumns")
+	}
+}
+
+func TestCloneParents(t *testing.T) {
+	person := New("people")
+	person.Set("Name", "Ryan")
+	addr := New("addresses")
+	addr.Set("City", "Nowhere")
+	person.Children["addresses"] = NewArray(addr)
+	addr.Parents = map[string]Array{"people": NewArray(person)}
+
+	clone := person.Clone()
+	cloneAddr := clone.Children["addresses"][0]
+	if cloneAddr == addr || cloneAddr.Parents["people"][0] != clone {
+		t.Fatal("expected the clone to keep the cycle between its own objects")
+	}
+	cloneAddr.Parents["people"][0].Set("Name", "Joe")
+	if person.Get("Name") != "Ryan" {
+		t.Fatal("mutating the clone's parent changed the original")
+	}
+	if New("people").Clone().Parents != nil {
+		t.Fatal("expected an object without parents to clone without parents")
+	}
+}
@@ -0,0 +1,46 @@
+package object
+
+import (
+	"reflect"
+)
+
+// FieldDiff holds the old and new values of a single field, as reported by
+// Diff.
+type FieldDiff struct {
+	Old interface{}
+	New interface{}
+}
+
+// ChangedFields returns the current values of every field that has been
+// changed since the object was last saved or retrieved (that is, the fields
+// which an UPDATE would write). The returned map is a copy and is empty
+// immediately after a successful Save, whose orm.SaveOutcome keeps the
+// fields it wrote.
+func (o *Object) ChangedFields() map[string]interface{} {
+	changed := make(map[string]interface{}, len(o.ChangedColumns))
+	for k := range o.ChangedColumns {
+		changed[k] = o.KV[k]
+	}
+	return changed
+}
+
+// Diff compares the KV of this object (the old values) against other (the
+// new values), returning every field whose value differs. Fields present in
+// only one of the objects are reported with a nil value on the other side.
+// Children and Parents are not compared, so that a fleshened object diffs
+// equal to the same row retrieved on its own.
+func (o *Object) Diff(other *Object) map[string]FieldDiff {
+	diff := make(map[string]FieldDiff)
+	for k, oldVal := range o.KV {
+		newVal, ok := other.KV[k]
+		if !ok || !reflect.DeepEqual(oldVal, newVal) {
+			diff[k] = FieldDiff{Old: oldVal, New: newVal}
+		}
+	}
+	for k, newVal := range other.KV {
+		if _, ok := o.KV[k]; !ok {
+			diff[k] = FieldDiff{Old: nil, New: newVal}
+		}
+	}
+	return diff
+}
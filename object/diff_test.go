@@ -0,0 +1,37 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestChangedFieldsAndDiff(t *testing.T) {
+	obj := New("people")
+	obj.Set("Name", "Ryan")
+	obj.Set("Age", int64(30))
+	obj.MarkDirty(false)
+
+	orig := obj.Clone()
+	obj.Set("Name", "Joe")
+	obj.Set("City", "Nowhere")
+
+	changed := obj.ChangedFields()
+	if len(changed) != 1 || changed["Name"] != "Joe" {
+		t.Fatal("unexpected ChangedFields", changed)
+	}
+
+	diff := orig.Diff(obj)
+	if len(diff) != 2 {
+		t.Fatal("unexpected Diff", diff)
+	}
+	if diff["Name"].Old != "Ryan" || diff["Name"].New != "Joe" {
+		t.Fatal("unexpected Diff for Name", diff["Name"])
+	}
+	if diff["City"].Old != nil || diff["City"].New != "Nowhere" {
+		t.Fatal("unexpected Diff for City", diff["City"])
+	}
+
+	obj.ResetChangedColumns()
+	if len(obj.ChangedFields()) != 0 {
+		t.Fatal("expected no ChangedFields after reset")
+	}
+}
@@ -0,0 +1,299 @@
+package object
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonObject is the JSON representation of an Object. Values in KV and
+// ChangedColumns are encoded by encodeJSONValue. Relations may be encoded
+// without their own relations, see MarshalJSON.
+type jsonObject struct {
+	Type           string                   `json:"Type"`
+	KV             map[string]interface{}   `json:"KV"`
+	ChangedColumns map[string]interface{}   `json:"ChangedColumns,omitempty"`
+	Children       map[string][]*jsonObject `json:"Children,omitempty"`
+	Parents        map[string][]*jsonObject `json:"Parents,omitempty"`
+	Dirty          bool                     `json:"Dirty"`
+}
+
+// jsonSQLValue and jsonTime tag values that JSON has no native type for, so
+// that they can be told apart from plain strings when unmarshaling.
+type jsonSQLValue struct {
+	SQLValue string `json:"SQLValue"`
+}
+
+type jsonTime struct {
+	Time string `json:"Time"`
+}
+
+type jsonBytes struct {
+	Bytes []byte `json:"Bytes"`
+}
+
+// MarshalJSON encodes the object's Type, KV, ChangedColumns, Children, Parents
+// and dirty state. SQLValues (including NULL values) are encoded as
+// {"SQLValue": ...}, time.Time values as {"Time": RFC3339}, []byte values as
+// {"Bytes": base64} and floating point values always carry a decimal point, so
+// that UnmarshalJSON can reconstruct them faithfully. Parents, and children
+// which are also an ancestor of themselves, are encoded without their own
+// Children and Parents: those lead back to objects already being encoded, and
+// encoding them would never end.
+func (o *Object) MarshalJSON() ([]byte, error) {
+	jo, err := o.toJSONObject(make(map[*Object]bool))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jo)
+}
+
+// toJSONObject encodes the object and its relations. path holds the objects
+// whose children are being encoded, which the object's children may refer
+// back to.
+func (o *Object) toJSONObject(path map[*Object]bool) (*jsonObject, error) {
+	jo, err := o.shallowJSONObject()
+	if err != nil || path[o] {
+		return jo, err
+	}
+	path[o] = true
+	defer delete(path, o)
+
+	if len(o.Children) > 0 {
+		jo.Children = make(map[string][]*jsonObject, len(o.Children))
+		for name, children := range o.Children {
+			if jo.Children[name], err = encodeJSONArray(children, func(child *Object) (*jsonObject, error) {
+				return child.toJSONObject(path)
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(o.Parents) > 0 {
+		jo.Parents = make(map[string][]*jsonObject, len(o.Parents))
+		for name, parents := range o.Parents {
+			if jo.Parents[name], err = encodeJSONArray(parents, (*Object).shallowJSONObject); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return jo, nil
+}
+
+// shallowJSONObject encodes the object without its Children and Parents.
+func (o *Object) shallowJSONObject() (*jsonObject, error) {
+	kv, err := encodeJSONMap(o.KV)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := encodeJSONMap(o.ChangedColumns)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonObject{Type: o.Type, KV: kv, ChangedColumns: changed, Dirty: o.dirty}, nil
+}
+
+func encodeJSONArray(objs Array, encode func(*Object) (*jsonObject, error)) ([]*jsonObject, error) {
+	if objs == nil {
+		return nil, nil
+	}
+	jos := make([]*jsonObject, len(objs))
+	for i, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		var err error
+		if jos[i], err = encode(obj); err != nil {
+			return nil, err
+		}
+	}
+	return jos, nil
+}
+
+// UnmarshalJSON decodes an object previously encoded by MarshalJSON. Numbers
+// without a decimal point or exponent become int64, other numbers become
+// float64.
+func (o *Object) UnmarshalJSON(buf []byte) error {
+	var jo jsonObject
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	if err := dec.Decode(&jo); err != nil {
+		return err
+	}
+	return o.setFromJSONObject(&jo)
+}
+
+func (o *Object) setFromJSONObject(jo *jsonObject) error {
+	kv, err := decodeJSONMap(jo.KV)
+	if err != nil {
+		return err
+	}
+	changed, err := decodeJSONMap(jo.ChangedColumns)
+	if err != nil {
+		return err
+	}
+	children := makeEmptyChildrenMap()
+	for name, jcs := range jo.Children {
+		if children[name], err = decodeJSONArray(jcs); err != nil {
+			return err
+		}
+	}
+	var parents map[string]Array
+	if jo.Parents != nil {
+		parents = make(map[string]Array, len(jo.Parents))
+		for name, jps := range jo.Parents {
+			if parents[name], err = decodeJSONArray(jps); err != nil {
+				return err
+			}
+		}
+	}
+
+	o.Type = jo.Type
+	o.KV = kv
+	o.ChangedColumns = changed
+	o.Children = children
+	o.Parents = parents
+	o.dirty = jo.Dirty
+	return nil
+}
+
+func decodeJSONArray(jos []*jsonObject) (Array, error) {
+	if jos == nil {
+		return nil, nil
+	}
+	ary := MakeArray(len(jos))
+	for i, jo := range jos {
+		if jo == nil {
+			continue
+		}
+		ary[i] = New(jo.Type)
+		if err := ary[i].setFromJSONObject(jo); err != nil {
+			return nil, err
+		}
+	}
+	return ary, nil
+}
+
+func encodeJSONMap(m map[string]interface{}) (map[string]interface{}, error) {
+	enc := makeEmptyMap()
+	for k, v := range m {
+		ev, err := encodeJSONValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("MarshalJSON: key %s: %s", k, err.Error())
+		}
+		enc[k] = ev
+	}
+	return enc, nil
+}
+
+func encodeJSONValue(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case *SQLValue:
+		if t == nil {
+			return nil, nil
+		}
+		return jsonSQLValue{SQLValue: t.Value}, nil
+	case SQLValue:
+		return jsonSQLValue{SQLValue: t.Value}, nil
+	case time.Time:
+		return jsonTime{Time: t.Format(time.RFC3339Nano)}, nil
+	case *time.Time:
+		if t == nil {
+			return nil, nil
+		}
+		return jsonTime{Time: t.Format(time.RFC3339Nano)}, nil
+	case []byte:
+		if t == nil {
+			return nil, nil
+		}
+		return jsonBytes{Bytes: t}, nil
+	case float64:
+		return encodeJSONFloat(t)
+	case float32:
+		return encodeJSONFloat(float64(t))
+	case sql.NullString:
+		if !t.Valid {
+			return nil, nil
+		}
+		return t.String, nil
+	case sql.NullInt64:
+		if !t.Valid {
+			return nil, nil
+		}
+		return t.Int64, nil
+	case sql.NullFloat64:
+		if !t.Valid {
+			return nil, nil
+		}
+		return encodeJSONFloat(t.Float64)
+	case sql.NullBool:
+		if !t.Valid {
+			return nil, nil
+		}
+		return t.Bool, nil
+	default:
+		return v, nil
+	}
+}
+
+func encodeJSONFloat(f float64) (interface{}, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("unsupported float value %v", f)
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return json.Number(s), nil
+}
+
+func decodeJSONMap(m map[string]interface{}) (map[string]interface{}, error) {
+	dec := makeEmptyMap()
+	for k, v := range m {
+		dv, err := decodeJSONValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("UnmarshalJSON: key %s: %s", k, err.Error())
+		}
+		dec[k] = dv
+	}
+	return dec, nil
+}
+
+func decodeJSONValue(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case json.Number:
+		s := t.String()
+		if strings.ContainsAny(s, ".eE") {
+			return t.Float64()
+		}
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return u, nil
+		}
+		return t.Float64()
+	case map[string]interface{}:
+		if len(t) != 1 {
+			return t, nil
+		}
+		if sv, ok := t["SQLValue"].(string); ok {
+			return NewSQLValue(sv), nil
+		}
+		if ts, ok := t["Time"].(string); ok {
+			return time.Parse(time.RFC3339Nano, ts)
+		}
+		if bs, ok := t["Bytes"].(string); ok {
+			return base64.StdEncoding.DecodeString(bs)
+		}
+		return t, nil
+	default:
+		return v, nil
+	}
+}
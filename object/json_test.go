@@ -0,0 +1,112 @@
+package object
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	obj := New("people")
+	obj.Set("Name", "Ryan")
+	obj.Set("PersonID", int64(1))
+	obj.Set("Score", 3.0)
+	obj.Set("Born", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
+	obj.Set("NullText", NewNULLValue())
+	obj.Set("Stamp", NewSQLValue("CURRENT_TIMESTAMP"))
+	obj.Set("Avatar", []byte{0, 'a', 0, 255})
+
+	addr1 := New("addresses")
+	addr1.Set("Address1", "Test")
+	addr1.Set("AddressID", int64(1))
+	addr1.MarkDirty(false)
+	addr2 := New("addresses")
+	addr2.Set("Address1", "Foo")
+	obj.Children["addresses"] = Array{addr1, addr2}
+
+	buf, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := New("")
+	err = json.Unmarshal(buf, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(obj, decoded) {
+		t.Fatalf("round-trip mismatch:\n%s\noriginal: %v\ndecoded:  %v", string(buf), obj, decoded)
+	}
+	if !decoded.ValueIsNULL(decoded.Get("NullText")) {
+		t.Fatal("expected NullText to remain a NULL value")
+	}
+	if _, ok := decoded.Get("Score").(float64); !ok {
+		t.Fatal("expected Score to remain a float64")
+	}
+	if decoded.Children["addresses"][0].IsDirty() {
+		t.Fatal("expected the first address to remain clean")
+	}
+}
+
+func TestJSONParents(t *testing.T) {
+	person := New("people")
+	person.Set("PersonID", int64(1))
+	person.MarkDirty(false)
+	addr := New("addresses")
+	addr.Set("City", "Nowhere")
+	person.Children["addresses"] = NewArray(addr)
+	addr.Parents = map[string]Array{"people": NewArray(person)}
+
+	// The parent refers back to the address, which must not loop
+	buf, err := json.Marshal(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := New("")
+	if err := json.Unmarshal(buf, decoded); err != nil {
+		t.Fatal(err)
+	}
+	parents := decoded.Parents["people"]
+	if len(parents) != 1 || parents[0].Get("PersonID") != int64(1) || parents[0].IsDirty() {
+		t.Fatalf("expected the clean person to round trip as a parent, got %s", string(buf))
+	}
+	if len(parents[0].Children) != 0 || parents[0].Parents != nil {
+		t.Fatal("expected parents to be encoded without their relations")
+	}
+
+	buf, err = json.Marshal(New("people"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded = New("")
+	if err := json.Unmarshal(buf, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Parents != nil {
+		t.Fatal("expected an object without parents to decode without parents")
+	}
+}
+
+func TestJSONChildCycle(t *testing.T) {
+	ada := New("members")
+	ada.Set("MemberID", int64(1))
+	bob := New("members")
+	bob.Set("MemberID", int64(2))
+	ada.Children["members"] = NewArray(bob)
+	bob.Children["members"] = NewArray(ada)
+
+	buf, err := json.Marshal(ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := New("")
+	if err := json.Unmarshal(buf, decoded); err != nil {
+		t.Fatal(err)
+	}
+	back := decoded.Children["members"][0].Children["members"][0]
+	if back.Get("MemberID") != int64(1) || len(back.Children) != 0 {
+		t.Fatalf("expected the cycle to end at the back-reference, got %s", string(buf))
+	}
+}
@@ -0,0 +1,77 @@
+package object
+
+import (
+	"database/sql"
+	"database/sql/driver"
+)
+
+// FromMap returns a new object of type table holding the values in m. nil
+// values become NULL values. A value of type []map[string]interface{}, as
+// produced by ToMapWithChildren, becomes the children of that name instead.
+func FromMap(table string, m map[string]interface{}) *Object {
+	obj := New(table)
+	for k, v := range m {
+		switch t := v.(type) {
+		case nil:
+			obj.Set(k, NewNULLValue())
+		case []map[string]interface{}:
+			children := MakeArray(len(t))
+			for i, cm := range t {
+				children[i] = FromMap(k, cm)
+			}
+			obj.Children[k] = children
+		default:
+			obj.Set(k, v)
+		}
+	}
+	return obj
+}
+
+// ToMap returns the object's values as a new map. NULL values, and the
+// invalid sql.Null* values scanned from NULL columns, become nil; valid
+// sql.Null* values become the value they hold. Other SQLValues are kept as
+// is. Children are not included.
+func (o *Object) ToMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(o.KV))
+	for k, v := range o.KV {
+		m[k] = mapValue(v)
+	}
+	return m
+}
+
+// ToMapWithChildren is ToMap, with the children of each child table
+// included, recursively, as a []map[string]interface{} keyed by the child
+// table's name.
+func (o *Object) ToMapWithChildren() map[string]interface{} {
+	m := o.ToMap()
+	for name, children := range o.Children {
+		cms := make([]map[string]interface{}, len(children))
+		for i, child := range children {
+			cms[i] = child.ToMapWithChildren()
+		}
+		m[name] = cms
+	}
+	return m
+}
+
+func mapValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case *SQLValue:
+		if t == nil || t.Value == "NULL" {
+			return nil
+		}
+		return t
+	case sql.NullString, sql.NullInt64, sql.NullInt32, sql.NullFloat64, sql.NullBool, sql.NullTime,
+		*sql.NullString, *sql.NullInt64, *sql.NullInt32, *sql.NullFloat64, *sql.NullBool, *sql.NullTime:
+		// A nil pointer is NULL, and would panic in Value
+		if IsNULL(t) {
+			return nil
+		}
+		val, err := t.(driver.Valuer).Value()
+		if err != nil {
+			return nil
+		}
+		return val
+	}
+	return v
+}
@@ -0,0 +1,51 @@
+package object
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestFromMapToMap(t *testing.T) {
+	obj := FromMap("people", map[string]interface{}{
+		"Name":     "Ryan",
+		"Age":      int64(30),
+		"Nickname": nil,
+		"addresses": []map[string]interface{}{
+			{"City": "Nowhere"},
+		},
+	})
+	if !obj.ValueIsNULL(obj.Get("Nickname")) {
+		t.Fatal("expected nil to become a NULL value, got", obj.Get("Nickname"))
+	}
+	if len(obj.Children["addresses"]) != 1 || obj.Children["addresses"][0].Get("City") != "Nowhere" {
+		t.Fatal("expected the nested maps to become children", obj.Children)
+	}
+	if _, ok := obj.KV["addresses"]; ok {
+		t.Fatal("expected children not to be stored as a value")
+	}
+
+	obj.Set("Scanned", sql.NullString{String: "x", Valid: true})
+	obj.Set("ScannedNULL", sql.NullInt64{})
+	obj.Set("ScannedNilPtr", (*sql.NullString)(nil))
+	expected := map[string]interface{}{
+		"Name":          "Ryan",
+		"Age":           int64(30),
+		"Nickname":      nil,
+		"Scanned":       "x",
+		"ScannedNULL":   nil,
+		"ScannedNilPtr": nil,
+	}
+	if m := obj.ToMap(); !reflect.DeepEqual(m, expected) {
+		t.Fatal("unexpected map", m)
+	}
+
+	m := obj.ToMapWithChildren()
+	if !reflect.DeepEqual(m["addresses"], []map[string]interface{}{{"City": "Nowhere"}}) {
+		t.Fatal("expected the children to be included", m)
+	}
+	round := FromMap("people", m)
+	if round.Get("Name") != "Ryan" || len(round.Children["addresses"]) != 1 || !round.ValueIsNULL(round.Get("Nickname")) {
+		t.Fatal("expected ToMapWithChildren to round trip through FromMap", round)
+	}
+}
@@ -6,11 +6,14 @@
 package object
 
 import (
+	"bytes"
 	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"time"
 )
 
 var (
@@ -42,6 +45,7 @@ type Object struct {
 	HiddenKV       map[string]interface{}
 	ChangedColumns map[string]interface{}
 	Children       map[string]Array
+	Parents        map[string]Array // set by orm.FleshenParents, nil until then
 	dirty          bool
 }
 
@@ -91,6 +95,25 @@ func (o *Object) Get(k string) interface{} {
 	return o.KV[k]
 }
 
+// Fields returns the names of the object's fields in sorted order. Children
+// are not included.
+func (o *Object) Fields() []string {
+	keys := make([]string, 0, len(o.KV))
+	for k := range o.KV {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Each calls fn for every field of the object, in the order returned by
+// Fields. Children are not included.
+func (o *Object) Each(fn func(key string, val interface{})) {
+	for _, k := range o.Fields() {
+		fn(k, o.KV[k])
+	}
+}
+
 // GetWithFlag is the second most basic accessor, for cases
 // that may not be handled by other methods
 func (o *Object) GetWithFlag(k string) (interface{}, bool) {
@@ -104,6 +127,12 @@ func (o *Object) GetBool(k string) (bool, bool) {
 	return v, ok
 }
 
+// GetBytes is a safe, typed []byte accessor
+func (o *Object) GetBytes(k string) ([]byte, bool) {
+	v, ok := o.KV[k].([]byte)
+	return v, ok
+}
+
 // GetString is a safe, typed string accessor
 func (o *Object) GetString(k string) (string, bool) {
 	v, ok := o.KV[k].(string)
@@ -190,8 +219,9 @@ func (o *Object) GetStringAlways(k string) (string, error) {
 }
 
 // GetFloatAlways is a safe, typed float64 accessor. It will force conversion away
-// from float64, int64, uint64, string, and nil values. Nils and unrecognized values
-// are marked as an error (nil values will return 0 and ErrValueWasNil)
+// from float64, float32, int64, int, uint64, string, sql.NullFloat64, and nil
+// values. Nils and unrecognized values are marked as an error (nil values will
+// return 0 and ErrValueWasNil)
 func (o *Object) GetFloatAlways(k string) (float64, error) {
 	v, ok := o.KV[k]
 	if !ok {
@@ -202,15 +232,27 @@ func (o *Object) GetFloatAlways(k string) (float64, error) {
 	case float64:
 		fl := v.(float64)
 		return fl, nil
+	case float32:
+		fl := v.(float32)
+		return float64(fl), nil
 	case int64:
-		fl := v.(float64)
-		return fl, nil
+		fl := v.(int64)
+		return float64(fl), nil
+	case int:
+		fl := v.(int)
+		return float64(fl), nil
 	case uint64:
 		fl := v.(uint64)
 		return float64(fl), nil
 	case string:
 		fl := v.(string)
 		return strconv.ParseFloat(fl, 64)
+	case sql.NullFloat64:
+		fl := v.(sql.NullFloat64)
+		if !fl.Valid {
+			return 0, ErrValueWasNil
+		}
+		return fl.Float64, nil
 	case nil:
 		return 0, ErrValueWasNil
 	// TODO: what about booleans?
@@ -219,6 +261,99 @@ func (o *Object) GetFloatAlways(k string) (float64, error) {
 	}
 }
 
+// GetBoolAlways is a safe, typed bool accessor. It will force conversion away
+// from bool, int64, int, uint64, float64 (non-zero is true), string (anything
+// strconv.ParseBool accepts, such as "1", "t", "true", "0", "f" and "false"),
+// sql.NullBool, and nil values. Nils and unrecognized values are marked as an
+// error (nil values will return false and ErrValueWasNil)
+func (o *Object) GetBoolAlways(k string) (bool, error) {
+	v, ok := o.KV[k]
+	if !ok {
+		return false, ErrKeyWasMissing
+	}
+
+	switch v.(type) {
+	case bool:
+		b := v.(bool)
+		return b, nil
+	case int64:
+		b := v.(int64)
+		return b != 0, nil
+	case int:
+		b := v.(int)
+		return b != 0, nil
+	case uint64:
+		b := v.(uint64)
+		return b != 0, nil
+	case float64:
+		b := v.(float64)
+		return b != 0, nil
+	case string:
+		b := v.(string)
+		return strconv.ParseBool(b)
+	case sql.NullBool:
+		b := v.(sql.NullBool)
+		if !b.Valid {
+			return false, ErrValueWasNil
+		}
+		return b.Bool, nil
+	case nil:
+		return false, ErrValueWasNil
+	default:
+		return false, fmt.Errorf("GetBoolAlways: unrecognized type %v", reflect.TypeOf(v))
+	}
+}
+
+// timeLayouts are the string layouts accepted by GetTimeAlways, in the order
+// they are tried.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// GetTimeAlways is a safe, typed time.Time accessor. It will force conversion
+// away from time.Time, *time.Time, string (RFC3339, "2006-01-02 15:04:05" or
+// "2006-01-02"), sql.NullTime, and nil values. Nils and unrecognized values are
+// marked as an error (nil values will return the zero time and ErrValueWasNil)
+func (o *Object) GetTimeAlways(k string) (time.Time, error) {
+	v, ok := o.KV[k]
+	if !ok {
+		return time.Time{}, ErrKeyWasMissing
+	}
+
+	switch v.(type) {
+	case time.Time:
+		t := v.(time.Time)
+		return t, nil
+	case *time.Time:
+		t := v.(*time.Time)
+		if t == nil {
+			return time.Time{}, ErrValueWasNil
+		}
+		return *t, nil
+	case string:
+		s := v.(string)
+		for _, layout := range timeLayouts {
+			t, err := time.Parse(layout, s)
+			if err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("GetTimeAlways: unable to parse %s as a time", s)
+	case sql.NullTime:
+		t := v.(sql.NullTime)
+		if !t.Valid {
+			return time.Time{}, ErrValueWasNil
+		}
+		return t.Time, nil
+	case nil:
+		return time.Time{}, ErrValueWasNil
+	default:
+		return time.Time{}, fmt.Errorf("GetTimeAlways: unrecognized type %v", reflect.TypeOf(v))
+	}
+}
+
 // GetIntAlways is a safe, typed int64 accessor. It will force conversion away
 // from float64, uint64, int64 and string values. Nils and unrecognized values are
 // marked as an error (nil values will return 0 and ErrValueWasNil)
@@ -287,7 +422,7 @@ func (o *Object) Set(k string, v interface{}) {
 
 	if oldVal != nil {
 		// Avoid redundant Set()s
-		if oldVal == v {
+		if sameValue(oldVal, v) {
 			return
 		}
 		o.ColumnChanged(k, oldVal)
@@ -298,6 +433,111 @@ func (o *Object) Set(k string, v interface{}) {
 	o.SetCore(k, v)
 }
 
+// sameValue reports whether a and b are equal, without panicking on values
+// such as []byte which can't be compared with ==.
+func sameValue(a, b interface{}) bool {
+	if ab, ok := a.([]byte); ok {
+		bb, ok := b.([]byte)
+		return ok && bytes.Equal(ab, bb)
+	}
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) || ta == nil || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// SetNull sets k to an explicit NULL value, so that saving the object writes
+// k = NULL. Unlike Set, it records the change even when k had no value in the
+// object, so k is written alongside any other changed columns rather than
+// being left as it is in the database. Keys that are already NULL are left
+// alone.
+func (o *Object) SetNull(k string) {
+	oldVal, ok := o.KV[k]
+	if ok && o.ValueIsNULL(oldVal) {
+		return
+	}
+	o.ColumnChanged(k, oldVal)
+	o.MarkDirty(true)
+	o.SetCore(k, NewNULLValue())
+}
+
+// SetIfChanged is Set for values that may be unchanged, such as those
+// resubmitted by a form. It sets k, and marks the object dirty, only when v
+// differs from the current value, and reports whether it did. Unlike Set, it
+// treats nil, NULL SQLValues and invalid sql.Null* values as the same NULL,
+// compares valid sql.Null* values by the value they hold, and compares
+// integers and floats by value regardless of their Go type. A key with no
+// value is always set.
+func (o *Object) SetIfChanged(k string, v interface{}) bool {
+	if oldVal, ok := o.KV[k]; ok && equalValues(oldVal, v) {
+		return false
+	}
+	o.Set(k, v)
+	return true
+}
+
+// equalValues reports whether a and b hold the same value, as described for
+// SetIfChanged.
+func equalValues(a, b interface{}) bool {
+	a, b = mapValue(a), mapValue(b)
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if sameValue(a, b) {
+		return true
+	}
+	if sa, ok := a.(*SQLValue); ok {
+		sb, ok := b.(*SQLValue)
+		return ok && sa.Value == sb.Value
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isIntKind(va.Kind()) && isIntKind(vb.Kind()):
+		return va.Int() == vb.Int()
+	case isUintKind(va.Kind()) && isUintKind(vb.Kind()):
+		return va.Uint() == vb.Uint()
+	case isIntKind(va.Kind()) && isUintKind(vb.Kind()):
+		return va.Int() >= 0 && uint64(va.Int()) == vb.Uint()
+	case isUintKind(va.Kind()) && isIntKind(vb.Kind()):
+		return vb.Int() >= 0 && va.Uint() == uint64(vb.Int())
+	case isNumericKind(va.Kind()) && isNumericKind(vb.Kind()):
+		return toFloat(va) == toFloat(vb)
+	}
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return false
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUintKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isIntKind(v.Kind()):
+		return float64(v.Int())
+	case isUintKind(v.Kind()):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
 // ColumnChanged records the previous value for something that is about to be
 // set
 func (o *Object) ColumnChanged(k string, oldVal interface{}) {
@@ -350,3 +590,18 @@ func (o *Object) MarkDirty(status bool) {
 func (o *Object) IsDirty() bool {
 	return o.dirty
 }
+
+// FieldIsDirty reports whether the field k has unsaved changes, that is,
+// whether saving the object would write it: k is one of ChangedColumns or,
+// for a dirty object without any, such as a new one, one of its fields, as
+// an update then writes all of them.
+func (o *Object) FieldIsDirty(k string) bool {
+	if _, ok := o.ChangedColumns[k]; ok {
+		return true
+	}
+	if len(o.ChangedColumns) == 0 && o.IsDirty() {
+		_, ok := o.KV[k]
+		return ok
+	}
+	return false
+}
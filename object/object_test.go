@@ -1,8 +1,10 @@
 package object
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestObject(t *testing.T) {
@@ -23,3 +25,230 @@ func TestObject(t *testing.T) {
 	fmt.Println(obj.ChangedColumns)
 	fmt.Println(obj.KV)
 }
+
+func TestGetFloatAlways(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected float64
+		fails    bool
+	}{
+		{float64(1.5), 1.5, false},
+		{float32(0.5), 0.5, false},
+		{int64(2), 2, false},
+		{int(3), 3, false},
+		{uint64(4), 4, false},
+		{"5.25", 5.25, false},
+		{sql.NullFloat64{Float64: 6, Valid: true}, 6, false},
+		{"abc", 0, true},
+		{true, 0, true},
+		{nil, 0, true},
+	}
+	for i, tt := range tests {
+		obj := New("test")
+		obj.Set("k", tt.value)
+		v, err := obj.GetFloatAlways("k")
+		if (err != nil) != tt.fails || v != tt.expected {
+			t.Errorf("case %d (%v): got %v, %v", i, tt.value, v, err)
+		}
+	}
+}
+
+func TestGetBoolAlways(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected bool
+		fails    bool
+	}{
+		{true, true, false},
+		{false, false, false},
+		{int64(1), true, false},
+		{int64(0), false, false},
+		{int(2), true, false},
+		{uint64(0), false, false},
+		{float64(1), true, false},
+		{"1", true, false},
+		{"true", true, false},
+		{"0", false, false},
+		{"false", false, false},
+		{sql.NullBool{Bool: true, Valid: true}, true, false},
+		{sql.NullBool{}, false, true},
+		{"maybe", false, true},
+		{nil, false, true},
+	}
+	for i, tt := range tests {
+		obj := New("test")
+		obj.Set("k", tt.value)
+		v, err := obj.GetBoolAlways("k")
+		if (err != nil) != tt.fails || v != tt.expected {
+			t.Errorf("case %d (%v): got %v, %v", i, tt.value, v, err)
+		}
+	}
+}
+
+func TestGetTimeAlways(t *testing.T) {
+	when := time.Date(2017, 6, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		value    interface{}
+		expected time.Time
+		fails    bool
+	}{
+		{when, when, false},
+		{&when, when, false},
+		{"2017-06-01T12:30:00Z", when, false},
+		{"2017-06-01 12:30:00", when, false},
+		{"2017-06-01", time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC), false},
+		{sql.NullTime{Time: when, Valid: true}, when, false},
+		{sql.NullTime{}, time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+		{int64(1), time.Time{}, true},
+		{nil, time.Time{}, true},
+	}
+	for i, tt := range tests {
+		obj := New("test")
+		obj.Set("k", tt.value)
+		v, err := obj.GetTimeAlways("k")
+		if (err != nil) != tt.fails || !v.Equal(tt.expected) {
+			t.Errorf("case %d (%v): got %v, %v", i, tt.value, v, err)
+		}
+	}
+
+	obj := New("test")
+	if _, err := obj.GetTimeAlways("missing"); err != ErrKeyWasMissing {
+		t.Error("expected ErrKeyWasMissing, got", err)
+	}
+}
+
+func TestSetBytes(t *testing.T) {
+	obj := New("test")
+	obj.Set("blob", []byte{0, 1, 2})
+	obj.MarkDirty(false)
+	obj.ResetChangedColumns()
+
+	// Setting an equal slice again is not a change
+	obj.Set("blob", []byte{0, 1, 2})
+	if obj.IsDirty() || len(obj.ChangedColumns) != 0 {
+		t.Fatal("expected an equal []byte to leave the object clean")
+	}
+
+	obj.Set("blob", []byte{0, 1, 3})
+	if !obj.IsDirty() || len(obj.ChangedColumns) != 1 {
+		t.Fatal("expected a different []byte to be tracked as a change")
+	}
+	if b, ok := obj.GetBytes("blob"); !ok || len(b) != 3 || b[2] != 3 {
+		t.Fatal("unexpected bytes", obj.Get("blob"))
+	}
+}
+
+func TestSetIfChanged(t *testing.T) {
+	obj := New("person")
+	obj.SetCore("age", int64(30))
+	obj.SetCore("name", "Ryan")
+	obj.SetCore("nickname", NewNULLValue())
+	obj.MarkDirty(false)
+
+	if obj.SetIfChanged("age", 30) || obj.SetIfChanged("age", int64(30)) {
+		t.Fatal("expected equal ints not to be set")
+	}
+	if obj.SetIfChanged("name", "Ryan") || obj.SetIfChanged("name", sql.NullString{String: "Ryan", Valid: true}) {
+		t.Fatal("expected equal strings not to be set")
+	}
+	if obj.SetIfChanged("nickname", nil) || obj.SetIfChanged("nickname", sql.NullString{}) {
+		t.Fatal("expected NULL not to replace NULL")
+	}
+	if obj.IsDirty() || len(obj.ChangedColumns) != 0 {
+		t.Fatal("expected unchanged values to leave the object clean", obj.ChangedColumns)
+	}
+
+	if !obj.SetIfChanged("nickname", "Ry") {
+		t.Fatal("expected a value to replace NULL")
+	}
+	if !obj.ValueIsNULL(obj.ChangedColumns["nickname"]) || !obj.IsDirty() {
+		t.Fatal("expected the change from NULL to be tracked", obj.ChangedColumns)
+	}
+	if !obj.SetIfChanged("age", nil) {
+		t.Fatal("expected NULL to replace a value")
+	}
+	if obj.ChangedColumns["age"] != int64(30) {
+		t.Fatal("expected the change to NULL to be tracked", obj.ChangedColumns)
+	}
+	if !obj.SetIfChanged("name", "Bob") || obj.Get("name") != "Bob" {
+		t.Fatal("expected a different string to be set")
+	}
+	if !obj.SetIfChanged("email", nil) {
+		t.Fatal("expected a missing key to be set")
+	}
+}
+
+func TestSetNull(t *testing.T) {
+	obj := New("person")
+	obj.SetCore("age", int64(30))
+	obj.MarkDirty(false)
+
+	obj.SetNull("age")
+	obj.SetNull("nickname")
+	if !obj.ValueIsNULL(obj.Get("age")) || !obj.ValueIsNULL(obj.Get("nickname")) {
+		t.Fatal("expected SetNull to set NULL values", obj.KV)
+	}
+	if _, ok := obj.ChangedColumns["nickname"]; !ok || obj.ChangedColumns["age"] != int64(30) {
+		t.Fatal("expected both keys to be recorded as changed", obj.ChangedColumns)
+	}
+	if !obj.IsDirty() {
+		t.Fatal("expected SetNull to mark the object dirty")
+	}
+
+	if obj.ValueIsNULL(nil) || !obj.ValueIsNULL(sql.NullInt64{}) || obj.ValueIsNULL(sql.NullInt64{Valid: true}) {
+		t.Fatal("unexpected ValueIsNULL result")
+	}
+}
+
+func TestFields(t *testing.T) {
+	obj := New("person")
+	obj.Set("name", "Ryan")
+	obj.Set("age", 30)
+	obj.Set("id", int64(1))
+	obj.Children["pets"] = NewArray(New("pets"))
+
+	fields := obj.Fields()
+	if fmt.Sprint(fields) != "[age id name]" {
+		t.Fatal("expected sorted fields without children, got", fields)
+	}
+
+	var keys []string
+	var vals []interface{}
+	obj.Each(func(k string, v interface{}) {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	})
+	if fmt.Sprint(keys) != "[age id name]" || fmt.Sprint(vals) != "[30 1 Ryan]" {
+		t.Fatal("unexpected Each order", keys, vals)
+	}
+
+	if len(New("person").Fields()) != 0 {
+		t.Fatal("expected no fields for an empty object")
+	}
+}
+
+func TestFieldIsDirty(t *testing.T) {
+	obj := New("person")
+	obj.Set("name", "Ryan")
+	obj.Set("age", 30)
+	if !obj.FieldIsDirty("name") || !obj.FieldIsDirty("age") || obj.FieldIsDirty("id") {
+		t.Fatal("expected the fields of a new object to be dirty")
+	}
+
+	// As if it had just been saved
+	obj.ResetChangedColumns()
+	obj.MarkDirty(false)
+	if obj.FieldIsDirty("name") || obj.FieldIsDirty("age") {
+		t.Fatal("expected no dirty fields once saved")
+	}
+
+	obj.Set("age", 31)
+	if !obj.FieldIsDirty("age") || obj.FieldIsDirty("name") {
+		t.Fatal("expected only the changed field to be dirty", obj.ChangedColumns)
+	}
+	obj.Set("name", "Ryan")
+	if obj.FieldIsDirty("name") {
+		t.Fatal("expected setting the same value not to make a field dirty")
+	}
+}
@@ -0,0 +1,91 @@
+package object
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
+// NewFromSchema returns a new object for table with a key for every column
+// of the table, and an empty Children entry for every child table, ready to
+// be filled in before it is saved. Columns with a DefaultValue start with it,
+// other columns which allow NULL start as NULL, and the rest start with the
+// zero value for their type. The primary key is left unset (unless the
+// caller supplies it), so that saving the object inserts it, and read-only
+// columns are left for the database to fill in.
+func NewFromSchema(sch *schema.Schema, table string) (*Object, error) {
+	tbl := sch.GetTable(table)
+	if tbl == nil {
+		return nil, errors.New("object: NewFromSchema: unknown table " + table)
+	}
+
+	obj := New(table)
+	for name, col := range tbl.Columns {
+		if col.IsIdentity || col.IsReadOnly() || (name == tbl.Primary && !tbl.CallerSuppliesPK) {
+			continue
+		}
+		obj.Set(name, initialValue(col))
+	}
+	for childName := range tbl.Children {
+		obj.Children[childName] = Array{}
+	}
+	return obj, nil
+}
+
+// initialValue is the value a column starts with in NewFromSchema.
+func initialValue(col *schema.Column) interface{} {
+	if col.DefaultValue != "" {
+		return defaultValue(col)
+	}
+	if col.AllowNull {
+		return NewNULLValue()
+	}
+	switch {
+	case col.IsBoolean():
+		return false
+	case col.IsDecimal():
+		return "0"
+	case col.IsNumber || col.LogicalType == schema.LogicalInt:
+		return int64(0)
+	case col.LogicalType == schema.LogicalFloat:
+		return float64(0)
+	case col.LogicalType == schema.LogicalTimestamp:
+		return time.Time{}
+	case col.LogicalType == schema.LogicalBlob:
+		return []byte{}
+	}
+	return ""
+}
+
+// defaultValue converts a column's DefaultValue to the type of the column.
+// SQL keywords such as CURRENT_TIMESTAMP are kept as SQLValues, so that the
+// database evaluates them.
+func defaultValue(col *schema.Column) interface{} {
+	d := col.DefaultValue
+	if strings.EqualFold(d, "NULL") {
+		return NewNULLValue()
+	}
+	if strings.HasPrefix(strings.ToUpper(d), "CURRENT_") {
+		return NewSQLValue(d)
+	}
+	switch {
+	case col.IsBoolean():
+		if b, err := strconv.ParseBool(d); err == nil {
+			return b
+		}
+	case col.IsDecimal():
+		return d
+	case col.IsNumber || col.LogicalType == schema.LogicalInt:
+		if n, err := strconv.ParseInt(d, 10, 64); err == nil {
+			return n
+		}
+	case col.LogicalType == schema.LogicalFloat:
+		if f, err := strconv.ParseFloat(d, 64); err == nil {
+			return f
+		}
+	}
+	return d
+}
@@ -0,0 +1,66 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
+func TestNewFromSchema(t *testing.T) {
+	sch := schema.DefaultSchema()
+	tbl := schema.DefaultTable()
+	tbl.Name = "widgets"
+	tbl.Primary = "WidgetID"
+	for _, c := range []struct {
+		name, logicalType, defaultValue string
+		allowNull, isNumber             bool
+	}{
+		{name: "WidgetID", isNumber: true},
+		{name: "Age", isNumber: true, defaultValue: "7"},
+		{name: "Color", logicalType: schema.LogicalString},
+		{name: "Created", logicalType: schema.LogicalTimestamp, defaultValue: "CURRENT_TIMESTAMP"},
+		{name: "Active", logicalType: schema.LogicalBool, allowNull: true},
+		{name: "Weight", logicalType: schema.LogicalFloat},
+	} {
+		col := schema.DefaultColumn()
+		col.Name = c.name
+		col.LogicalType = c.logicalType
+		col.DefaultValue = c.defaultValue
+		col.AllowNull = c.allowNull
+		col.IsNumber = c.isNumber
+		tbl.Columns[c.name] = col
+	}
+	tbl.Columns["WidgetID"].IsIdentity = true
+	tbl.Children["parts"] = schema.DefaultChildTable()
+	sch.Tables["widgets"] = tbl
+
+	obj, err := NewFromSchema(sch, "widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range tbl.Columns {
+		_, ok := obj.KV[name]
+		if name == "WidgetID" && ok {
+			t.Fatal("expected the primary key to be left unset")
+		}
+		if name != "WidgetID" && !ok {
+			t.Error("expected a key for column", name)
+		}
+	}
+	if obj.Get("Age") != int64(7) || obj.Get("Color") != "" || obj.Get("Weight") != float64(0) {
+		t.Fatal("unexpected initial values", obj.KV)
+	}
+	if !obj.ValueIsNULL(obj.Get("Active")) {
+		t.Fatal("expected a nullable column to start as NULL, got", obj.Get("Active"))
+	}
+	if sv, ok := obj.Get("Created").(*SQLValue); !ok || sv.String() != "CURRENT_TIMESTAMP" {
+		t.Fatal("expected a keyword default to be a SQLValue, got", obj.Get("Created"))
+	}
+	if children, ok := obj.Children["parts"]; !ok || children == nil {
+		t.Fatal("expected an empty Children entry for parts")
+	}
+
+	if _, err := NewFromSchema(sch, "nope"); err == nil {
+		t.Fatal("expected an error for an unknown table")
+	}
+}
@@ -1,10 +1,24 @@
 package object
 
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
 // SQLValue struct is for encapsulating raw SQL Function calls.
 // For example, if we want to use SYS_GUID() as a value for an
 // INSERT with Oracle, or LAST_INSERT_ID() as a value for an INSERT with MySQL.
 // It's meant to be stored in an object's KV, so that it's type
 // can be detected and it can be rendered appropriately into a string value.
+//
+// Any column may hold one: inserts render it in the column's VALUES slot and
+// updates in its SET clause instead of a bind placeholder, so that a column
+// can be filled in by the database, such as a timestamp set with NOW() or a
+// counter updated with Count + 1.
+//
+// A SQLValue is rendered into the statement as-is, so it is for trusted SQL
+// fragments only and must never carry user input. A string literal that has
+// to be embedded should be rendered with the SQLGenerator's QuoteLiteral.
 type SQLValue struct {
 	Value string
 }
@@ -33,15 +47,31 @@ func NewNULLValue() *SQLValue {
 	return &SQLValue{Value: "NULL"}
 }
 
+// ValueIsNULL reports whether v is a NULL value: a NULL SQLValue, such as
+// those set by SetNull, or an invalid sql.Null* value scanned from a NULL
+// column. A nil v, as returned by Get for a missing key, is not NULL.
 func (o Object) ValueIsNULL(v interface{}) bool {
-	switch v.(type) {
+	if v == nil {
+		return false
+	}
+	return mapValue(v) == nil
+}
+
+// IsNULL reports whether v stands for NULL as a query value: nil, a NULL
+// SQLValue, or a driver.Valuer, such as an invalid sql.NullString, whose
+// value is nil.
+func IsNULL(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
 	case *SQLValue:
-		sv := v.(*SQLValue)
-		if sv.Value == "NULL" {
+		return t == nil || t.Value == "NULL"
+	case driver.Valuer:
+		if rv := reflect.ValueOf(t); rv.Kind() == reflect.Ptr && rv.IsNil() {
 			return true
 		}
-		return false
-	default:
-		return false
+		val, err := t.Value()
+		return err == nil && val == nil
 	}
+	return false
 }
@@ -0,0 +1,194 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// structField is a struct field mapped to an object key.
+type structField struct {
+	key   string
+	index int
+}
+
+// structFields returns the exported fields of struct type t with the object
+// keys they map to. A `dyndao:"Key"` tag names the key, `dyndao:"-"` skips
+// the field, and untagged fields use the field's name.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		key := f.Name
+		if tag, ok := f.Tag.Lookup("dyndao"); ok {
+			tag = strings.TrimSpace(strings.Split(tag, ",")[0])
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				key = tag
+			}
+		}
+		fields = append(fields, structField{key: key, index: i})
+	}
+	return fields
+}
+
+// FromStruct returns a new object of type table holding the fields of the
+// struct v, or of the struct v points to, keyed as described by their
+// dyndao tags. Nil pointer fields become NULL values, and other pointer
+// fields are stored as the value they point to.
+func FromStruct(table string, v interface{}) (*Object, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("object: FromStruct: nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("object: FromStruct: expected a struct, got %T", v)
+	}
+
+	obj := New(table)
+	for _, f := range structFields(rv.Type()) {
+		fv := rv.Field(f.index)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				obj.Set(f.key, NewNULLValue())
+				continue
+			}
+			fv = fv.Elem()
+		}
+		obj.Set(f.key, fv.Interface())
+	}
+	return obj, nil
+}
+
+// ToStruct copies the object's values into the struct dest points to, using
+// the same field mapping as FromStruct. NULL values leave pointer fields nil
+// and other fields at their zero value, so nullable columns should be mapped
+// to pointer fields. Numeric values are converted to the field's numeric
+// type, and []byte values to string fields. Fields with no value in the
+// object are left alone.
+func (o *Object) ToStruct(dest interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("object: ToStruct: expected a pointer to a struct, got %T", dest)
+	}
+	rv = rv.Elem()
+
+	for _, f := range structFields(rv.Type()) {
+		v, ok := o.KV[f.key]
+		if !ok {
+			continue
+		}
+		fv := rv.Field(f.index)
+		v = mapValue(v)
+		if v == nil {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+
+		target := fv.Type()
+		if target.Kind() == reflect.Ptr {
+			target = target.Elem()
+		}
+		cv, err := convertFieldValue(reflect.ValueOf(v), target)
+		if err != nil {
+			return fmt.Errorf("object: ToStruct: field %s: %v", rv.Type().Field(f.index).Name, err)
+		}
+		if fv.Kind() == reflect.Ptr {
+			p := reflect.New(target)
+			p.Elem().Set(cv)
+			cv = p
+		}
+		fv.Set(cv)
+	}
+	return nil
+}
+
+// convertFieldValue converts v to type t where that loses no meaning.
+// reflect would also convert integers to strings (as runes), so conversions
+// are limited to those between numeric types and between strings and []byte.
+// A number t cannot hold, out of its range or with a fractional part for an
+// integer type, is an error rather than silently truncated.
+func convertFieldValue(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if v.Type().ConvertibleTo(t) {
+		switch {
+		case isNumericKind(v.Kind()) && isNumericKind(t.Kind()):
+			if err := checkNumberFits(v, t); err != nil {
+				return reflect.Value{}, err
+			}
+			return v.Convert(t), nil
+		case isStringOrBytes(v.Type()) && isStringOrBytes(t):
+			return v.Convert(t), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", v.Type(), t)
+}
+
+// checkNumberFits returns an error if converting the number v to the
+// numeric type t would overflow it or drop a fractional part.
+func checkNumberFits(v reflect.Value, t reflect.Type) error {
+	out := reflect.New(t).Elem()
+	overflows := false
+	switch {
+	case isIntKind(v.Kind()):
+		n := v.Int()
+		switch {
+		case isIntKind(t.Kind()):
+			overflows = out.OverflowInt(n)
+		case isUintKind(t.Kind()):
+			overflows = n < 0 || out.OverflowUint(uint64(n))
+		}
+	case isUintKind(v.Kind()):
+		n := v.Uint()
+		switch {
+		case isIntKind(t.Kind()):
+			overflows = n > math.MaxInt64 || out.OverflowInt(int64(n))
+		case isUintKind(t.Kind()):
+			overflows = out.OverflowUint(n)
+		}
+	default:
+		f := v.Float()
+		if (isIntKind(t.Kind()) || isUintKind(t.Kind())) && f != math.Trunc(f) {
+			return fmt.Errorf("%v has a fractional part, which %s cannot hold", f, t)
+		}
+		switch {
+		case isIntKind(t.Kind()):
+			overflows = f < math.MinInt64 || f >= -math.MinInt64 || out.OverflowInt(int64(f))
+		case isUintKind(t.Kind()):
+			overflows = f < 0 || f >= 1<<64 || out.OverflowUint(uint64(f))
+		default:
+			overflows = out.OverflowFloat(f)
+		}
+	}
+	if overflows {
+		return fmt.Errorf("%v overflows %s", v, t)
+	}
+	return nil
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isStringOrBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.String ||
+		(t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8)
+}
@@ -0,0 +1,103 @@
+package object
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type structPerson struct {
+	ID       int64   `dyndao:"PersonID"`
+	Name     string  `dyndao:"Name"`
+	Nickname *string `dyndao:"Nickname"`
+	Age      int
+	Ignored  string `dyndao:"-"`
+	private  string
+}
+
+func TestFromStruct(t *testing.T) {
+	obj, err := FromStruct("people", &structPerson{ID: 1, Name: "Ryan", Age: 30, Ignored: "x", private: "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Get("PersonID") != int64(1) || obj.Get("Name") != "Ryan" || obj.Get("Age") != 30 {
+		t.Fatal("unexpected values", obj.KV)
+	}
+	if !obj.ValueIsNULL(obj.Get("Nickname")) {
+		t.Fatal("expected a nil pointer field to become NULL, got", obj.Get("Nickname"))
+	}
+	if len(obj.KV) != 4 {
+		t.Fatal("expected skipped and unexported fields to be left out", obj.KV)
+	}
+
+	nick := "Ry"
+	obj, err = FromStruct("people", structPerson{Nickname: &nick})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Get("Nickname") != "Ry" {
+		t.Fatal("expected a pointer field to be stored as its value, got", obj.Get("Nickname"))
+	}
+
+	if _, err := FromStruct("people", 5); err == nil {
+		t.Fatal("expected an error for a non-struct")
+	}
+}
+
+func TestToStruct(t *testing.T) {
+	obj := New("people")
+	obj.Set("PersonID", int64(7))
+	obj.Set("Name", sql.NullString{String: "Ryan", Valid: true})
+	obj.Set("Nickname", sql.NullString{String: "Ry", Valid: true})
+	obj.Set("Age", int64(30))
+
+	var p structPerson
+	if err := obj.ToStruct(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != 7 || p.Name != "Ryan" || p.Age != 30 || p.Nickname == nil || *p.Nickname != "Ry" {
+		t.Fatal("unexpected struct", p)
+	}
+
+	obj.Set("Nickname", NewNULLValue())
+	if err := obj.ToStruct(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Nickname != nil {
+		t.Fatal("expected NULL to leave the pointer field nil")
+	}
+
+	var small struct {
+		Count  int8    `dyndao:"Count"`
+		Size   uint16  `dyndao:"Size"`
+		Weight float32 `dyndao:"Weight"`
+	}
+	for _, tc := range []struct {
+		key string
+		v   interface{}
+		ok  bool
+	}{
+		{"Count", int64(127), true},
+		{"Count", int64(128), false},
+		{"Count", float64(12), true},
+		{"Count", float64(1.5), false},
+		{"Size", int64(-1), false},
+		{"Size", uint64(70000), false},
+		{"Size", float64(-0.5), false},
+		{"Weight", float64(2.5), true},
+		{"Weight", float64(1e300), false},
+	} {
+		values := New("sizes")
+		values.Set(tc.key, tc.v)
+		if err := values.ToStruct(&small); (err == nil) != tc.ok {
+			t.Fatalf("converting %v (%T) to %s: unexpected error %v", tc.v, tc.v, tc.key, err)
+		}
+	}
+
+	obj.Set("Name", int64(5))
+	if err := obj.ToStruct(&p); err == nil {
+		t.Fatal("expected an error converting an integer to a string field")
+	}
+	if err := obj.ToStruct(p); err == nil {
+		t.Fatal("expected an error for a non-pointer destination")
+	}
+}
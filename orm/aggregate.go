@@ -0,0 +1,114 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
+)
+
+// Agg describes a COUNT, SUM, AVG, MIN or MAX aggregate on a column, see
+// query.Count, query.Sum, etc.
+type Agg = query.Aggregate
+
+// Having is a HAVING predicate on an aggregate.
+type Having = query.Having
+
+// Aggregate runs an aggregate query over table, grouped by groupCols, and
+// returns one clean object per group. Each object holds the group columns
+// and the aggregate results, keyed by Agg.Name(). Aggregate results are
+// returned as the driver reports them (typically int64 or float64).
+func (o ORM) Aggregate(ctx context.Context, table string, groupCols []string, aggs []Agg, having ...Having) (object.Array, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	start := o.startObserve()
+	objs, err := o.aggregate(ctx, table, groupCols, aggs, having)
+	o.observe("Aggregate", table, start, err)
+	return objs, err
+}
+
+func (o ORM) aggregate(ctx context.Context, table string, groupCols []string, aggs []Agg, having []Having) (object.Array, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	sg := o.sqlGen
+	sqlStr, columnNames, bindArgs, err := sg.BindingAggregate(sg, o.s, table, groupCols, aggs, having)
+
+	if sg.Tracing {
+		fmt.Println("Aggregate/sqlStr=", sqlStr, "columnNames=", columnNames, "bindArgs=", bindArgs)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := stmtFromReaderOrTx(ctx, o, nil, sqlStr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := stmt.Close()
+		if err != nil {
+			fmt.Println(err) // TODO logger implementation
+		}
+	}()
+
+	res, err := stmt.QueryContext(ctx, bindArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := res.Close()
+		if err != nil {
+			fmt.Println(err) // TODO logger implementation
+		}
+	}()
+
+	// Aggregate expressions have no declared column type with some drivers,
+	// so scan into interface{} rather than using MakeColumnPointers.
+	return scanGeneric(ctx, res, table, columnNames)
+}
+
+// scanGeneric scans every row of res into a clean object of type table, one
+// value per column in columnNames, without consulting the schema. []byte
+// values become strings and NULL values are left out.
+func scanGeneric(ctx context.Context, res *sql.Rows, table string, columnNames []string) (object.Array, error) {
+	values := make([]interface{}, len(columnNames))
+	columnPointers := make([]interface{}, len(columnNames))
+	for i := range values {
+		columnPointers[i] = &values[i]
+	}
+
+	var objectArray object.Array
+	for res.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := res.Scan(columnPointers...); err != nil {
+			return nil, err
+		}
+		obj := object.New(table)
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			if v != nil {
+				obj.Set(columnNames[i], v)
+			}
+		}
+		obj.MarkDirty(false)
+		obj.ResetChangedColumns()
+		objectArray = append(objectArray, obj)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+	return objectArray, nil
+}
@@ -0,0 +1,214 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// bulkInsertMaxBinds bounds the bind arguments of a single multi-row INSERT,
+// staying below SQL Server's limit of 2100 parameters per statement.
+const bulkInsertMaxBinds = 2000
+
+// BulkInsert inserts objs, which must all be of the same type, setting the
+// primary key on each of them as Insert does. Consecutive objects that hold
+// values for the same columns are inserted with multi-row INSERT statements
+// where the dialect has them, reading the generated keys back with RETURNING
+// (SQLite). RETURNING may emit the rows in any order, but SQLite allocates
+// the keys in the order of the VALUES list, so they are handed to the
+// objects in ascending order, preserving the order of objs. Dialects that
+// make no such promise, or cannot return the keys (SQL Server, MySQL and
+// Oracle), insert the objects of tables with generated keys one at a time.
+// Without a transaction, BulkInsert runs in one of its own, so that either
+// every object is inserted or none is.
+func (o ORM) BulkInsert(ctx context.Context, tx *sql.Tx, objs object.Array) (rowsAff int64, err error) {
+	if len(objs) == 0 {
+		return 0, nil
+	}
+	objType := objs[0].Type
+	ctx, span := o.startSpan(ctx, "BulkInsert", objType)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	defer func() { o.observe("Insert", objType, start, err) }()
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+	objTable := o.s.GetTable(objType)
+	if objTable == nil {
+		return 0, errors.New("BulkInsert: unknown object table " + objType)
+	}
+	for _, obj := range objs {
+		if obj.Type != objType {
+			return 0, fmt.Errorf("BulkInsert: object of type %s amongst objects of type %s", obj.Type, objType)
+		}
+	}
+
+	if tx != nil {
+		return o.bulkInsert(ctx, tx, objTable, objs)
+	}
+	tx, err = o.RawConn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "BulkInsert: begin")
+	}
+	rowsAff, err = o.bulkInsert(ctx, tx, objTable, objs)
+	if err != nil {
+		return 0, rollback(tx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "BulkInsert: commit")
+	}
+	return rowsAff, nil
+}
+
+func (o ORM) bulkInsert(ctx context.Context, tx *sql.Tx, objTable *schema.Table, objs object.Array) (int64, error) {
+	keySources := make([]KeySource, len(objs))
+	for i, obj := range objs {
+		var err error
+		if keySources[i], err = o.prepareInsert(objTable, obj); err != nil {
+			return 0, err
+		}
+	}
+
+	var rowsAff int64
+	for start := 0; start < len(objs); {
+		// Each statement holds a run of objects with the same columns
+		perStmt := bulkChunkSize
+		if n := len(objs[start].KV); n > 0 && bulkInsertMaxBinds/n < perStmt {
+			perStmt = bulkInsertMaxBinds / n
+			if perStmt == 0 {
+				perStmt = 1
+			}
+		}
+		end := start + 1
+		for end < len(objs) && end-start < perStmt && sameFields(objs[start], objs[end]) {
+			end++
+		}
+
+		aff, err := o.bulkInsertRun(ctx, tx, objTable, objs[start:end], keySources[start:end])
+		rowsAff += aff
+		if err != nil {
+			return rowsAff, err
+		}
+		start = end
+	}
+	return rowsAff, nil
+}
+
+// bulkInsertRun inserts objects holding values for the same columns with a
+// single statement, or one at a time if the dialect can't.
+func (o ORM) bulkInsertRun(ctx context.Context, tx *sql.Tx, objTable *schema.Table, objs object.Array, keySources []KeySource) (int64, error) {
+	sg := o.sqlGen
+	var sqlStr string
+	var bindArgs []interface{}
+	if len(objs) > 1 {
+		rows := make([]map[string]interface{}, len(objs))
+		for i, obj := range objs {
+			var err error
+			if rows[i], err = o.encodeValues(obj, obj.KV); err != nil {
+				return 0, err
+			}
+		}
+		var err error
+		sqlStr, bindArgs, err = sg.BindingBulkInsert(sg, o.s, objs[0].Type, rows)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if sqlStr == "" {
+		var rowsAff int64
+		for i, obj := range objs {
+			res, err := o.execInsert(ctx, tx, objTable, obj, keySources[i])
+			rowsAff += res.RowsAffected
+			if err != nil {
+				return rowsAff, err
+			}
+		}
+		return rowsAff, nil
+	}
+	if sg.Tracing {
+		fmt.Println("BulkInsert/sqlStr=", sqlStr, "bindArgs=", bindArgs)
+	}
+
+	stmt, err := stmtFromDbOrTx(ctx, o, tx, sqlStr)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := stmt.Close(); err != nil {
+			fmt.Println("BulkInsert stmt.Close error=", err) // TODO: logging implementation
+		}
+	}()
+
+	var rowsAff int64
+	if objTable.UsesLastInsertID() {
+		keys, err := queryKeys(ctx, stmt, bindArgs)
+		if err != nil {
+			return 0, err
+		}
+		if len(keys) != len(objs) {
+			return 0, fmt.Errorf("BulkInsert: the database reported %d primary keys for %d rows of table %s", len(keys), len(objs), objTable.Name)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		for i, obj := range objs {
+			obj.SetCore(objTable.Primary, keys[i])
+		}
+		rowsAff = int64(len(keys))
+	} else {
+		res, err := stmt.ExecContext(ctx, bindArgs...)
+		if err != nil {
+			return 0, errors.Wrap(err, "BulkInsert/ExecContext")
+		}
+		if rowsAff, err = res.RowsAffected(); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, obj := range objs {
+		if err := o.finishInsert(obj); err != nil {
+			return rowsAff, err
+		}
+	}
+	return rowsAff, nil
+}
+
+// queryKeys runs a multi-row INSERT returning the generated keys.
+func queryKeys(ctx context.Context, stmt *sql.Stmt, bindArgs []interface{}) ([]int64, error) {
+	rows, err := stmt.QueryContext(ctx, bindArgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "BulkInsert/QueryContext")
+	}
+	defer rows.Close()
+	var keys []int64
+	for rows.Next() {
+		var key int64
+		if err := rows.Scan(&key); err != nil {
+			return nil, errors.Wrap(err, "BulkInsert/Scan")
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "BulkInsert/Next")
+	}
+	return keys, nil
+}
+
+// sameFields reports whether a and b hold values for the same fields.
+func sameFields(a, b *object.Object) bool {
+	if len(a.KV) != len(b.KV) {
+		return false
+	}
+	for k := range a.KV {
+		if _, ok := b.KV[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,243 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// BulkUpdate updates objs, which must all be of the same type and hold
+// their primary keys, calling the update hooks and marking them saved as
+// Update does. Like Update, each object writes its changed columns, or all
+// of its fields if none are marked as changed.
+//
+// Rather than one UPDATE per object, consecutive objects that write the same
+// columns are loaded into a temporary table, with multi-row INSERTs, and
+// applied with a single UPDATE joining it on the primary key: UPDATE ... FROM
+// in SQLite and PostgreSQL, a multi-table UPDATE in MySQL and UPDATE ...
+// FROM ... JOIN in SQL Server. This costs four statements per run (create,
+// load, update, drop) whatever its length, so it pays off for large sets of
+// rows, such as ETL workloads, while for a handful of objects Update is as
+// fast. Oracle, and runs of a single object, are updated one at a time.
+// objs should not repeat a key, as which of the values would be applied is
+// up to the database. Without a transaction, BulkUpdate runs in one of its
+// own, so that either every object is updated or none is.
+func (o ORM) BulkUpdate(ctx context.Context, tx *sql.Tx, objs object.Array) (rowsAff int64, err error) {
+	if len(objs) == 0 {
+		return 0, nil
+	}
+	objType := objs[0].Type
+	ctx, span := o.startSpan(ctx, "BulkUpdate", objType)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	defer func() { o.observe("Update", objType, start, err) }()
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+	objTable := o.s.GetTable(objType)
+	if objTable == nil {
+		return 0, errors.New("BulkUpdate: unknown object table " + objType)
+	}
+	for _, obj := range objs {
+		if obj.Type != objType {
+			return 0, fmt.Errorf("BulkUpdate: object of type %s amongst objects of type %s", obj.Type, objType)
+		}
+	}
+
+	if tx != nil {
+		return o.bulkUpdate(ctx, tx, objTable, objs)
+	}
+	tx, err = o.RawConn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "BulkUpdate: begin")
+	}
+	rowsAff, err = o.bulkUpdate(ctx, tx, objTable, objs)
+	if err != nil {
+		return 0, rollback(tx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "BulkUpdate: commit")
+	}
+	return rowsAff, nil
+}
+
+func (o ORM) bulkUpdate(ctx context.Context, tx *sql.Tx, objTable *schema.Table, objs object.Array) (int64, error) {
+	keyCols := objTable.PrimaryKeyColumns()
+	setCols := make([][]string, len(objs))
+	for i, obj := range objs {
+		if err := o.CallBeforeUpdateHookIfNeeded(obj); err != nil {
+			return 0, err
+		}
+		if err := o.applyLengthPolicy(objTable, obj); err != nil {
+			return 0, err
+		}
+		var err error
+		if setCols[i], err = bulkUpdateColumns(objTable, keyCols, obj); err != nil {
+			return 0, err
+		}
+	}
+
+	var rowsAff int64
+	for start := 0; start < len(objs); {
+		// Each temporary table holds a run of objects writing the same columns
+		perStmt := bulkChunkSize
+		if n := len(keyCols) + len(setCols[start]); bulkInsertMaxBinds/n < perStmt {
+			perStmt = bulkInsertMaxBinds / n
+			if perStmt == 0 {
+				perStmt = 1
+			}
+		}
+		end := start + 1
+		for end < len(objs) && end-start < perStmt && sameColumns(setCols[start], setCols[end]) {
+			end++
+		}
+
+		aff, err := o.bulkUpdateRun(ctx, tx, objTable, keyCols, setCols[start], objs[start:end])
+		rowsAff += aff
+		if err != nil {
+			return rowsAff, err
+		}
+		start = end
+	}
+	return rowsAff, nil
+}
+
+// bulkUpdateRun updates objects writing the same columns through a
+// temporary table, or one at a time if the dialect can't.
+func (o ORM) bulkUpdateRun(ctx context.Context, tx *sql.Tx, objTable *schema.Table, keyCols []string, setCols []string, objs object.Array) (int64, error) {
+	g := o.sqlGen
+	var bu *sg.BulkUpdate
+	if len(objs) > 1 {
+		rows := make([]map[string]interface{}, len(objs))
+		for i, obj := range objs {
+			row := make(map[string]interface{}, len(keyCols)+len(setCols))
+			for _, c := range append(append([]string{}, keyCols...), setCols...) {
+				row[c] = columnValue(objTable, obj, c)
+			}
+			var err error
+			if rows[i], err = o.encodeValues(obj, row); err != nil {
+				return 0, err
+			}
+		}
+		var err error
+		bu, err = g.BindingBulkUpdate(g, o.s, objs[0].Type, keyCols, setCols, rows)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var rowsAff int64
+	if bu == nil {
+		for _, obj := range objs {
+			aff, err := o.execUpdate(ctx, tx, obj)
+			rowsAff += aff
+			if err != nil {
+				return rowsAff, err
+			}
+		}
+	} else {
+		var err error
+		if rowsAff, err = o.execBulkUpdate(ctx, tx, bu); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, obj := range objs {
+		if err := o.finishUpdate(obj); err != nil {
+			return rowsAff, err
+		}
+	}
+	return rowsAff, nil
+}
+
+// execBulkUpdate runs the statements of a bulk update, returning the rows
+// affected by its UPDATE. The temporary table is dropped even if the update
+// fails, for dialects where it would outlive the rolled back transaction.
+func (o ORM) execBulkUpdate(ctx context.Context, tx *sql.Tx, bu *sg.BulkUpdate) (rowsAff int64, err error) {
+	exec := func(sqlStr string, args ...interface{}) (sql.Result, error) {
+		o.traceSQL(ctx, sqlStr)
+		if o.sqlGen.Tracing {
+			fmt.Println("BulkUpdate/sqlStr=", sqlStr, "bindArgs=", args)
+		}
+		res, err := tx.ExecContext(ctx, sqlStr, args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "BulkUpdate/"+sqlStr)
+		}
+		return res, nil
+	}
+
+	if _, err := exec(bu.Create); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if _, dropErr := exec(bu.Drop); dropErr != nil && err == nil {
+			err = dropErr
+		}
+	}()
+
+	if _, err := exec(bu.Load, bu.LoadArgs...); err != nil {
+		return 0, err
+	}
+	res, err := exec(bu.Update)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// bulkUpdateColumns returns the columns BulkUpdate writes for obj, in column
+// order: its changed columns, or all of its fields, leaving out the key
+// columns, which it must hold, and the columns the database populates.
+func bulkUpdateColumns(objTable *schema.Table, keyCols []string, obj *object.Object) ([]string, error) {
+	isKey := make(map[string]bool, len(keyCols))
+	for _, k := range keyCols {
+		isKey[k] = true
+		if columnValue(objTable, obj, k) == nil {
+			return nil, errors.New("BulkUpdate: no value for primary key column " + k + " of object Type: " + obj.Type)
+		}
+	}
+
+	fields := obj.ChangedColumns
+	if len(fields) == 0 {
+		fields = obj.KV
+	}
+	var cols []string
+	for k := range fields {
+		f := objTable.GetColumn(k)
+		if f == nil {
+			return nil, errors.New("BulkUpdate: field config unavailable for object Type: " + obj.Type + ", key: " + k)
+		}
+		name := objTable.GetColumnName(k)
+		if f.IsIdentity || f.IsReadOnly() || isKey[name] {
+			continue
+		}
+		cols = append(cols, name)
+	}
+	if len(cols) == 0 {
+		return nil, errors.New("BulkUpdate: no writable columns to update for object Type: " + obj.Type)
+	}
+	objTable.SortColumns(cols)
+	return cols, nil
+}
+
+// sameColumns reports whether a and b, which are sorted, are the same
+// columns.
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
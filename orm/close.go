@@ -0,0 +1,27 @@
+package orm
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Close closes RawConn and every reader, unless KeepDBOpen is set because the
+// databases are shared with other code. The ORM must not be used afterwards.
+// The errors of every failed close are joined together.
+func (o *ORM) Close() error {
+	if o.KeepDBOpen {
+		return nil
+	}
+	var errs []error
+	closed := make(map[*sql.DB]bool, len(o.readers)+1)
+	for _, db := range append([]*sql.DB{o.RawConn}, o.readers...) {
+		if db == nil || closed[db] {
+			continue
+		}
+		closed[db] = true
+		if err := db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,143 @@
+package orm
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// Codec converts the values of a column between the Go values an
+// application works with and the values stored in the database, such as a
+// map stored as JSON text. Encode is called with the values bound by
+// inserts and updates, and with those matched by retrieves and deletes, so
+// it should encode equal values alike. Decode is called with the values
+// retrieved. Neither is called for NULL.
+type Codec interface {
+	Encode(value interface{}) (driver.Value, error)
+	Decode(src interface{}) (interface{}, error)
+}
+
+// RegisterCodec makes the ORM encode and decode the values of a column with
+// c. table is the name of a schema table, and column one of its columns.
+func (o ORM) RegisterCodec(table, column string, c Codec) {
+	table = o.s.GetTableName(table)
+	if schTable := o.s.GetTable(table); schTable != nil {
+		column = schTable.GetColumnName(column)
+	}
+	if o.Codecs[table] == nil {
+		o.Codecs[table] = make(map[string]Codec)
+	}
+	o.Codecs[table][column] = c
+}
+
+// encodeValues returns data, values of obj keyed by field name, with the
+// values of the columns that have a Codec encoded. data itself is returned
+// when there are none, and is never modified.
+func (o ORM) encodeValues(obj *object.Object, data map[string]interface{}) (map[string]interface{}, error) {
+	codecs := o.Codecs[o.s.GetTableName(obj.Type)]
+	schTable := o.s.GetTable(obj.Type)
+	if len(codecs) == 0 || schTable == nil {
+		return data, nil
+	}
+
+	var encoded map[string]interface{}
+	for k, v := range data {
+		c := codecs[schTable.GetColumnName(k)]
+		if c == nil || v == nil || obj.ValueIsNULL(v) {
+			continue
+		}
+		if _, ok := v.(*object.SQLValue); ok {
+			continue
+		}
+		ev, err := c.Encode(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "encode "+obj.Type+"."+k)
+		}
+		if encoded == nil {
+			encoded = make(map[string]interface{}, len(data))
+			for k, v := range data {
+				encoded[k] = v
+			}
+		}
+		encoded[k] = ev
+	}
+	if encoded == nil {
+		return data, nil
+	}
+	return encoded, nil
+}
+
+// encodeObject returns obj, or a copy of it holding the encoded values for
+// the generator to bind, see encodeValues.
+func (o ORM) encodeObject(obj *object.Object) (*object.Object, error) {
+	if len(o.Codecs[o.s.GetTableName(obj.Type)]) == 0 {
+		return obj, nil
+	}
+	kv, err := o.encodeValues(obj, obj.KV)
+	if err != nil {
+		return nil, err
+	}
+	enc := *obj
+	enc.KV = kv
+	return &enc, nil
+}
+
+// decodeValues decodes the retrieved values of obj's columns that have a
+// Codec.
+func (o ORM) decodeValues(objTable *schema.Table, obj *object.Object) error {
+	if objTable == nil {
+		return nil
+	}
+	codecs := o.Codecs[o.s.GetTableName(obj.Type)]
+	if len(codecs) == 0 {
+		return nil
+	}
+	for k, v := range obj.KV {
+		c := codecs[objTable.GetColumnName(k)]
+		if c == nil || obj.ValueIsNULL(v) {
+			continue
+		}
+		dv, err := c.Decode(v)
+		if err != nil {
+			return errors.Wrap(err, "decode "+obj.Type+"."+k)
+		}
+		obj.KV[k] = dv
+	}
+	return nil
+}
+
+// JSONCodec is a Codec storing values as JSON text. Values are decoded as
+// encoding/json decodes into an interface{}: objects as
+// map[string]interface{}, arrays as []interface{} and numbers as float64.
+type JSONCodec struct{}
+
+// Encode marshals value to JSON.
+func (JSONCodec) Encode(value interface{}) (driver.Value, error) {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// Decode unmarshals JSON text, retrieved as a string or []byte.
+func (JSONCodec) Decode(src interface{}) (interface{}, error) {
+	var buf []byte
+	switch s := src.(type) {
+	case string:
+		buf = []byte(s)
+	case []byte:
+		buf = s
+	default:
+		return nil, fmt.Errorf("JSONCodec: cannot decode a %T", src)
+	}
+	var v interface{}
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,120 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryConfig says how long OpenWithRetry keeps trying to connect. The zero
+// value retries until the database answers or refuses the connection for
+// good.
+type RetryConfig struct {
+	// Context bounds the attempts, along with Timeout. It defaults to
+	// context.Background().
+	Context context.Context
+	// Timeout, if set, is how long to keep trying in all.
+	Timeout time.Duration
+	// InitialBackoff is the wait after the first failed attempt, doubled
+	// after each of the next ones up to MaxBackoff. They default to 100ms
+	// and 5s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// IsPermanent reports whether a connection error is not worth
+	// retrying. It defaults to IsPermanentConnectError.
+	IsPermanent func(err error) bool
+}
+
+// OpenWithRetry opens a database and pings it, pinging it again with
+// exponential backoff while it fails, for services that start before their
+// database is ready to accept connections. It gives up when cfg's Context is
+// done or its Timeout elapses, returning the last error, and at once on an
+// error cfg.IsPermanent deems permanent, such as bad credentials, or if
+// sql.Open itself fails.
+func OpenWithRetry(driverName, dsn string, cfg RetryConfig) (*sql.DB, error) {
+	ctx := cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+	backoff := cfg.InitialBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxBackoff := cfg.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Second
+	}
+	isPermanent := cfg.IsPermanent
+	if isPermanent == nil {
+		isPermanent = IsPermanentConnectError
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "OpenWithRetry")
+	}
+	var lastErr error
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return db, nil
+		}
+		if lastErr == nil || ctx.Err() == nil {
+			// A ping cut short by the deadline tells less than the one before
+			lastErr = err
+		}
+		if isPermanent(err) || ctx.Err() != nil {
+			break
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	if closeErr := db.Close(); closeErr != nil {
+		return nil, errors.Wrap(closeErr, "OpenWithRetry: close")
+	}
+	return nil, errors.Wrap(lastErr, "OpenWithRetry")
+}
+
+// permanentConnectErrors are fragments of the connection errors which
+// retrying won't fix: refused credentials and missing databases.
+var permanentConnectErrors = []string{
+	"Error 1044",                     // MySQL: access denied to the database
+	"Error 1045",                     // MySQL: access denied for the user
+	"Error 1049",                     // MySQL: unknown database
+	"password authentication failed", // PostgreSQL
+	"SQLSTATE 28P01",                 // PostgreSQL: invalid password
+	"ORA-01017",                      // Oracle: invalid username or password
+	"ORA-28000",                      // Oracle: account locked
+	"Login failed for user",          // SQL Server
+}
+
+// IsPermanentConnectError reports whether err, from connecting to a
+// database, refuses the connection for good, such as bad credentials, as
+// opposed to a database that is not up yet.
+func IsPermanentConnectError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, s := range permanentConnectErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
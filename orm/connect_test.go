@@ -0,0 +1,99 @@
+package orm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// flakyDriver refuses the first failures[dsn] connections to a database, as
+// one still starting up would, and every connection to "badauth".
+type flakyDriver struct {
+	mu       sync.Mutex
+	failures map[string]int
+	attempts map[string]int
+}
+
+var flaky = &flakyDriver{failures: make(map[string]int), attempts: make(map[string]int)}
+
+func init() {
+	sql.Register("dyndao_flaky", flaky)
+}
+
+func (d *flakyDriver) Open(dsn string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.attempts[dsn]++
+	if dsn == "badauth" {
+		return nil, errors.New("Error 1045 (28000): Access denied for user 'app'@'localhost'")
+	}
+	if d.failures[dsn] > 0 {
+		d.failures[dsn]--
+		return nil, errors.New("dial tcp 127.0.0.1:3306: connect: connection refused")
+	}
+	return flakyConn{}, nil
+}
+
+func (d *flakyDriver) setFailures(dsn string, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures[dsn] = n
+	d.attempts[dsn] = 0
+}
+
+func (d *flakyDriver) attemptsOf(dsn string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.attempts[dsn]
+}
+
+type flakyConn struct{}
+
+func (flakyConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (flakyConn) Close() error                        { return nil }
+func (flakyConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func TestOpenWithRetry(t *testing.T) {
+	flaky.setFailures("starting", 3)
+	db, err := OpenWithRetry("dyndao_flaky", "starting", RetryConfig{Timeout: 5 * time.Second, InitialBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if n := flaky.attemptsOf("starting"); n != 4 {
+		t.Fatal("expected three failed attempts before connecting, got", n-1)
+	}
+}
+
+func TestOpenWithRetryGivesUp(t *testing.T) {
+	flaky.setFailures("down", 1000)
+	start := time.Now()
+	_, err := OpenWithRetry("dyndao_flaky", "down", RetryConfig{Timeout: 50 * time.Millisecond, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatal("expected the last connection error once the timeout elapsed, got", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatal("expected OpenWithRetry to give up at its timeout, took", elapsed)
+	}
+	if n := flaky.attemptsOf("down"); n < 2 {
+		t.Fatal("expected several attempts, got", n)
+	}
+
+	flaky.setFailures("badauth", 0)
+	_, err = OpenWithRetry("dyndao_flaky", "badauth", RetryConfig{Timeout: 5 * time.Second, InitialBackoff: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "Access denied") {
+		t.Fatal("expected the authentication error, got", err)
+	}
+	if n := flaky.attemptsOf("badauth"); n != 1 {
+		t.Fatal("expected a permanent error not to be retried, got attempts:", n)
+	}
+
+	if _, err := OpenWithRetry("no_such_driver", "", RetryConfig{}); err == nil {
+		t.Fatal("expected an unknown driver to fail at once")
+	}
+}
@@ -12,6 +12,10 @@ import (
 // trying to save. It will return rows affected, the resulting object, or an
 // error.
 func (o ORM) CreateOrUpdateTx(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, *object.Object, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+	o = o.writerOnly()
+
 	var err error
 	var retObj *object.Object
 
@@ -46,6 +50,10 @@ func (o ORM) CreateOrUpdate(ctx context.Context, obj *object.Object) (int64, *ob
 }
 
 func (o ORM) CreateOrUpdateKVTx(ctx context.Context, tx *sql.Tx, typ string, queryKV map[string]interface{}, createKV map[string]interface{}) (int64, *object.Object, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+	o = o.writerOnly()
+
 	var err error
 	var retObj *object.Object
 
@@ -86,6 +94,10 @@ func (o ORM) CreateOrUpdateKV(ctx context.Context, typ string, queryKV map[strin
 
 func (o ORM) CreateOrUpdateKVHookUpdate(ctx context.Context, tx *sql.Tx, typ string, queryKV, createKV map[string]interface{}, beforeUpdateCopyColumns []string) (int64, *object.Object, error) {
 
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+	o = o.writerOnly()
+
 	var err error
 	var retObj *object.Object
 
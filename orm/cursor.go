@@ -0,0 +1,193 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+)
+
+// Cursor iterates over the objects matching a retrieval one row at a time,
+// with the same semantics as sql.Rows:
+//
+//	c, err := o.OpenCursor(ctx, table, queryVals)
+//	if err != nil { ... }
+//	defer c.Close()
+//	for c.Next() {
+//		obj := c.Object()
+//		...
+//	}
+//	if err := c.Err(); err != nil { ... }
+//
+// A Cursor holds a prepared statement and a database connection until it is
+// closed, either by calling Close or by iterating to the end. The ORM's
+// Timeout bounds the cursor from when it is opened until it is closed.
+type Cursor struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
+	o              ORM
+	table          string
+	columnNames    []string
+	columnTypes    []*sql.ColumnType
+	columnPointers []interface{}
+	stmt           *sql.Stmt
+	rows           *sql.Rows
+	obj            *object.Object
+	err            error
+	closed         bool
+}
+
+// OpenCursor executes a retrieval for the objects matching queryVals and
+// returns a Cursor positioned before the first row.
+func (o ORM) OpenCursor(ctx context.Context, table string, queryVals map[string]interface{}) (*Cursor, error) {
+	return o.openCursorCore(ctx, nil, table, queryVals)
+}
+
+// OpenCursorTx is OpenCursor inside a transaction.
+func (o ORM) OpenCursorTx(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (*Cursor, error) {
+	return o.openCursorCore(ctx, tx, table, queryVals)
+}
+
+func (o ORM) openCursorCore(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (*Cursor, error) {
+	ctx, cancel := o.boundContext(ctx)
+	start := o.startObserve()
+	c, err := o.openCursor(ctx, tx, table, queryVals)
+	o.observe("Retrieve", table, start, err)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The timeout outlives the call, it is released by Close
+	c.cancel = cancel
+	return c, nil
+}
+
+func (o ORM) openCursor(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (*Cursor, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	sqlStr, columnNames, bindArgs, err := o.renderRetrieve(table, nil, queryVals)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := stmtFromReaderOrTx(ctx, o, tx, sqlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cursor{ctx: ctx, o: o, table: table, columnNames: columnNames, stmt: stmt}
+
+	c.rows, err = stmt.QueryContext(ctx, bindArgs...)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	sg := o.sqlGen
+	c.columnTypes, err = c.rows.ColumnTypes()
+	if err == nil {
+		c.columnPointers, err = sg.MakeColumnPointers(sg, len(columnNames), c.columnTypes)
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Next advances the cursor to the next row, returning false when there are no
+// more rows, an error occurred or the context was cancelled. The cursor is
+// closed automatically once Next returns false.
+func (c *Cursor) Next() bool {
+	if c.closed {
+		return false
+	}
+	c.obj = nil
+
+	if err := c.ctx.Err(); err != nil {
+		c.setErr(err)
+		c.Close()
+		return false
+	}
+	if !c.rows.Next() {
+		c.setErr(c.rows.Err())
+		c.Close()
+		return false
+	}
+	if err := c.rows.Scan(c.columnPointers...); err != nil {
+		c.setErr(err)
+		c.Close()
+		return false
+	}
+	return true
+}
+
+// Object returns the object for the current row. The row is mapped into an
+// object the first time Object is called after Next. Object returns nil if
+// there is no current row or the row could not be mapped, in which case Err
+// reports why.
+func (c *Cursor) Object() *object.Object {
+	if c.obj != nil {
+		return c.obj
+	}
+	if c.closed || c.err != nil {
+		return nil
+	}
+
+	sg := c.o.sqlGen
+	obj := object.New(c.table)
+	err := sg.DynamicObjectSetter(sg, c.columnNames, c.columnPointers, c.columnTypes, obj)
+	if err != nil {
+		c.setErr(errors.Wrap(err, "Cursor.Object"))
+		c.Close()
+		return nil
+	}
+	convertValues(c.o.s.GetTable(c.table), obj)
+	if err := c.o.decodeValues(c.o.s.GetTable(c.table), obj); err != nil {
+		c.setErr(errors.Wrap(err, "Cursor.Object"))
+		c.Close()
+		return nil
+	}
+	obj.MarkDirty(false)
+	obj.ResetChangedColumns()
+	c.obj = obj
+	return obj
+}
+
+// Err returns the error, if any, that ended the iteration.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Close releases the rows, the statement and the underlying connection. It
+// is safe to call Close more than once.
+func (c *Cursor) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	if c.cancel != nil {
+		defer c.cancel()
+	}
+
+	var err error
+	if c.rows != nil {
+		err = c.rows.Close()
+	}
+	if stmtErr := c.stmt.Close(); err == nil {
+		err = stmtErr
+	}
+	return err
+}
+
+func (c *Cursor) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
@@ -11,7 +11,16 @@ import (
 )
 
 // Delete function will DELETE a record ...
-func (o ORM) Delete(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+func (o ORM) Delete(ctx context.Context, tx *sql.Tx, obj *object.Object) (rowsAff int64, err error) {
+	ctx, span := o.startSpan(ctx, "Delete", obj.Type)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	rowsAff, err = o.deleteCore(ctx, tx, obj)
+	o.observe("Delete", obj.Type, start, err)
+	return rowsAff, err
+}
+
+func (o ORM) deleteCore(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
 	sg := o.sqlGen
 
 	select {
@@ -24,7 +33,11 @@ func (o ORM) Delete(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 	if objTable == nil {
 		return 0, errors.New("Delete: unknown object table " + obj.Type)
 	}
-	sqlStr, bindWhere, err := sg.BindingDelete(o.sqlGen, o.s, obj)
+	enc, err := o.encodeObject(obj)
+	if err != nil {
+		return 0, err
+	}
+	sqlStr, bindWhere, err := sg.BindingDelete(o.sqlGen, o.s, enc)
 	if err != nil {
 		return 0, err
 	}
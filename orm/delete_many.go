@@ -0,0 +1,206 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// DeleteMany deletes every row of table matching whereVals, inside tx if it
+// is not nil, and returns the number of rows deleted. whereVals must not be
+// empty; use Truncate to delete every row.
+func (o ORM) DeleteMany(ctx context.Context, tx *sql.Tx, table string, whereVals map[string]interface{}) (rowsAff int64, err error) {
+	ctx, span := o.startSpan(ctx, "Delete", table)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	rowsAff, err = o.deleteMany(ctx, tx, table, whereVals)
+	o.observe("Delete", table, start, err)
+	return rowsAff, err
+}
+
+func (o ORM) deleteMany(ctx context.Context, tx *sql.Tx, table string, whereVals map[string]interface{}) (int64, error) {
+	objTable := o.s.GetTable(table)
+	if objTable == nil {
+		return 0, errors.New("DeleteMany: unknown object table " + table)
+	}
+	if len(whereVals) == 0 {
+		return 0, errors.New("DeleteMany: no values to match rows of " + table + " by")
+	}
+	whereObj, err := o.encodeObject(o.makeQueryObj(objTable, whereVals))
+	if err != nil {
+		return 0, err
+	}
+	sqlStr, bindWhere, err := o.sqlGen.BindingDelete(o.sqlGen, o.s, whereObj)
+	if err != nil {
+		return 0, err
+	}
+	return o.execDelete(ctx, tx, sqlStr, bindWhere)
+}
+
+// DeleteManyReturning is DeleteMany for callers that need to know what was
+// deleted, such as to publish change events. The deleted rows are returned as
+// clean objects holding the table's EssentialColumns, along with its primary
+// key columns. Where the dialect can return them from the DELETE itself, with
+// RETURNING (SQLite) or OUTPUT (SQL Server), a single statement is run.
+// Otherwise the matching rows are retrieved first and then deleted by their
+// primary keys, inside tx or a transaction of its own if tx is nil, and rows
+// added between the two statements are neither deleted nor returned.
+func (o ORM) DeleteManyReturning(ctx context.Context, tx *sql.Tx, table string, whereVals map[string]interface{}) (objs object.Array, err error) {
+	ctx, span := o.startSpan(ctx, "Delete", table)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	defer func() {
+		o.observe("Delete", table, start, err)
+	}()
+
+	objTable := o.s.GetTable(table)
+	if objTable == nil {
+		return nil, errors.New("DeleteManyReturning: unknown object table " + table)
+	}
+	if len(whereVals) == 0 {
+		return nil, errors.New("DeleteManyReturning: no values to match rows of " + table + " by")
+	}
+
+	whereObj, err := o.encodeObject(o.makeQueryObj(objTable, whereVals))
+	if err != nil {
+		return nil, err
+	}
+	sqlStr, columnNames, bindWhere, err := o.sqlGen.BindingDeleteReturning(o.sqlGen, o.s, whereObj, keyedColumns(objTable))
+	if err != nil {
+		return nil, errors.Wrap(err, "DeleteManyReturning")
+	}
+	if sqlStr != "" {
+		if o.sqlGen.Tracing {
+			fmt.Printf("DeleteManyReturning: sqlStr->%s, bindWhere->%v\n", sqlStr, bindWhere)
+		}
+		// The DELETE writes, so it must not be sent to a reader
+		err = o.writerOnly().scanObjects(ctx, tx, table, sqlStr, aliasColumnNames(objTable, columnNames), bindWhere, func(obj *object.Object) error {
+			objs = append(objs, obj)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "DeleteManyReturning")
+		}
+		return objs, nil
+	}
+
+	if tx == nil {
+		tx, err = o.RawConn.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "DeleteManyReturning")
+		}
+		defer func() {
+			if err != nil {
+				err = rollback(tx, err)
+				objs = nil
+				return
+			}
+			if err = tx.Commit(); err != nil {
+				err = errors.Wrap(err, "DeleteManyReturning: commit")
+				objs = nil
+			}
+		}()
+	}
+
+	objs, err = o.retrieveMany(ctx, tx, table, keyedColumns(objTable), whereVals)
+	if err != nil {
+		return nil, errors.Wrap(err, "DeleteManyReturning")
+	}
+	if err := o.deleteByKeys(ctx, tx, objTable, table, objs); err != nil {
+		return nil, errors.Wrap(err, "DeleteManyReturning")
+	}
+	return objs, nil
+}
+
+// deleteByKeys deletes the rows for objs by their primary keys, with one
+// DELETE ... IN statement per bulkChunkSize rows, or one statement per row
+// for tables with a composite key.
+func (o ORM) deleteByKeys(ctx context.Context, tx *sql.Tx, objTable *schema.Table, table string, objs object.Array) error {
+	// A missing key would match nothing, or every row with a NULL one
+	for _, obj := range objs {
+		for _, pk := range objTable.PrimaryKeyColumns() {
+			if columnValue(objTable, obj, pk) == nil {
+				return errors.New("no value for primary key column " + pk + " of object Type: " + obj.Type)
+			}
+		}
+	}
+	if objTable.MultiKey {
+		for _, obj := range objs {
+			if _, err := o.deleteCore(ctx, tx, obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	keys := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		keys[i] = columnValue(objTable, obj, objTable.Primary)
+	}
+	pk := objTable.GetColumn(objTable.Primary)
+	tableName := o.s.QualifiedTableName(objTable.Name, table, o.sqlGen.QuoteIdentifier)
+	for start := 0; start < len(keys); start += bulkChunkSize {
+		end := start + bulkChunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		whereClause, bindWhere, err := sg.NewWhereBuilder(o.sqlGen).Render([]sg.Predicate{
+			{Column: pk.Name, Operator: "IN", Value: keys[start:end]},
+		})
+		if err != nil {
+			return err
+		}
+		sqlStr := fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, whereClause)
+		if _, err := o.execDelete(ctx, tx, sqlStr, bindWhere); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyedColumns returns the table's default columns, with its primary key
+// columns added if they aren't amongst them.
+func keyedColumns(objTable *schema.Table) []string {
+	cols := append([]string(nil), objTable.DefaultColumns()...)
+	for _, pk := range objTable.PrimaryKeyColumns() {
+		found := false
+		for _, c := range cols {
+			if c == pk {
+				found = true
+				break
+			}
+		}
+		if !found {
+			cols = append(cols, pk)
+		}
+	}
+	return cols
+}
+
+func (o ORM) execDelete(ctx context.Context, tx *sql.Tx, sqlStr string, bindWhere []interface{}) (int64, error) {
+	if o.sqlGen.Tracing {
+		fmt.Printf("DeleteMany: sqlStr->%s, bindWhere->%v\n", sqlStr, bindWhere)
+	}
+	stmt, err := stmtFromDbOrTx(ctx, o, tx, sqlStr)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		stmtErr := stmt.Close()
+		if stmtErr != nil {
+			fmt.Println(stmtErr) // TODO: logger implementation
+		}
+	}()
+
+	res, err := stmt.ExecContext(ctx, bindWhere...)
+	if err != nil {
+		return 0, errors.Wrap(err, "DeleteMany")
+	}
+	return res.RowsAffected()
+}
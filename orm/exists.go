@@ -0,0 +1,85 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Exists reports whether at least one row in table matches queryVals,
+// without retrieving or counting the matching rows.
+func (o ORM) Exists(ctx context.Context, table string, queryVals map[string]interface{}) (bool, error) {
+	return o.existsCore(ctx, nil, table, queryVals)
+}
+
+// ExistsTx is Exists inside a transaction.
+func (o ORM) ExistsTx(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (bool, error) {
+	return o.existsCore(ctx, tx, table, queryVals)
+}
+
+func (o ORM) existsCore(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (bool, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	start := o.startObserve()
+	found, err := o.exists(ctx, tx, table, queryVals)
+	o.observe("Exists", table, start, err)
+	return found, err
+}
+
+func (o ORM) exists(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (bool, error) {
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	objTable := o.s.GetTable(table)
+	if objTable == nil {
+		return false, errors.New("Exists: unknown object table " + table)
+	}
+	queryObj, err := o.encodeObject(o.makeQueryObj(objTable, queryVals))
+	if err != nil {
+		return false, err
+	}
+
+	sg := o.sqlGen
+	sqlStr, bindArgs, err := sg.BindingExists(sg, o.s, queryObj)
+
+	if sg.Tracing {
+		fmt.Println("Exists/sqlStr=", sqlStr, "bindArgs=", bindArgs)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	stmt, err := stmtFromReaderOrTx(ctx, o, tx, sqlStr)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err := stmt.Close()
+		if err != nil {
+			fmt.Println(err) // TODO logger implementation
+		}
+	}()
+
+	res, err := stmt.QueryContext(ctx, bindArgs...)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err := res.Close()
+		if err != nil {
+			fmt.Println(err) // TODO logger implementation
+		}
+	}()
+
+	if res.Next() {
+		return true, nil
+	}
+	return false, res.Err()
+}
@@ -0,0 +1,111 @@
+package orm
+
+// The Explain functions render the SQL and bind arguments that the
+// corresponding ORM operation would execute, without touching the database.
+// Hooks are not called and objects are not modified.
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+)
+
+// ExplainInsert returns the SQL and bind arguments that Insert would execute
+// for obj. If the table uses a UUID primary key and obj has none, a fresh UUID
+// is rendered in its place. The output parameter used to capture the new key
+// with RETURNING ... INTO (Oracle) is not included in the bind arguments.
+func (o ORM) ExplainInsert(obj *object.Object) (string, []interface{}, error) {
+	objTable := o.s.GetTable(obj.Type)
+	if objTable == nil {
+		return "", nil, errors.New("ExplainInsert: unknown object table " + obj.Type)
+	}
+
+	data := obj.KV
+	pkCol := objTable.GetColumn(objTable.Primary)
+	if pkCol != nil && pkCol.IsUUID {
+		if _, ok := obj.KV[objTable.Primary]; !ok {
+			id, err := NewUUID()
+			if err != nil {
+				return "", nil, errors.Wrap(err, "ExplainInsert/NewUUID")
+			}
+			data = make(map[string]interface{}, len(obj.KV)+1)
+			for k, v := range obj.KV {
+				data[k] = v
+			}
+			data[objTable.Primary] = id
+		}
+	}
+
+	data, err := o.encodeValues(obj, data)
+	if err != nil {
+		return "", nil, err
+	}
+	sg := o.sqlGen
+	sqlStr, bindArgs, err := sg.BindingInsert(sg, o.s, obj.Type, data)
+	if err != nil {
+		return "", nil, err
+	}
+	if n := len(bindArgs); n > 0 && isOutArg(bindArgs[n-1]) {
+		bindArgs = bindArgs[:n-1]
+	}
+	return sqlStr, bindArgs, nil
+}
+
+// ExplainUpdate returns the SQL and bind arguments (the new values followed by
+// the where clause values) that Update would execute for obj.
+func (o ORM) ExplainUpdate(obj *object.Object) (string, []interface{}, error) {
+	enc, err := o.encodeObject(obj)
+	if err != nil {
+		return "", nil, err
+	}
+	sg := o.sqlGen
+	sqlStr, bindArgs, bindWhere, err := sg.BindingUpdate(sg, o.s, enc)
+	if err != nil {
+		return "", nil, err
+	}
+	return sqlStr, append(bindArgs, bindWhere...), nil
+}
+
+// ExplainSave returns the SQL and bind arguments that Save would execute for
+// obj, choosing between INSERT and UPDATE the same way. A clean object would
+// not be saved, so its SQL is the empty string.
+func (o ORM) ExplainSave(obj *object.Object) (string, []interface{}, error) {
+	objTable := o.s.GetTable(obj.Type)
+	if objTable == nil {
+		return "", nil, errors.New("ExplainSave: unknown object table " + obj.Type)
+	}
+	if !obj.IsDirty() {
+		return "", nil, nil
+	}
+	if objTable.Primary == "" {
+		return "", nil, errors.New("ExplainSave: empty primary key for " + obj.Type)
+	}
+	f := objTable.Columns[objTable.Primary]
+	if f == nil {
+		return "", nil, errors.New("ExplainSave: empty field " + objTable.Primary + " for " + obj.Type)
+	}
+	if _, ok := obj.KV[f.Name]; !ok {
+		return o.ExplainInsert(obj)
+	}
+	return o.ExplainUpdate(obj)
+}
+
+// ExplainDelete returns the SQL and bind arguments that Delete would execute
+// for obj.
+func (o ORM) ExplainDelete(obj *object.Object) (string, []interface{}, error) {
+	if o.s.GetTable(obj.Type) == nil {
+		return "", nil, errors.New("ExplainDelete: unknown object table " + obj.Type)
+	}
+	sg := o.sqlGen
+	return sg.BindingDelete(sg, o.s, obj)
+}
+
+// ExplainRetrieve returns the SQL and bind arguments that Retrieve and
+// RetrieveMany would execute for queryVals.
+func (o ORM) ExplainRetrieve(table string, queryVals map[string]interface{}) (string, []interface{}, error) {
+	sqlStr, _, bindArgs, err := o.renderRetrieve(table, nil, queryVals)
+	if err != nil {
+		return "", nil, err
+	}
+	return sqlStr, bindArgs, nil
+}
@@ -13,6 +13,10 @@ import (
 // one of the recommended methods to use for a FindOrCreate. FindOrCreateKVTx
 // is likely better to use in certain situations.
 func (o ORM) FindOrCreateTx(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, *object.Object, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+	o = o.writerOnly()
+
 	obj, err := o.RetrieveTx(ctx, tx, obj.Type, obj.KV)
 	if err != nil {
 		return 0, nil, err
@@ -47,6 +51,10 @@ func (o ORM) FindOrCreate(ctx context.Context, obj *object.Object) (int64, *obje
 // row and if the row doesn't exist, then the other values are used for the
 // INSERT. Using a transaction is recommended.
 func (o ORM) FindOrCreateKVTx(ctx context.Context, tx *sql.Tx, typ string, queryKV map[string]interface{}, createKV map[string]interface{}) (int64, *object.Object, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+	o = o.writerOnly()
+
 	obj, err := o.RetrieveTx(ctx, tx, typ, queryKV)
 	if err != nil {
 		return 0, nil, err
@@ -0,0 +1,141 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// bulkChunkSize bounds the number of values in a single IN list. Oracle
+// refuses lists longer than 1000.
+const bulkChunkSize = 1000
+
+// FleshenChildrenBulk is FleshenChildrenFor for many parents at once. Rather
+// than one query per parent, it retrieves the children of all of them with a
+// single WHERE key IN (...) query per child table (per 1000 parents), and
+// hands each child to the parent with the matching key. Every object must be
+// of the same type. Child tables related by a composite key are fleshened one
+// parent at a time.
+func (o ORM) FleshenChildrenBulk(ctx context.Context, objs []*object.Object, childTypes ...string) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	if len(objs) == 0 {
+		return nil
+	}
+	parentType := objs[0].Type
+	for _, obj := range objs {
+		if obj.Type != parentType {
+			return errors.New("FleshenChildrenBulk: objects of different types " + parentType + " and " + obj.Type)
+		}
+	}
+	schemaTable := o.s.GetTable(parentType)
+	if schemaTable == nil {
+		return errors.New("FleshenChildrenBulk: unknown object table " + parentType)
+	}
+
+	if len(childTypes) == 0 {
+		for childTableName := range schemaTable.Children {
+			childTypes = append(childTypes, childTableName)
+		}
+		sort.Strings(childTypes)
+	}
+
+	for _, childTableName := range childTypes {
+		childConfig, ok := schemaTable.Children[childTableName]
+		if !ok {
+			return errors.New("FleshenChildrenBulk: " + childTableName + " is not a child of table " + parentType)
+		}
+		childTable := o.s.GetTable(childTableName)
+		if childTable == nil {
+			return errors.New("FleshenChildrenBulk: unknown child table " + childTableName)
+		}
+		localCols, foreignCols, err := childConfig.KeyColumns()
+		if err != nil {
+			return errors.Wrap(err, "FleshenChildrenBulk")
+		}
+		if localCols == nil {
+			// Like FleshenChildren, the child holds the parent's primary key
+			localCols, foreignCols = []string{schemaTable.Primary}, []string{schemaTable.Primary}
+		}
+		if len(localCols) != 1 {
+			for _, obj := range objs {
+				if _, err := o.FleshenChildrenFor(ctx, obj, childTableName); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// Group the parents by their key, so that parents sharing a key
+		// share the children too.
+		var keyVals []interface{}
+		parents := make(map[string][]*object.Object)
+		for _, obj := range objs {
+			obj.Children[childTableName] = nil
+			v := columnValue(schemaTable, obj, foreignCols[0])
+			if v == nil {
+				continue
+			}
+			k := bulkKey(v)
+			if _, seen := parents[k]; !seen {
+				keyVals = append(keyVals, v)
+			}
+			parents[k] = append(parents[k], obj)
+		}
+
+		for start := 0; start < len(keyVals); start += bulkChunkSize {
+			end := start + bulkChunkSize
+			if end > len(keyVals) {
+				end = len(keyVals)
+			}
+			q := query.New().From(childTableName).Where(localCols[0], "IN", keyVals[start:end])
+			children, err := o.Query(ctx, q)
+			if err != nil {
+				return errors.Wrap(err, "FleshenChildrenBulk")
+			}
+			for _, child := range children {
+				for _, parent := range parents[bulkKey(columnValue(childTable, child, localCols[0]))] {
+					parent.Children[childTableName] = append(parent.Children[childTableName], child)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// columnValue returns the value of column col in obj, whether it is keyed by
+// the column's name or by its alias. A NULL value is returned as nil.
+func columnValue(tbl *schema.Table, obj *object.Object, col string) interface{} {
+	v, ok := obj.KV[col]
+	if !ok {
+		v = obj.Get(tbl.GetColumnAlias(col))
+	}
+	if obj.ValueIsNULL(v) {
+		return nil
+	}
+	return v
+}
+
+// bulkKey renders a key value so that values of different Go types which
+// hold the same key, such as an int set by the caller and the int64 retrieved
+// from the database, match.
+func bulkKey(v interface{}) string {
+	switch t := v.(type) {
+	case sql.NullString:
+		return t.String
+	case sql.NullInt64:
+		return fmt.Sprint(t.Int64)
+	case *sql.NullString:
+		return t.String
+	case *sql.NullInt64:
+		return fmt.Sprint(t.Int64)
+	}
+	return fmt.Sprint(v)
+}
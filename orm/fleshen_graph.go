@@ -0,0 +1,88 @@
+package orm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// fleshenVisited holds the objects reached while fleshening a graph, keyed by
+// their table and primary key, so that each row is loaded and fleshened once
+// however many paths lead to it.
+type fleshenVisited map[string]*object.Object
+
+// visit returns the object already reached for obj's row, or records obj and
+// returns it if the row is new. Objects without a complete primary key are
+// never matched.
+func (v fleshenVisited) visit(sch *schema.Schema, obj *object.Object) *object.Object {
+	tbl := sch.GetTable(obj.Type)
+	if tbl == nil {
+		return obj
+	}
+	cols := tbl.PrimaryKeyColumns()
+	parts := make([]string, 0, 1+len(cols))
+	parts = append(parts, sch.GetTableName(obj.Type))
+	for _, c := range cols {
+		val := columnValue(tbl, obj, c)
+		if val == nil {
+			return obj
+		}
+		parts = append(parts, bulkKey(val))
+	}
+	k := strings.Join(parts, "\x00")
+	if seen, ok := v[k]; ok {
+		return seen
+	}
+	v[k] = obj
+	return obj
+}
+
+// visitAll visits every object in rel, replacing those already reached with
+// the object loaded for them, and returns the ones reached for the first
+// time.
+func (v fleshenVisited) visitAll(sch *schema.Schema, rel map[string]object.Array) object.Array {
+	var reached object.Array
+	for _, objs := range rel {
+		for i, obj := range objs {
+			if obj == nil {
+				continue
+			}
+			if seen := v.visit(sch, obj); seen != obj {
+				objs[i] = seen
+				continue
+			}
+			reached = append(reached, obj)
+		}
+	}
+	return reached
+}
+
+// FleshenGraph fleshens the children and parents of obj, and of every object
+// reached through them, until the whole connected graph of rows is loaded.
+// Each row is loaded once: an object reached again, such as the parent of one
+// of obj's children, is the same *object.Object wherever it appears, so the
+// result may contain cycles. Clone and MarshalJSON cope with them, but code
+// walking the graph by hand must not assume it is a tree.
+func (o ORM) FleshenGraph(ctx context.Context, obj *object.Object) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	visited := make(fleshenVisited)
+	visited.visit(o.s, obj)
+	queue := object.Array{obj}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if _, err := o.FleshenChildren(ctx, cur); err != nil {
+			return err
+		}
+		if err := o.FleshenParents(ctx, cur); err != nil {
+			return err
+		}
+		queue = append(queue, visited.visitAll(o.s, cur.Children)...)
+		queue = append(queue, visited.visitAll(o.s, cur.Parents)...)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// FleshenParents is the counterpart of FleshenChildren. It retrieves the
+// direct parents of obj, from every table which lists obj's table in its
+// Children or is named in its ParentTables, and stores them in
+// obj.Parents keyed by parent table name. The parent rows are located by the
+// ChildTable key columns, or by the parent's primary key when the relation
+// has none. A parent table for which obj has a nil or NULL key gets no
+// entry.
+func (o ORM) FleshenParents(ctx context.Context, obj *object.Object) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	objTable := o.s.GetTable(obj.Type)
+	if objTable == nil {
+		return errors.New("FleshenParents: unknown object table " + obj.Type)
+	}
+
+	parentNames := o.s.ParentTableNames(obj.Type)
+	if _, ok := objTable.Children[obj.Type]; ok {
+		// ParentTableNames leaves out self-references
+		parentNames = append(parentNames, obj.Type)
+	}
+
+	parents := make(map[string]object.Array)
+	for _, parentName := range parentNames {
+		parentTable := o.s.GetTable(parentName)
+		if parentTable == nil {
+			return errors.New("FleshenParents: unknown parent table " + parentName)
+		}
+
+		var localCols, foreignCols []string
+		if childConfig, ok := parentTable.Children[obj.Type]; ok {
+			var err error
+			localCols, foreignCols, err = childConfig.KeyColumns()
+			if err != nil {
+				return errors.Wrap(err, "FleshenParents")
+			}
+		}
+		if localCols == nil {
+			// Like FleshenChildren, the child holds the parent's primary key
+			localCols, foreignCols = []string{parentTable.Primary}, []string{parentTable.Primary}
+		}
+
+		queryVals := keyQueryVals(objTable, obj, localCols, foreignCols)
+		if queryVals == nil {
+			continue
+		}
+
+		parentObjs, err := o.RetrieveMany(ctx, parentName, queryVals)
+		if err != nil {
+			return errors.Wrap(err, "FleshenParents")
+		}
+		parents[parentName] = parentObjs
+	}
+	obj.Parents = parents
+	return nil
+}
+
+// keyQueryVals returns the query values matching the columns to of a related
+// table with the values of the columns from of obj, whose table is objTable,
+// pairing them in order as ChildTable.KeyColumns does. It returns nil if obj
+// has a nil or NULL value for any of them.
+func keyQueryVals(objTable *schema.Table, obj *object.Object, from []string, to []string) map[string]interface{} {
+	queryVals := make(map[string]interface{}, len(from))
+	for i, c := range from {
+		v := columnValue(objTable, obj, c)
+		if v == nil {
+			return nil
+		}
+		queryVals[to[i]] = v
+	}
+	return queryVals
+}
@@ -5,6 +5,7 @@ import (
 )
 
 // GetDB accepts a Driver and DSN as strings, returning a sql.DB and an error.
+// OpenWithRetry also waits for the database to accept connections.
 func GetDB(driver, dsn string) (*sql.DB, error) {
 	db, err := sql.Open(driver, dsn)
 	if err != nil {
@@ -0,0 +1,33 @@
+package orm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Ping verifies that the database connection, and every reader, is alive,
+// within the context's deadline.
+func (o ORM) Ping(ctx context.Context) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	if o.RawConn == nil {
+		return errors.New("dyndao: ORM.Ping: RawConn is nil")
+	}
+	if err := o.RawConn.PingContext(ctx); err != nil {
+		return errors.Wrap(err, "dyndao: ORM.Ping")
+	}
+	for i, r := range o.readers {
+		if err := r.PingContext(ctx); err != nil {
+			return errors.Wrapf(err, "dyndao: ORM.Ping: reader %d", i)
+		}
+	}
+	return nil
+}
+
+// Healthy reports whether Ping succeeds, for use in liveness and readiness
+// probes.
+func (o ORM) Healthy(ctx context.Context) bool {
+	return o.Ping(ctx) == nil
+}
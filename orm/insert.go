@@ -8,11 +8,73 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
 	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
 )
 
+// KeySource describes where the primary key reported in an InsertResult came
+// from.
+type KeySource string
+
+const (
+	// KeyFromLastInsertID means the key was read from sql.Result.LastInsertId,
+	// which is the case for MySQL and SQLite.
+	KeyFromLastInsertID KeySource = "LastInsertId"
+	// KeyFromReturning means the key was bound through a RETURNING ... INTO
+	// output parameter, which is the case for generators that set
+	// FixLastInsertIDbug (Oracle).
+	KeyFromReturning KeySource = "RETURNING"
+	// KeyFromOutput means the key was read from the single row returned by
+	// an INSERT ... OUTPUT statement, which is the case for generators that
+	// set InsertOutputsPK (SQL Server).
+	KeyFromOutput KeySource = "OUTPUT"
+	// KeyFromUUID means the ORM generated a UUID key before inserting.
+	KeyFromUUID KeySource = "UUID"
+	// KeyFromCaller means the caller supplied the primary key.
+	KeyFromCaller KeySource = "caller"
+	// KeyUnknown means the row was inserted but the database did not report
+	// its key, as MySQL does for a table without an AUTO_INCREMENT column.
+	// The object's primary key is left unset.
+	KeyUnknown KeySource = "unknown"
+)
+
+// InsertResult describes the outcome of an INSERT. PrimaryKey is the value of
+// the object's primary key after the insert, which is also set on the
+// object; KeySource says which mechanism is authoritative for it with the
+// active dialect. For MultiKey tables, PrimaryKey is the value of Primary.
+type InsertResult struct {
+	RowsAffected int64
+	PrimaryKey   interface{}
+	KeySource    KeySource
+}
+
 // Insert function will INSERT a record, given an optional transaction and an object.
 // It returns the number of rows affected (int64) and any error that may have occurred.
+//
+// Unless the caller supplies the primary key, a successful Insert sets the
+// new primary key on the object, whichever mechanism the dialect uses to
+// report it. An Insert never fails once the row is written: if the database
+// doesn't report the key, the object's key is left unset, which
+// InsertWithResult reports as KeyUnknown.
 func (o ORM) Insert(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+	res, err := o.InsertWithResult(ctx, tx, obj)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected, nil
+}
+
+// InsertWithResult is Insert, returning both the rows affected and the
+// primary key of the inserted row.
+func (o ORM) InsertWithResult(ctx context.Context, tx *sql.Tx, obj *object.Object) (res InsertResult, err error) {
+	ctx, span := o.startSpan(ctx, "Insert", obj.Type)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	res, err = o.insertWithResult(ctx, tx, obj)
+	o.observe("Insert", obj.Type, start, err)
+	return res, err
+}
+
+func (o ORM) insertWithResult(ctx context.Context, tx *sql.Tx, obj *object.Object) (InsertResult, error) {
 	sg := o.sqlGen
 	tracing := sg.Tracing
 	errorString := "Insert error"
@@ -20,7 +82,7 @@ func (o ORM) Insert(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 	// Check context
 	select {
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return InsertResult{}, ctx.Err()
 	default:
 	}
 
@@ -30,27 +92,78 @@ func (o ORM) Insert(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 		if tracing {
 			log15.Error(errorString, "GetTable_error", "objTable was unknown")
 		}
-		return 0, errors.New("Insert: unknown object table " + obj.Type)
+		return InsertResult{}, errors.New("Insert: unknown object table " + obj.Type)
 	}
 
-	callerSuppliesPK := objTable.CallerSuppliesPK
+	keySource, err := o.prepareInsert(objTable, obj)
+	if err != nil {
+		return InsertResult{}, err
+	}
+	return o.execInsert(ctx, tx, objTable, obj, keySource)
+}
+
+// prepareInsert readies obj for insertion, generating its UUID primary key if
+// needed, calling the before create hooks and applying the LengthPolicy. It returns KeyFromUUID if it
+// generated a key, and KeyFromCaller otherwise.
+func (o ORM) prepareInsert(objTable *schema.Table, obj *object.Object) (KeySource, error) {
+	keySource := KeyFromCaller
+
+	// Generate a UUID primary key if the table uses them and the caller
+	// didn't provide one.
+	pkCol := objTable.GetColumn(objTable.Primary)
+	if pkCol != nil && pkCol.IsUUID {
+		if _, ok := obj.KV[objTable.Primary]; !ok {
+			id, err := NewUUID()
+			if err != nil {
+				return keySource, errors.Wrap(err, "Insert/NewUUID")
+			}
+			obj.SetCore(objTable.Primary, id)
+			keySource = KeyFromUUID
+		}
+	}
 
 	// Call any before create hooks
 	err := o.CallBeforeCreateHookIfNeeded(obj)
 	if err != nil {
-		if tracing {
-			log15.Error(errorString, "BeforeCreateHookError", err)
+		if o.sqlGen.Tracing {
+			log15.Error("Insert error", "BeforeCreateHookError", err)
 		}
-		return 0, err
+		return keySource, err
+	}
+	if err := o.applyLengthPolicy(objTable, obj); err != nil {
+		return keySource, err
+	}
+	return keySource, nil
+}
+
+// execInsert runs the INSERT of an object readied by prepareInsert.
+func (o ORM) execInsert(ctx context.Context, tx *sql.Tx, objTable *schema.Table, obj *object.Object, keySource KeySource) (InsertResult, error) {
+	sg := o.sqlGen
+	tracing := sg.Tracing
+	errorString := "Insert error"
+	callerSuppliesPK := !objTable.UsesLastInsertID()
+
+	// A key the caller set is the row's key, whatever LastInsertId says: it
+	// may not be an AUTO_INCREMENT column, or not even an integer.
+	suppliedPK := false
+	if !callerSuppliesPK && !sg.FixLastInsertIDbug && !sg.InsertOutputsPK {
+		if v := obj.Get(objTable.Primary); v != nil && !obj.ValueIsNULL(v) {
+			suppliedPK = true
+		}
+	}
+
+	data, err := o.encodeValues(obj, obj.KV)
+	if err != nil {
+		return InsertResult{}, err
 	}
 
 	// Prepare our binding insert SQL statement and the binding parameters
-	sqlStr, bindArgs, err := sg.BindingInsert(sg, o.s, obj.Type, obj.KV)
+	sqlStr, bindArgs, err := sg.BindingInsert(sg, o.s, obj.Type, data)
 	if err != nil {
 		if tracing {
 			log15.Error(errorString, "BindingInsert_error", err)
 		}
-		return 0, err
+		return InsertResult{}, err
 	}
 	if tracing {
 		fmt.Println("Insert/sqlStr=", sqlStr, "bindArgs=", bindArgs)
@@ -60,9 +173,15 @@ func (o ORM) Insert(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 	var lastID int64
 	// Oracle-specific fix. Possibly Postgres also.
 	if (!callerSuppliesPK) && o.sqlGen.FixLastInsertIDbug {
-		bindArgs = append(bindArgs, sql.Named(o.s.GetTable(obj.Type).Primary, sql.Out{
-			Dest: &lastID,
-		}))
+		out := sql.Out{Dest: &lastID}
+		if n := len(bindArgs); n > 0 && isOutArg(bindArgs[n-1]) {
+			// The generator named the out bind, after its placeholder
+			na := bindArgs[n-1].(sql.NamedArg)
+			na.Value = out
+			bindArgs[n-1] = na
+		} else {
+			bindArgs = append(bindArgs, sql.Named(o.s.GetTable(obj.Type).Primary, out))
+		}
 	}
 
 	// Prepare statement handle from either the database or the transaction
@@ -72,7 +191,7 @@ func (o ORM) Insert(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 			log15.Error(errorString, "stmtFromDbOrTx_error", err)
 		}
 
-		return 0, err
+		return InsertResult{}, err
 	}
 	defer func() {
 		//fmt.Println("DEFER INSERT ABOUT TO CLOSE")
@@ -90,57 +209,96 @@ func (o ORM) Insert(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 		newBindArgs[i] = maybeDereferenceArgs(arg)
 	}
 
-	// Execute our statement
-	res, err := stmt.ExecContext(ctx, bindArgs...)
-	if err != nil {
-		if tracing {
-			log15.Error(errorString, "ExecContext_error", err)
-			fmt.Println("orm/save error", err)
+	var rowsAff int64
+	if !callerSuppliesPK && sg.InsertOutputsPK {
+		// The statement returns the new key as its only row
+		err = stmt.QueryRowContext(ctx, bindArgs...).Scan(&lastID)
+		if err != nil {
+			if tracing {
+				log15.Error(errorString, "QueryRowContext_error", err)
+			}
+			return InsertResult{}, errors.Wrap(err, "Insert/QueryRowContext")
 		}
-
-		return 0, errors.Wrap(err, "Insert/ExecContext")
-	}
-
-	// If the user supplies the primary key for this table, there is no need
-	// for us to bother with populating the result of LastInsertID().
-	if !callerSuppliesPK {
-		newID, err := res.LastInsertId()
-		if err != nil && lastID == 0 {
+		rowsAff = 1
+		keySource = KeyFromOutput
+	} else {
+		// Execute our statement
+		res, err := stmt.ExecContext(ctx, bindArgs...)
+		if err != nil {
 			if tracing {
+				log15.Error(errorString, "ExecContext_error", err)
 				fmt.Println("orm/save error", err)
 			}
-			log15.Error(errorString, "LastInsertID_error", err)
-			return 0, err
-		}
-		if lastID != 0 {
-			newID = lastID
+
+			return InsertResult{}, errors.Wrap(err, "Insert/ExecContext")
 		}
-		if tracing {
-			fmt.Println("DEBUG Insert received newID=", newID)
+
+		// If the user supplies the primary key for this table, there is no need
+		// for us to bother with populating the result of LastInsertID().
+		if !callerSuppliesPK && !suppliedPK {
+			keySource = KeyFromLastInsertID
+			if lastID != 0 {
+				keySource = KeyFromReturning
+			} else if newID, err := res.LastInsertId(); err != nil {
+				// The row is written, so this is no reason to fail
+				if tracing {
+					log15.Error(errorString, "LastInsertID_error", err)
+				}
+			} else {
+				lastID = newID
+			}
 		}
 
-		obj.SetCore(objTable.Primary, newID) // Set the new primary key in the object
+		// Check rows affected
+		rowsAff, err = res.RowsAffected()
+		if err != nil {
+			if tracing {
+				fmt.Println("orm/save error", err)
+			}
+			return InsertResult{}, err
+		}
 	}
 
-	// Check rows affected
-	rowsAff, err := res.RowsAffected()
-	if err != nil {
-		if tracing {
-			fmt.Println("orm/save error", err)
+	if !callerSuppliesPK && !suppliedPK {
+		if lastID == 0 {
+			keySource = KeyUnknown
+		} else {
+			if tracing {
+				fmt.Println("DEBUG Insert received newID=", lastID)
+			}
+			obj.SetCore(objTable.Primary, lastID) // Set the new primary key in the object
 		}
-		return 0, err
 	}
 
+	if err := o.finishInsert(obj); err != nil {
+		return InsertResult{}, err
+	}
+	return InsertResult{RowsAffected: rowsAff, PrimaryKey: obj.Get(objTable.Primary), KeySource: keySource}, nil
+}
+
+// finishInsert calls the after create hooks for an inserted object, and
+// marks it clean.
+func (o ORM) finishInsert(obj *object.Object) error {
 	// Call after create hook
-	err = o.CallAfterCreateHookIfNeeded(obj)
+	err := o.CallAfterCreateHookIfNeeded(obj)
 	if err != nil {
-		if tracing {
-			log15.Error(errorString, "BeforeAfterCreateHookError", err)
+		if o.sqlGen.Tracing {
+			log15.Error("Insert error", "BeforeAfterCreateHookError", err)
 		}
-		return 0, err
+		return err
 	}
 
 	obj.MarkDirty(false)      // Note that the object has been recently saved
 	obj.ResetChangedColumns() // Reset the 'changed fields', if any
-	return rowsAff, nil
+	return nil
+}
+
+// isOutArg reports whether a bind argument is a named out bind.
+func isOutArg(arg interface{}) bool {
+	na, ok := arg.(sql.NamedArg)
+	if !ok {
+		return false
+	}
+	_, ok = na.Value.(sql.Out)
+	return ok
 }
@@ -0,0 +1,79 @@
+package orm
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// LengthPolicy says what the ORM does with string values longer than their
+// column's Length when inserting and updating.
+type LengthPolicy int
+
+const (
+	// LengthUnchecked sends strings as they are, leaving it to the database
+	// to refuse or truncate them. It is the default.
+	LengthUnchecked LengthPolicy = iota
+	// LengthError fails the insert or update with a ValidationErrors,
+	// before anything is sent to the database.
+	LengthError
+	// LengthTruncate trims strings to their column's Length, counted in
+	// characters, both in the object and in the statement.
+	LengthTruncate
+)
+
+// applyLengthPolicy checks the string values of obj against the Length of
+// their columns, as the ORM's LengthPolicy says. Raw SQL, and columns with a
+// Codec, are left alone.
+func (o ORM) applyLengthPolicy(objTable *schema.Table, obj *object.Object) error {
+	if o.LengthPolicy == LengthUnchecked {
+		return nil
+	}
+	codecs := o.Codecs[o.s.GetTableName(obj.Type)]
+
+	keys := make([]string, 0, len(obj.KV))
+	for k := range obj.KV {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs ValidationErrors
+	for _, k := range keys {
+		s, ok := obj.KV[k].(string)
+		f := objTable.GetColumn(k)
+		if !ok || f == nil || f.Length <= 0 || f.IsReadOnly() || codecs[f.Name] != nil {
+			continue
+		}
+		n := utf8.RuneCountInString(s)
+		if n <= f.Length {
+			continue
+		}
+		if o.LengthPolicy == LengthTruncate {
+			obj.KV[k] = truncateString(s, f.Length)
+			continue
+		}
+		errs = append(errs, lengthError(f, n))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func lengthError(f *schema.Column, n int) error {
+	return fmt.Errorf("value of length %d exceeds length %d of column %s", n, f.Length, f.Name)
+}
+
+// truncateString returns the first n characters of s.
+func truncateString(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
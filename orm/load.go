@@ -25,7 +25,13 @@ import (
 // GetParentsViaChild retrieves all direct (one-level 'up') parents for a given child object.
 // If a child contains multiple parent tables (possibility?) then this would return an Array
 // of objects with multiple potential values for their obj.Type fields.
+// Each parent table is matched on the key columns of its ChildTable entry for
+// the child, every one of them if it is MultiKey, or else on the parent's
+// primary and foreign key columns.
 func (o ORM) GetParentsViaChild(ctx context.Context, childObj *object.Object) (object.Array, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -45,10 +51,31 @@ func (o ORM) GetParentsViaChild(ctx context.Context, childObj *object.Object) (o
 		return nil, errors.New("GetParentsViaChild: cannot retrieve parents for table " + table + ", schema ParentTables is nil")
 	}
 	for _, pt := range objTable.ParentTables {
-		// 'Capture' the primary key(s)
-		pkQueryVals, err := pkQueryValsFromKV(childObj, o.s, pt)
-		if err != nil {
-			return nil, err
+		parentTable := o.s.GetTable(pt)
+		if parentTable == nil {
+			return nil, errors.New("GetParentsViaChild: unknown parent table " + pt)
+		}
+		var pkQueryVals map[string]interface{}
+		if childConfig, ok := parentTable.Children[table]; ok {
+			// The relation names the key columns, all of them if MultiKey
+			localCols, foreignCols, err := childConfig.KeyColumns()
+			if err != nil {
+				return nil, errors.Wrap(err, "GetParentsViaChild")
+			}
+			if localCols != nil {
+				pkQueryVals = keyQueryVals(objTable, childObj, localCols, foreignCols)
+				if pkQueryVals == nil {
+					continue
+				}
+			}
+		}
+		if pkQueryVals == nil {
+			// 'Capture' the primary key(s)
+			var err error
+			pkQueryVals, err = pkQueryValsFromKV(childObj, o.s, pt)
+			if err != nil {
+				return nil, err
+			}
 		}
 		// Retrieve + append the relevant parent objs
 		objs, err := o.RetrieveMany(ctx, pt, pkQueryVals)
@@ -66,6 +93,9 @@ func (o ORM) GetParentsViaChild(ctx context.Context, childObj *object.Object) (o
 // when fleshening the children structures -- when retrieving the children, we do a single-level retrieve, ignoring
 // any child structures that may be configured at two levels of depth.
 func (o ORM) RetrieveWithChildren(ctx context.Context, table string, pkValues map[string]interface{}) (*object.Object, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -139,13 +169,13 @@ func (o ORM) RetrieveWithChildren(ctx context.Context, table string, pkValues ma
 // for both the object and the error if a row is unable to be matched by the underlying
 // datastore.
 // TODO: Implement LIMIT so that we can improve this.
-func (o ORM) retrieveCore(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (*object.Object, error) {
+func (o ORM) retrieveCore(ctx context.Context, tx *sql.Tx, table string, columns []string, queryVals map[string]interface{}) (*object.Object, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
-	objAry, err := o.retrieveManyCore(ctx, tx, table, queryVals)
+	objAry, err := o.retrieveManyCore(ctx, tx, table, columns, queryVals)
 	if err != nil {
 		return nil, err
 	}
@@ -162,8 +192,9 @@ func (o ORM) retrieveCore(ctx context.Context, tx *sql.Tx, table string, queryVa
 // for both the object and the error if a row is unable to be matched by the underlying
 // datastore.
 // TODO: Implement LIMIT so that we can improve this.
-func (o ORM) RetrieveTx(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (*object.Object, error) {
-	return o.retrieveCore(ctx, tx, table, queryVals)
+func (o ORM) RetrieveTx(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}, opts ...RetrieveOption) (*object.Object, error) {
+	columns, queryVals := o.applyRetrieveOptions(table, queryVals, opts)
+	return o.retrieveCore(ctx, tx, table, columns, queryVals)
 }
 
 // Retrieve function will fleshen an object structure, given some primary keys.
@@ -171,13 +202,37 @@ func (o ORM) RetrieveTx(ctx context.Context, tx *sql.Tx, table string, queryVals
 // it's just a cheap implementation that returns the zeroeth value. Nil will be returned
 // for both the object and the error if a row is unable to be matched by the underlying
 // datastore.
+// Only the table's EssentialColumns are selected, unless WithAllColumns is
+// given. A nil or NULL query value matches NULL columns, unless
+// WithNullSemantics(NullStandard) is given.
 // TODO: Implement LIMIT so that we can improve this.
-func (o ORM) Retrieve(ctx context.Context, table string, queryVals map[string]interface{}) (*object.Object, error) {
-	return o.retrieveCore(ctx, nil, table, queryVals)
+func (o ORM) Retrieve(ctx context.Context, table string, queryVals map[string]interface{}, opts ...RetrieveOption) (*object.Object, error) {
+	columns, queryVals := o.applyRetrieveOptions(table, queryVals, opts)
+	return o.retrieveCore(ctx, nil, table, columns, queryVals)
+}
+
+// RetrieveColumns is Retrieve, but only selects the given columns. Any other
+// columns are absent from the returned object. Every column must exist in
+// the schema table.
+func (o ORM) RetrieveColumns(ctx context.Context, table string, columns []string, queryVals map[string]interface{}) (*object.Object, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("RetrieveColumns: no columns given for table " + table)
+	}
+	return o.retrieveCore(ctx, nil, table, columns, queryVals)
 }
 
 // FleshenChildren function accepts an object and resets it's children.
 func (o ORM) FleshenChildren(ctx context.Context, obj *object.Object) (*object.Object, error) {
+	return o.FleshenChildrenFor(ctx, obj)
+}
+
+// FleshenChildrenFor is FleshenChildren restricted to the named child tables,
+// leaving any other children of obj untouched. Every name must be a child of
+// the object's table. Passing no names fleshens all children.
+func (o ORM) FleshenChildrenFor(ctx context.Context, obj *object.Object, childTypes ...string) (*object.Object, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -185,26 +240,74 @@ func (o ORM) FleshenChildren(ctx context.Context, obj *object.Object) (*object.O
 	}
 	// Retrieve schema configuration for this object type (schema.Table)
 	schemaTable := o.s.GetTable(obj.Type)
+	if schemaTable == nil {
+		return nil, errors.New("FleshenChildren: unknown object table " + obj.Type)
+	}
 
-	// Retrieve primary key
-	pkKey := schemaTable.Primary
-	pkVal := obj.Get(pkKey)
-
-	// If this table is configured with child tables, then we iterate over
-	// them and call RetrieveMany using the singular primary key value.
-	// FIXME: We need to support multikey in this instance if we are going
-	// to consider this complete.
-	if len(schemaTable.Children) > 0 {
+	if len(childTypes) == 0 {
 		for childTableName := range schemaTable.Children {
-			// TODO: multi-key support here...
-			m := map[string]interface{}{}
-			m[pkKey] = pkVal
-			childObjs, err := o.RetrieveMany(ctx, childTableName, m)
+			childTypes = append(childTypes, childTableName)
+		}
+	} else {
+		for _, childTableName := range childTypes {
+			if _, ok := schemaTable.Children[childTableName]; !ok {
+				return nil, errors.New("FleshenChildrenFor: " + childTableName + " is not a child of table " + obj.Type)
+			}
+		}
+	}
+
+	// For each requested child table, we call RetrieveMany matching the
+	// parent's primary key, unless the relation names its key columns: its
+	// LocalColumn and ForeignColumn, or every one of its LocalColumns and
+	// ForeignColumns if it is MultiKey.
+	for _, childTableName := range childTypes {
+		localCols, foreignCols := []string{schemaTable.Primary}, []string{schemaTable.Primary}
+		if childConfig := schemaTable.Children[childTableName]; childConfig != nil {
+			l, f, err := childConfig.KeyColumns()
 			if err != nil {
+				return nil, errors.Wrap(err, "FleshenChildren")
+			}
+			if l != nil {
+				localCols, foreignCols = l, f
+			}
+		}
+		m := keyQueryVals(schemaTable, obj, foreignCols, localCols)
+		if m == nil {
+			// Without a key nothing can be its child, and a NULL key
+			// would match the orphans
+			obj.Children[childTableName] = object.Array{}
+			continue
+		}
+		childObjs, err := o.RetrieveMany(ctx, childTableName, m)
+		if err != nil {
+			return nil, err
+		}
+		obj.Children[childTableName] = childObjs
+	}
+	return obj, nil
+}
+
+// FleshenChildrenDepth fleshens the children of obj recursively, up to
+// maxDepth levels below obj. A maxDepth of 1 is equivalent to FleshenChildren,
+// and a maxDepth below 1 leaves obj untouched. Each row is fleshened once: a
+// child which has already been reached, such as an ancestor in a
+// self-referential table, is replaced by the object already loaded for it.
+func (o ORM) FleshenChildrenDepth(ctx context.Context, obj *object.Object, maxDepth int) (*object.Object, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	visited := make(fleshenVisited)
+	visited.visit(o.s, obj)
+	level := object.Array{obj}
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		var next object.Array
+		for _, cur := range level {
+			if _, err := o.FleshenChildren(ctx, cur); err != nil {
 				return nil, err
 			}
-			obj.Children[childTableName] = childObjs
+			next = append(next, visited.visitAll(o.s, cur.Children)...)
 		}
+		level = next
 	}
 	return obj, nil
 }
@@ -213,6 +316,16 @@ func (o ORM) FleshenChildren(ctx context.Context, obj *object.Object) (*object.O
 // the column names and the binding arguments in addition to the SQL string, so that it can dynamically map
 // the column types accordingly to the destination object. (Mainly, so we know the array length..)
 func (o ORM) RetrieveManyFromCustomSQL(ctx context.Context, table string, sqlStr string, columnNames []string, bindArgs []interface{}) (object.Array, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	start := o.startObserve()
+	objs, err := o.retrieveManyFromCustomSQL(ctx, table, sqlStr, columnNames, bindArgs)
+	o.observe("Retrieve", table, start, err)
+	return objs, err
+}
+
+func (o ORM) retrieveManyFromCustomSQL(ctx context.Context, table string, sqlStr string, columnNames []string, bindArgs []interface{}) (object.Array, error) {
 	sg := o.sqlGen
 
 	select {
@@ -226,7 +339,7 @@ func (o ORM) RetrieveManyFromCustomSQL(ctx context.Context, table string, sqlStr
 		fmt.Println("RetrieveManyFromCustomSQL/sqlStr=", sqlStr, "columnNames=", columnNames, "bindArgs=", bindArgs)
 	}
 
-	stmt, err := o.RawConn.PrepareContext(ctx, sqlStr)
+	stmt, err := stmtFromReaderOrTx(ctx, o, nil, sqlStr)
 	if err != nil {
 		return nil, err
 	}
@@ -259,6 +372,11 @@ func (o ORM) RetrieveManyFromCustomSQL(ctx context.Context, table string, sqlStr
 	}
 
 	for res.Next() {
+		// Stop promptly once the context is done, rather than relying on
+		// the driver to notice mid-scan.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if err := res.Scan(columnPointers...); err != nil {
 			return nil, err
 		}
@@ -268,6 +386,10 @@ func (o ORM) RetrieveManyFromCustomSQL(ctx context.Context, table string, sqlStr
 		if err != nil {
 			return nil, err
 		}
+		convertValues(o.s.GetTable(table), obj)
+		if err := o.decodeValues(o.s.GetTable(table), obj); err != nil {
+			return nil, err
+		}
 
 		obj.MarkDirty(false)
 		obj.ResetChangedColumns()
@@ -296,7 +418,15 @@ func (o ORM) makeQueryObj(objTable *schema.Table, queryVals map[string]interface
 	return queryObj
 }
 
-func (o ORM) retrieveManyCore(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (object.Array, error) {
+// retrieveManyCore selects the given columns, or the table's DefaultColumns
+// if columns is nil.
+func (o ORM) retrieveManyCore(ctx context.Context, tx *sql.Tx, table string, columns []string, queryVals map[string]interface{}) (objs object.Array, err error) {
+	ctx, span := o.startSpan(ctx, "Retrieve", table)
+	defer endSpan(span, &err)
+	return o.retrieveMany(ctx, tx, table, columns, queryVals)
+}
+
+func (o ORM) retrieveMany(ctx context.Context, tx *sql.Tx, table string, columns []string, queryVals map[string]interface{}) (object.Array, error) {
 	// Check for timeout
 	select {
 	case <-ctx.Done():
@@ -304,39 +434,111 @@ func (o ORM) retrieveManyCore(ctx context.Context, tx *sql.Tx, table string, que
 	default:
 	}
 
+	sqlStr, columnNames, bindArgs, err := o.renderRetrieve(table, columns, queryVals)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.queryObjects(ctx, tx, table, sqlStr, columnNames, bindArgs)
+}
+
+// renderRetrieve generates a sql string, the column names, and the binding
+// parameter arguments needed to retrieve the objects matching queryVals.
+func (o ORM) renderRetrieve(table string, columns []string, queryVals map[string]interface{}) (string, []string, []interface{}, error) {
 	// Check to make sure that the table arg is a valid table name We will
 	// need objTable later.
 	objTable := o.s.GetTable(table)
 	if objTable == nil {
-		return nil, errors.New("RetrieveMany: unknown object table " + table)
+		return "", nil, nil, errors.New("RetrieveMany: unknown object table " + table)
 	}
 	if objTable.Name == "" {
-		return nil, errors.New("RetrieveMany: schema table object has unset 'Name' property")
+		return "", nil, nil, errors.New("RetrieveMany: schema table object has unset 'Name' property")
 	}
 
-	var objectArray object.Array
-
 	// Construct a dyndao object from our queryVals
-	queryObj := o.makeQueryObj(objTable, queryVals)
+	queryObj, err := o.encodeObject(o.makeQueryObj(objTable, queryVals))
+	if err != nil {
+		return "", nil, nil, err
+	}
 
 	// Generate a sql string, the column names, and the binding parameter
 	// arguments from the schema and the query object
 	sg := o.sqlGen
-	sqlStr, columnNames, bindArgs, err := sg.BindingRetrieve(sg, o.s, queryObj)
+	var sqlStr string
+	var columnNames []string
+	var bindArgs []interface{}
+	if columns == nil {
+		sqlStr, columnNames, bindArgs, err = sg.BindingRetrieve(sg, o.s, queryObj)
+	} else {
+		sqlStr, columnNames, bindArgs, err = sg.BindingRetrieveColumns(sg, o.s, queryObj, columns)
+	}
 
 	if sg.Tracing {
 		fmt.Println("RetrieveMany/sqlStr=", sqlStr, "columnNames=", columnNames, "bindArgs=", bindArgs)
 	}
 
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return sqlStr, aliasColumnNames(objTable, columnNames), bindArgs, nil
+}
+
+// aliasColumnNames maps the real column names returned by the SQL generator
+// back to their aliases, so that retrieved objects use the same keys the
+// caller saves them with. Primary key columns keep their real names, as Save
+// and the update and delete WHERE clauses look them up by those.
+func aliasColumnNames(objTable *schema.Table, columnNames []string) []string {
+	if objTable.ColumnAliases == nil {
+		return columnNames
+	}
+	keys := make(map[string]bool)
+	for _, k := range objTable.PrimaryKeyColumns() {
+		keys[k] = true
+	}
+	aliased := make([]string, len(columnNames))
+	for i, c := range columnNames {
+		if keys[c] {
+			aliased[i] = c
+			continue
+		}
+		aliased[i] = objTable.GetColumnAlias(c)
+	}
+	return aliased
+}
+
+// queryObjects executes sqlStr, inside tx if it is not nil, and scans every
+// row into a clean object of type table.
+func (o ORM) queryObjects(ctx context.Context, tx *sql.Tx, table string, sqlStr string, columnNames []string, bindArgs []interface{}) (object.Array, error) {
+	var objectArray object.Array
+	err := o.eachObject(ctx, tx, table, sqlStr, columnNames, bindArgs, func(obj *object.Object) error {
+		objectArray = append(objectArray, obj)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return objectArray, nil
+}
+
+// eachObject executes sqlStr, inside tx if it is not nil, and scans each row
+// into a clean object of type table which is passed to fn. Iteration stops at
+// the first error returned by fn, which is returned as is. The statement and
+// rows are always closed before returning.
+func (o ORM) eachObject(ctx context.Context, tx *sql.Tx, table string, sqlStr string, columnNames []string, bindArgs []interface{}, fn func(*object.Object) error) error {
+	start := o.startObserve()
+	err := o.scanObjects(ctx, tx, table, sqlStr, columnNames, bindArgs, fn)
+	o.observe("Retrieve", table, start, err)
+	return err
+}
+
+func (o ORM) scanObjects(ctx context.Context, tx *sql.Tx, table string, sqlStr string, columnNames []string, bindArgs []interface{}, fn func(*object.Object) error) error {
+	sg := o.sqlGen
 
 	// Determines whether we are running inside a transaction or not,
 	// returning stmt either way
-	stmt, err := stmtFromDbOrTx(ctx, o, tx, sqlStr)
+	stmt, err := stmtFromReaderOrTx(ctx, o, tx, sqlStr)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer func() {
@@ -348,60 +550,144 @@ func (o ORM) retrieveManyCore(ctx context.Context, tx *sql.Tx, table string, que
 
 	res, err := stmt.QueryContext(ctx, bindArgs...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer func() {
-		//fmt.Println("RYAN DEFER RETRIEVE ABOUT TO CLOSE")
 		err := res.Close()
 		if err != nil {
-			//fmt.Println("RYAN DEFER RETRIEVE ERROR", err)
 			fmt.Println(err) // TODO logger implementation
 		}
-		//fmt.Println("RYAN DEFER RETRIEVE CLOSED")
 	}()
 
 	columnTypes, err := res.ColumnTypes()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	columnPointers, err := sg.MakeColumnPointers(sg, len(columnNames), columnTypes)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	objTable := o.s.GetTable(table)
 
 	for res.Next() {
+		// Stop promptly once the context is done, rather than relying on
+		// the driver to notice mid-scan.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		obj := object.New(table)
 		if err := res.Scan(columnPointers...); err != nil {
-			return nil, err
+			return err
 		}
 
 		err = sg.DynamicObjectSetter(sg, columnNames, columnPointers, columnTypes, obj)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		convertValues(objTable, obj)
+		if err := o.decodeValues(objTable, obj); err != nil {
+			return err
 		}
 
 		obj.MarkDirty(false)
 		obj.ResetChangedColumns()
-		objectArray = append(objectArray, obj)
-	}
-
-	err = res.Err()
-	if err != nil {
-		return nil, err
+		if err := fn(obj); err != nil {
+			return err
+		}
 	}
 
-	return objectArray, nil
+	return res.Err()
 }
 
 // RetrieveManyTx function will fleshen a top-level object structure, given some primary keys. And
 // it's transactional!
-func (o ORM) RetrieveManyTx(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}) (object.Array, error) {
-	return o.retrieveManyCore(ctx, tx, table, queryVals)
+func (o ORM) RetrieveManyTx(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}, opts ...RetrieveOption) (object.Array, error) {
+	columns, queryVals := o.applyRetrieveOptions(table, queryVals, opts)
+	return o.retrieveManyCore(ctx, tx, table, columns, queryVals)
 }
 
-// RetrieveMany function will fleshen a top-level object structure, given some primary keys
-func (o ORM) RetrieveMany(ctx context.Context, table string, queryVals map[string]interface{}) (object.Array, error) {
-	return o.retrieveManyCore(ctx, nil, table, queryVals)
+// RetrieveMany function will fleshen a top-level object structure, given some primary keys.
+// Only the table's EssentialColumns are selected, unless WithAllColumns is given.
+// A nil or NULL query value matches NULL columns, unless
+// WithNullSemantics(NullStandard) is given.
+func (o ORM) RetrieveMany(ctx context.Context, table string, queryVals map[string]interface{}, opts ...RetrieveOption) (object.Array, error) {
+	columns, queryVals := o.applyRetrieveOptions(table, queryVals, opts)
+	return o.retrieveManyCore(ctx, nil, table, columns, queryVals)
+}
+
+// RetrieveManyColumns is RetrieveMany, but only selects the given columns.
+func (o ORM) RetrieveManyColumns(ctx context.Context, table string, columns []string, queryVals map[string]interface{}) (object.Array, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("RetrieveManyColumns: no columns given for table " + table)
+	}
+	return o.retrieveManyCore(ctx, nil, table, columns, queryVals)
+}
+
+// RetrieveEach streams the objects matching queryVals to fn one row at a
+// time, rather than loading them all into memory. Iteration stops at the
+// first error returned by fn, which RetrieveEach then returns.
+func (o ORM) RetrieveEach(ctx context.Context, table string, queryVals map[string]interface{}, fn func(*object.Object) error) error {
+	return o.retrieveEachCore(ctx, nil, table, queryVals, fn)
+}
+
+// RetrieveEachTx is RetrieveEach inside a transaction.
+func (o ORM) RetrieveEachTx(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}, fn func(*object.Object) error) error {
+	return o.retrieveEachCore(ctx, tx, table, queryVals, fn)
+}
+
+func (o ORM) retrieveEachCore(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}, fn func(*object.Object) error) (err error) {
+	ctx, span := o.startSpan(ctx, "RetrieveEach", table)
+	defer endSpan(span, &err)
+	return o.retrieveEach(ctx, tx, table, queryVals, fn)
+}
+
+func (o ORM) retrieveEach(ctx context.Context, tx *sql.Tx, table string, queryVals map[string]interface{}, fn func(*object.Object) error) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if fn == nil {
+		return errors.New("RetrieveEach: nil callback")
+	}
+
+	sqlStr, columnNames, bindArgs, err := o.renderRetrieve(table, nil, queryVals)
+	if err != nil {
+		return err
+	}
+
+	return o.eachObject(ctx, tx, table, sqlStr, columnNames, bindArgs, fn)
+}
+
+// convertValues normalizes retrieved values to the types the schema asks for:
+// the numbers returned for boolean columns by dialects without a boolean
+// column type become bools, and decimal columns are rendered with the
+// column's Scale, since some drivers drop trailing zeros.
+func convertValues(objTable *schema.Table, obj *object.Object) {
+	if objTable == nil {
+		return
+	}
+	for k, v := range obj.KV {
+		f := objTable.GetColumn(k)
+		if f == nil {
+			continue
+		}
+		if f.IsDecimal() {
+			if d, ok, err := f.DecimalString(v); ok && err == nil {
+				obj.KV[k] = d
+			}
+			continue
+		}
+		if !f.IsBoolean() {
+			continue
+		}
+		switch n := v.(type) {
+		case int64:
+			obj.KV[k] = n != 0
+		case float64:
+			obj.KV[k] = n != 0
+		}
+	}
 }
@@ -0,0 +1,34 @@
+package orm
+
+import (
+	"time"
+)
+
+// Metrics receives the timing of the statements run by the ORM. op is one of
+// "Insert", "Update", "Delete", "Retrieve", "Exists", "Aggregate" or "Exec",
+// where "Retrieve" covers every statement that selects objects (Retrieve,
+// RetrieveMany, RetrieveEach, Query, RetrieveJoined, RetrieveManyFromCustomSQL,
+// RawQuery and OpenCursor) and "Exec" the statements of RawExec, whose table
+// is empty. d includes scanning the rows, and for RetrieveEach the
+// time spent in the callback. err is the error returned to the caller, if
+// any. Implementations must be safe for concurrent use.
+type Metrics interface {
+	ObserveQuery(op string, table string, d time.Duration, err error)
+}
+
+// startObserve returns the time an operation started, or the zero time if
+// the ORM has no Metrics, so that the default costs nothing but a nil check.
+func (o ORM) startObserve() time.Time {
+	if o.Metrics == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// observe reports an operation which began at start to the ORM's Metrics.
+func (o ORM) observe(op string, table string, start time.Time, err error) {
+	if o.Metrics == nil {
+		return
+	}
+	o.Metrics.ObserveQuery(op, table, time.Since(start), err)
+}
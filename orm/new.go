@@ -2,6 +2,8 @@ package orm
 
 import (
 	"database/sql"
+	"sync/atomic"
+	"time"
 
 	"github.com/rbastic/dyndao/object"
 	"github.com/rbastic/dyndao/schema"
@@ -25,6 +27,43 @@ type ORM struct {
 
 	BeforeUpdateHooks map[string]HookFunction
 	AfterUpdateHooks  map[string]HookFunction
+
+	// Codecs are keyed by table name, then by column name. See
+	// RegisterCodec.
+	Codecs map[string]map[string]Codec
+
+	// ValidateBeforeSave makes Save call Validate on an object before
+	// inserting or updating it.
+	ValidateBeforeSave bool
+
+	// LengthPolicy says what inserts and updates do with strings longer
+	// than their column's Length.
+	LengthPolicy LengthPolicy
+
+	// Metrics, if set, is told the duration and outcome of every
+	// operation.
+	Metrics Metrics
+
+	// Tracer, if set, starts a span around every operation. TraceSQL adds
+	// the statements run to the spans.
+	Tracer   Tracer
+	TraceSQL bool
+
+	// Timeout, if set, bounds every operation, see WithTimeout. A Cursor is
+	// bounded until it is closed, while the transactions of Begin, which
+	// outlive the call, are not: their statements are bounded one by one.
+	Timeout time.Duration
+
+	// KeepDBOpen stops Close from closing RawConn and the readers, for
+	// databases the ORM does not own.
+	KeepDBOpen bool
+
+	prepared *int64 // shared by copies of the ORM
+
+	// readers receive the statements which only read, outside of
+	// transactions. RawConn is used when there are none.
+	readers    []*sql.DB
+	nextReader *uint64
 }
 
 // GetSchema returns the ORM's active schema
@@ -32,6 +71,15 @@ func (o ORM) GetSchema() *schema.Schema {
 	return o.s
 }
 
+// PreparedStatements returns the number of statements the ORM has prepared
+// since it was constructed.
+func (o ORM) PreparedStatements() int64 {
+	if o.prepared == nil {
+		return 0
+	}
+	return atomic.LoadInt64(o.prepared)
+}
+
 func (o ORM) UseTracing() bool {
 	return o.sqlGen.Tracing
 }
@@ -47,7 +95,7 @@ func makeEmptyHookMap() map[string]HookFunction {
 
 // New is the ORM constructor. It expects a SQL generator, JSON/SQL Schema object, and database connection.
 func New(gen *sg.SQLGenerator, s *schema.Schema, db *sql.DB) ORM {
-	o := ORM{sqlGen: gen, s: s, RawConn: db}
+	o := ORM{sqlGen: gen, s: s, RawConn: db, prepared: new(int64)}
 
 	o.BeforeCreateHooks = makeEmptyHookMap()
 	o.AfterCreateHooks = makeEmptyHookMap()
@@ -55,9 +103,50 @@ func New(gen *sg.SQLGenerator, s *schema.Schema, db *sql.DB) ORM {
 	o.BeforeUpdateHooks = makeEmptyHookMap()
 	o.AfterUpdateHooks = makeEmptyHookMap()
 
+	o.Codecs = make(map[string]map[string]Codec)
+
 	return o
 }
 
+// NewWithReaders is New for a database with read replicas. Retrieve,
+// RetrieveMany, Query, Exists, Aggregate and cursors are sent to the readers
+// in turn, while everything that writes, and every transaction, uses the
+// writer. Replicas may lag behind the writer, so a row that was just saved may
+// not be retrievable at once. The reads of CreateOrUpdate, FindOrCreate and
+// Refresh, which must see the latest writes, use the writer too.
+func NewWithReaders(gen *sg.SQLGenerator, s *schema.Schema, writer *sql.DB, readers ...*sql.DB) ORM {
+	o := New(gen, s, writer)
+	o.readers = readers
+	o.nextReader = new(uint64)
+	return o
+}
+
+// readerDB returns the database to read from, round-robin across the
+// readers.
+func (o ORM) readerDB() *sql.DB {
+	if len(o.readers) == 0 {
+		return o.RawConn
+	}
+	n := atomic.AddUint64(o.nextReader, 1) - 1
+	return o.readers[n%uint64(len(o.readers))]
+}
+
+// writerOnly returns a copy of the ORM which sends its reads to the writer,
+// for reads that decide a write or must see the caller's own writes.
+func (o ORM) writerOnly() ORM {
+	o.readers = nil
+	return o
+}
+
+// NewValidated is New, but first checks the schema for self-consistency with
+// Schema.Validate, returning its error if any.
+func NewValidated(gen *sg.SQLGenerator, s *schema.Schema, db *sql.DB) (ORM, error) {
+	if err := s.Validate(); err != nil {
+		return ORM{}, err
+	}
+	return New(gen, s, db), nil
+}
+
 // Software trigger functions
 
 // CallBeforeCreateHookIfNeeded will call the necessary BeforeCreate triggers for a given
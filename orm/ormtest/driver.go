@@ -0,0 +1,180 @@
+package ormtest
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// connector hands out connections to a DB, so that it can be opened with
+// sql.OpenDB without registering a driver.
+type connector struct {
+	d *DB
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{d: c.d}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("ormtest: databases are only opened by New")
+}
+
+// conn is a connection to a DB. It holds the snapshots its transaction and
+// savepoints roll back to.
+type conn struct {
+	d          *DB
+	tx         *snapshot
+	savepoints []*snapshot
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	if c.tx != nil {
+		return nil, errors.New("ormtest: transaction already in progress")
+	}
+	c.tx = c.d.snapshot("")
+	return tx{c}, nil
+}
+
+type tx struct {
+	c *conn
+}
+
+func (t tx) Commit() error {
+	t.c.d.mu.Lock()
+	defer t.c.d.mu.Unlock()
+	t.c.tx = nil
+	t.c.savepoints = nil
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.c.d.mu.Lock()
+	defer t.c.d.mu.Unlock()
+	if t.c.tx != nil {
+		t.c.d.restore(t.c.tx)
+	}
+	t.c.tx = nil
+	t.c.savepoints = nil
+	return nil
+}
+
+type stmt struct {
+	c     *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput is -1, the number of placeholders is checked when the statement
+// is run.
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	res, _, err := s.c.d.run(s.c, s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	_, rs, err := s.c.d.run(s.c, s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.Exec(namedValues(args))
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.Query(namedValues(args))
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	vals := make([]driver.Value, len(args))
+	for i, a := range args {
+		vals[i] = a.Value
+	}
+	return vals
+}
+
+type result struct {
+	lastID       int64
+	rowsAffected int64
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return r.lastID, nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// rows is a result set. It reports the schema's column types, which the
+// generator uses to choose what each column is scanned into.
+type rows struct {
+	columns []string
+	types   []string
+	data    [][]driver.Value
+	pos     int
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.pos])
+	r.pos++
+	return nil
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(i int) string {
+	return r.types[i]
+}
+
+// ColumnTypeNullable reports every column as nullable, so that NULLs always
+// scan.
+func (r *rows) ColumnTypeNullable(i int) (bool, bool) {
+	return true, true
+}
@@ -0,0 +1,452 @@
+package ormtest
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// run records and runs a statement for a connection. Statements which don't
+// return rows produce an empty result set.
+func (d *DB) run(c *conn, query string, args []driver.Value) (result, *rows, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	recorded := make([]interface{}, len(args))
+	for i, a := range args {
+		recorded[i] = a
+	}
+	d.statements = append(d.statements, Statement{SQL: query, Args: recorded})
+
+	st, err := d.parse(query, args)
+	if err != nil {
+		return result{}, nil, err
+	}
+	switch st := st.(type) {
+	case *insertStmt:
+		return d.runInsert(st)
+	case *selectStmt:
+		rs, err := d.runSelect(st)
+		return result{}, rs, err
+	case *updateStmt:
+		return d.runUpdate(st)
+	case *deleteStmt:
+		return d.runDelete(st)
+	case *savepointStmt:
+		return result{}, &rows{}, d.runSavepoint(c, st)
+	}
+	return result{}, nil, errors.New("ormtest: unsupported statement: " + query)
+}
+
+func (d *DB) runInsert(st *insertStmt) (result, *rows, error) {
+	var res result
+	rs := &rows{}
+	for _, name := range st.returning {
+		rs.columns = append(rs.columns, name)
+		rs.types = append(rs.types, d.gen.ColumnDBType(d.gen, columnByName(st.schTable, name)))
+	}
+
+	for _, vals := range st.rows {
+		r := make(row, len(st.columns))
+		for i, col := range st.columns {
+			r[col] = copyValue(vals[i])
+		}
+		if err := d.checkUnique(st, r); err != nil {
+			return result{}, nil, err
+		}
+		if id := d.assignKey(st.table, st.schTable, r); id != 0 {
+			res.lastID = id
+		}
+		d.tables[st.table] = append(d.tables[st.table], r)
+		d.writes = append(d.writes, Write{Op: "INSERT", Table: st.table, Values: r.copy()})
+		res.rowsAffected++
+
+		if len(st.returning) > 0 {
+			out := make([]driver.Value, len(st.returning))
+			for i, name := range st.returning {
+				out[i] = r[name]
+			}
+			rs.data = append(rs.data, out)
+		}
+	}
+	return res, rs, nil
+}
+
+// checkUnique fails an insert which repeats the primary key of an existing
+// row, as the database would.
+func (d *DB) checkUnique(st *insertStmt, r row) error {
+	pk := st.schTable.GetColumn(st.schTable.Primary)
+	if pk == nil || st.schTable.MultiKey {
+		return nil
+	}
+	v, ok := r[pk.Name]
+	if !ok || v == nil {
+		return nil
+	}
+	for _, existing := range d.tables[st.table] {
+		if valuesEqual(existing[pk.Name], v) {
+			return errors.Errorf("ormtest: UNIQUE constraint failed: %s.%s", st.table, pk.Name)
+		}
+	}
+	return nil
+}
+
+func (d *DB) runSelect(st *selectStmt) (*rows, error) {
+	var matched []row
+	for _, r := range d.tables[st.table] {
+		ok, err := st.where.matches(r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, r)
+		}
+	}
+
+	if len(st.orders) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			for _, o := range st.orders {
+				c, _ := compareValues(matched[i][o.column], matched[j][o.column])
+				if matched[i][o.column] == nil && matched[j][o.column] != nil {
+					c = -1 // NULLs sort first, as in SQLite
+				} else if matched[i][o.column] != nil && matched[j][o.column] == nil {
+					c = 1
+				}
+				if c != 0 {
+					return (c < 0) != o.descending
+				}
+			}
+			return false
+		})
+	}
+
+	if st.offset > 0 {
+		if st.offset >= int64(len(matched)) {
+			matched = nil
+		} else {
+			matched = matched[st.offset:]
+		}
+	}
+	if st.limit >= 0 && st.limit < int64(len(matched)) {
+		matched = matched[:st.limit]
+	}
+
+	rs := &rows{}
+	for _, c := range st.columns {
+		rs.columns = append(rs.columns, c.text)
+		if c.column == "" {
+			rs.types = append(rs.types, "INTEGER")
+		} else {
+			rs.types = append(rs.types, d.gen.ColumnDBType(d.gen, columnByName(st.schTable, c.column)))
+		}
+	}
+	for _, r := range matched {
+		out := make([]driver.Value, len(st.columns))
+		for i, c := range st.columns {
+			if c.column == "" {
+				out[i] = c.literal
+			} else {
+				out[i] = copyValue(r[c.column])
+			}
+		}
+		rs.data = append(rs.data, out)
+	}
+	return rs, nil
+}
+
+func (d *DB) runUpdate(st *updateStmt) (result, *rows, error) {
+	var res result
+	for _, r := range d.tables[st.table] {
+		ok, err := st.where.matches(r)
+		if err != nil {
+			return result{}, nil, err
+		}
+		if !ok {
+			continue
+		}
+		for _, a := range st.set {
+			r[a.column] = copyValue(a.value)
+		}
+		d.writes = append(d.writes, Write{Op: "UPDATE", Table: st.table, Values: r.copy()})
+		res.rowsAffected++
+	}
+	return res, &rows{}, nil
+}
+
+func (d *DB) runDelete(st *deleteStmt) (result, *rows, error) {
+	var res result
+	rs := &rows{}
+	for _, name := range st.returning {
+		rs.columns = append(rs.columns, name)
+		rs.types = append(rs.types, d.gen.ColumnDBType(d.gen, columnByName(st.schTable, name)))
+	}
+	var kept []row
+	for _, r := range d.tables[st.table] {
+		ok, err := st.where.matches(r)
+		if err != nil {
+			return result{}, nil, err
+		}
+		if !ok {
+			kept = append(kept, r)
+			continue
+		}
+		d.writes = append(d.writes, Write{Op: "DELETE", Table: st.table, Values: r.copy()})
+		res.rowsAffected++
+
+		if len(st.returning) > 0 {
+			out := make([]driver.Value, len(st.returning))
+			for i, name := range st.returning {
+				out[i] = r[name]
+			}
+			rs.data = append(rs.data, out)
+		}
+	}
+	d.tables[st.table] = kept
+	return res, rs, nil
+}
+
+func (d *DB) runSavepoint(c *conn, st *savepointStmt) error {
+	if st.op == "SAVEPOINT" {
+		c.savepoints = append(c.savepoints, d.snapshot(st.name))
+		return nil
+	}
+	for i := len(c.savepoints) - 1; i >= 0; i-- {
+		sp := c.savepoints[i]
+		if !strings.EqualFold(sp.name, st.name) {
+			continue
+		}
+		if st.op == "RELEASE" {
+			c.savepoints = c.savepoints[:i]
+		} else {
+			// ROLLBACK TO keeps the savepoint itself
+			d.restore(d.snapshotOf(sp))
+			c.savepoints = c.savepoints[:i+1]
+		}
+		return nil
+	}
+	return errors.New("ormtest: no such savepoint: " + st.name)
+}
+
+// snapshotOf copies a snapshot, so that it can be restored more than once.
+func (d *DB) snapshotOf(s *snapshot) *snapshot {
+	current := d.tables
+	d.tables = s.tables
+	c := d.snapshot(s.name)
+	c.writes = s.writes
+	d.tables = current
+	return c
+}
+
+func (cond condition) matches(r row) (bool, error) {
+	if cond == nil {
+		return true, nil
+	}
+	for _, group := range cond {
+		all := true
+		for _, p := range group {
+			ok, err := p.matches(r)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matches evaluates a predicate as SQL does, where a comparison with NULL is
+// never true.
+func (p predicate) matches(r row) (bool, error) {
+	left := p.left.eval(r)
+	switch p.op {
+	case "IS NULL":
+		return left == nil, nil
+	case "IS NOT NULL":
+		return left != nil, nil
+	case "IN", "NOT IN":
+		if left == nil {
+			return false, nil
+		}
+		found := false
+		for _, o := range p.list {
+			if valuesEqual(left, o.eval(r)) {
+				found = true
+				break
+			}
+		}
+		return found == (p.op == "IN"), nil
+	case "LIKE", "NOT LIKE":
+		right := p.right.eval(r)
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if !lok || !rok {
+			return false, nil
+		}
+		return likeMatch(ls, rs) == (p.op == "LIKE"), nil
+	}
+
+	c, ok := compareValues(left, p.right.eval(r))
+	if !ok {
+		return false, nil
+	}
+	switch p.op {
+	case "=":
+		return c == 0, nil
+	case "<>", "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return false, errors.New("ormtest: unsupported operator " + p.op)
+}
+
+func (o operand) eval(r row) driver.Value {
+	if o.column != "" {
+		return r[o.column]
+	}
+	return o.value
+}
+
+// likeMatch matches s against a LIKE pattern, case-insensitively as SQLite
+// does.
+func likeMatch(s, pattern string) bool {
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String()).MatchString(s)
+}
+
+// valuesEqual reports whether two values are equal, treating two NULLs as
+// equal for assertions.
+func valuesEqual(a, b driver.Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	c, ok := compareValues(a, b)
+	return ok && c == 0
+}
+
+// compareValues compares two non-NULL values of compatible types, reporting
+// false for NULLs and for values that cannot be compared.
+func compareValues(a, b driver.Value) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	if x, ok := a.(int64); ok {
+		if y, ok := b.(int64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	if x, ok := numberValue(a); ok {
+		y, ok := numberValue(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	if x, ok := a.(time.Time); ok {
+		y, ok := timeValue(b)
+		if !ok {
+			return 0, false
+		}
+		return x.Compare(y), true
+	}
+	if _, ok := b.(time.Time); ok {
+		c, ok := compareValues(b, a)
+		return -c, ok
+	}
+	x, ok := bytesValue(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := bytesValue(b)
+	if !ok {
+		return 0, false
+	}
+	return bytes.Compare(x, y), true
+}
+
+func numberValue(v driver.Value) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func timeValue(v driver.Value) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func bytesValue(v driver.Value) ([]byte, bool) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), true
+	case []byte:
+		return v, true
+	}
+	return nil, false
+}
+
+func copyValue(v driver.Value) driver.Value {
+	if b, ok := v.([]byte); ok {
+		return append([]byte(nil), b...)
+	}
+	return v
+}
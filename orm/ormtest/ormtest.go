@@ -0,0 +1,326 @@
+// Package ormtest provides an in-memory database for unit testing code that
+// uses the ORM, without a real database server.
+//
+// New returns an ORM backed by the SQLite generator and a fake database/sql
+// driver, along with the DB holding its tables. Every statement the ORM runs
+// is recorded with its arguments, and the statements that write are applied
+// to the in-memory tables, so that application code can Save, Retrieve,
+// Query and Delete as it would against a real database:
+//
+//	o, db := ormtest.New(sch)
+//	// ... exercise the code under test with o ...
+//	db.AssertSaved(t, "people", map[string]interface{}{"Name": "Ryan"})
+//
+// Only the statements the core generator renders for single tables are
+// understood: INSERT (including multi-row inserts and RETURNING), SELECT with
+// a WHERE clause, ORDER BY and LIMIT, UPDATE, DELETE (including RETURNING)
+// and savepoints. Anything else, such as joins, aggregates and DDL, is
+// recorded and then fails with an unsupported statement error. BulkUpdate, which would need a temporary
+// table, updates its objects one at a time. Transactions are not isolated from each other:
+// rolling one back restores every table to its state when it began.
+package ormtest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/adapters/sqlite"
+	"github.com/rbastic/dyndao/orm"
+	"github.com/rbastic/dyndao/schema"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// Statement is a single statement run against a DB, with the arguments it
+// was run with.
+type Statement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Write is a single row written by a statement. Op is INSERT, UPDATE or
+// DELETE and Table is the schema table name. Values holds the row, keyed by
+// column name, as it was after an INSERT or UPDATE, and as it was deleted
+// for a DELETE.
+type Write struct {
+	Op     string
+	Table  string
+	Values map[string]interface{}
+}
+
+type row map[string]interface{}
+
+// DB is the in-memory database behind an ORM returned by New. It is safe for
+// concurrent use.
+type DB struct {
+	sch *schema.Schema
+	gen *sg.SQLGenerator
+
+	mu         sync.Mutex
+	tables     map[string][]row // keyed by schema table name
+	statements []Statement
+	writes     []Write
+}
+
+// New returns an ORM for the schema which runs against a new, empty DB.
+func New(sch *schema.Schema) (orm.ORM, *DB) {
+	d := &DB{
+		sch:    sch,
+		gen:    sqlite.New(core.New()),
+		tables: make(map[string][]row),
+	}
+	d.gen.BulkUpdateSQL = func(*sg.SQLGenerator, *schema.Table, string, string, []string, []string) *sg.BulkUpdate {
+		return nil
+	}
+	return orm.New(d.gen, sch, sql.OpenDB(connector{d})), d
+}
+
+// Seed adds a row to a table without recording it as a statement or a
+// write, for the data a test starts from. Values may be keyed by column name
+// or alias. An integer primary key the database would assign is assigned
+// when the row leaves it out.
+func (d *DB) Seed(table string, values map[string]interface{}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tblName, schTable, err := d.lookupTable(table)
+	if err != nil {
+		return err
+	}
+	r := make(row, len(values))
+	for k, v := range values {
+		f := schTable.GetColumn(k)
+		if f == nil {
+			return errors.New("ormtest: Seed: unknown column " + k + " in table " + table)
+		}
+		if r[f.Name], err = convertValue(v); err != nil {
+			return errors.Wrap(err, "ormtest: Seed")
+		}
+	}
+	d.assignKey(tblName, schTable, r)
+	d.tables[tblName] = append(d.tables[tblName], r)
+	return nil
+}
+
+// Rows returns a copy of the rows of a table, keyed by column name, in the
+// order they were inserted.
+func (d *DB) Rows(table string) []map[string]interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tblName, _, err := d.lookupTable(table)
+	if err != nil {
+		return nil
+	}
+	rows := make([]map[string]interface{}, len(d.tables[tblName]))
+	for i, r := range d.tables[tblName] {
+		rows[i] = r.copy()
+	}
+	return rows
+}
+
+// Statements returns the statements run so far, in order.
+func (d *DB) Statements() []Statement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Statement(nil), d.statements...)
+}
+
+// Writes returns the rows written so far, in order. Writes made inside a
+// transaction or savepoint that was rolled back are left out.
+func (d *DB) Writes() []Write {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Write(nil), d.writes...)
+}
+
+// Reset forgets the statements and writes recorded so far, leaving the
+// tables as they are.
+func (d *DB) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = nil
+	d.writes = nil
+}
+
+// Saved reports whether a row of the table was inserted or updated with the
+// given values. Values may be keyed by column name or alias, and only the
+// columns given are compared.
+func (d *DB) Saved(table string, values map[string]interface{}) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.saved(table, values)
+}
+
+// AssertSaved fails the test unless a row of the table was inserted or
+// updated with the given values, as reported by Saved.
+func (d *DB) AssertSaved(t testing.TB, table string, values map[string]interface{}) {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.saved(table, values) {
+		t.Errorf("ormtest: no %s row was saved with %v, saved: %s", table, values, d.describeSaves(table))
+	}
+}
+
+// AssertNotSaved fails the test if a row of the table was inserted or
+// updated with the given values.
+func (d *DB) AssertNotSaved(t testing.TB, table string, values map[string]interface{}) {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.saved(table, values) {
+		t.Errorf("ormtest: a %s row was saved with %v", table, values)
+	}
+}
+
+func (d *DB) saved(table string, values map[string]interface{}) bool {
+	tblName, schTable, err := d.lookupTable(table)
+	if err != nil {
+		return false
+	}
+	want := make(row, len(values))
+	for k, v := range values {
+		f := schTable.GetColumn(k)
+		if f == nil {
+			return false
+		}
+		if want[f.Name], err = convertValue(v); err != nil {
+			return false
+		}
+	}
+	for _, w := range d.writes {
+		if w.Table != tblName || w.Op == "DELETE" {
+			continue
+		}
+		if row(w.Values).matches(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeSaves lists the rows saved to a table, for test failures.
+func (d *DB) describeSaves(table string) string {
+	tblName, _, _ := d.lookupTable(table)
+	var saves []string
+	for _, w := range d.writes {
+		if w.Table == tblName && w.Op != "DELETE" {
+			saves = append(saves, fmt.Sprintf("%s %v", w.Op, w.Values))
+		}
+	}
+	if len(saves) == 0 {
+		return "none"
+	}
+	return strings.Join(saves, ", ")
+}
+
+// lookupTable returns the schema table for a schema table name or alias.
+func (d *DB) lookupTable(table string) (string, *schema.Table, error) {
+	schTable := d.sch.GetTable(table)
+	if schTable == nil {
+		return "", nil, errors.New("ormtest: unknown table " + table)
+	}
+	for name, t := range d.sch.Tables {
+		if t == schTable {
+			return name, t, nil
+		}
+	}
+	return "", nil, errors.New("ormtest: unknown table " + table)
+}
+
+// physicalTable returns the schema table for a table name as it appears in
+// generated SQL.
+func (d *DB) physicalTable(name string) (string, *schema.Table, error) {
+	for key, t := range d.sch.Tables {
+		if strings.EqualFold(d.sch.RealTableName(t.Name, key), name) {
+			return key, t, nil
+		}
+	}
+	return "", nil, errors.New("ormtest: no such table: " + name)
+}
+
+// assignKey gives r the next integer primary key, as the database would,
+// if the table's key is assigned on insert and r leaves it out.
+func (d *DB) assignKey(tblName string, schTable *schema.Table, r row) int64 {
+	pk := schTable.GetColumn(schTable.Primary)
+	if pk == nil || !schTable.UsesLastInsertID() {
+		return 0
+	}
+	if v, ok := r[pk.Name]; ok && v != nil {
+		id, _ := v.(int64)
+		return id
+	}
+	var maxID int64
+	for _, existing := range d.tables[tblName] {
+		if id, ok := existing[pk.Name].(int64); ok && id > maxID {
+			maxID = id
+		}
+	}
+	r[pk.Name] = maxID + 1
+	return maxID + 1
+}
+
+// snapshot is the state of a DB saved when a transaction or savepoint
+// begins, to restore on rollback.
+type snapshot struct {
+	name   string
+	tables map[string][]row
+	writes int
+}
+
+func (d *DB) snapshot(name string) *snapshot {
+	s := &snapshot{name: name, tables: make(map[string][]row, len(d.tables)), writes: len(d.writes)}
+	for k, rows := range d.tables {
+		copied := make([]row, len(rows))
+		for i, r := range rows {
+			copied[i] = r.copy()
+		}
+		s.tables[k] = copied
+	}
+	return s
+}
+
+func (d *DB) restore(s *snapshot) {
+	d.tables = s.tables
+	if s.writes < len(d.writes) {
+		d.writes = d.writes[:s.writes]
+	}
+}
+
+func (r row) copy() row {
+	c := make(row, len(r))
+	for k, v := range r {
+		c[k] = v
+	}
+	return c
+}
+
+// matches reports whether r holds every value in want.
+func (r row) matches(want row) bool {
+	for k, v := range want {
+		if !valuesEqual(r[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// convertValue converts a Go value to the driver.Value that database/sql
+// would pass to the driver for it.
+func convertValue(v interface{}) (driver.Value, error) {
+	dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := dv.([]byte); ok {
+		dv = append([]byte(nil), b...)
+	}
+	return dv, nil
+}
@@ -0,0 +1,220 @@
+package ormtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/orm"
+	"github.com/rbastic/dyndao/query"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func TestSaveAndRetrieve(t *testing.T) {
+	ctx := context.Background()
+	o, db := New(mock.NestedSchema())
+
+	person := mock.DefaultPersonWithAddress()
+	if _, err := o.SaveAll(ctx, person); err != nil {
+		t.Fatal(err)
+	}
+	if person.Get("PersonID") != int64(1) {
+		t.Fatal("expected the first person to get key 1, got", person.Get("PersonID"))
+	}
+	db.AssertSaved(t, "people", map[string]interface{}{"Name": "Ryan", "NullText": nil})
+	db.AssertSaved(t, "addresses", map[string]interface{}{"PersonID": 1, "City": "Nowhere"})
+	db.AssertNotSaved(t, "people", map[string]interface{}{"Name": "Joe"})
+
+	got, err := o.Retrieve(ctx, "people", map[string]interface{}{"PersonID": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Get("Name") != "Ryan" || !got.ValueIsNULL(got.Get("NullInt")) {
+		t.Fatal("unexpected retrieved person", got)
+	}
+
+	got.Set("Name", "Joe")
+	if _, err := o.Save(ctx, nil, got); err != nil {
+		t.Fatal(err)
+	}
+	db.AssertSaved(t, "people", map[string]interface{}{"PersonID": 1, "Name": "Joe"})
+
+	var update Statement
+	for _, st := range db.Statements() {
+		if strings.HasPrefix(st.SQL, "UPDATE people") {
+			update = st
+		}
+	}
+	if len(update.Args) != 2 || update.Args[0] != "Joe" || update.Args[1] != int64(1) {
+		t.Fatal("unexpected update statement", update)
+	}
+
+	if rows := db.Rows("people"); len(rows) != 1 || rows[0]["Name"] != "Joe" {
+		t.Fatal("unexpected people rows", rows)
+	}
+}
+
+func TestQueryAndDelete(t *testing.T) {
+	ctx := context.Background()
+	o, db := New(mock.NestedSchema())
+	for _, name := range []string{"Ann", "Bob", "Cat"} {
+		if err := db.Seed("people", map[string]interface{}{"Name": name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(db.Statements()) != 0 || len(db.Writes()) != 0 {
+		t.Fatal("expected seeding to record nothing")
+	}
+
+	objs, err := o.Query(ctx, query.New().From("people").Where("Name", "<>", "Bob").OrderByDesc("Name").Limit(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0].Get("Name") != "Cat" {
+		t.Fatal("unexpected query result", objs)
+	}
+
+	exists, err := o.Exists(ctx, "people", map[string]interface{}{"Name": "Bob"})
+	if err != nil || !exists {
+		t.Fatal("expected Bob to exist", err)
+	}
+
+	bob := object.New("people")
+	bob.Set("PersonID", 2)
+	if _, err := o.Delete(ctx, nil, bob); err != nil {
+		t.Fatal(err)
+	}
+	if rows := db.Rows("people"); len(rows) != 2 {
+		t.Fatal("expected Bob to be deleted", rows)
+	}
+	if w := db.Writes(); len(w) != 1 || w[0].Op != "DELETE" || w[0].Values["Name"] != "Bob" {
+		t.Fatal("unexpected writes", w)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	o, db := New(mock.BasicSchema())
+
+	tx, err := o.RawConn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := object.New("people")
+	obj.Set("Name", "Ryan")
+	if _, err := o.Save(ctx, tx, obj); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if rows := db.Rows("people"); len(rows) != 0 {
+		t.Fatal("expected the rollback to remove the row", rows)
+	}
+	db.AssertNotSaved(t, "people", map[string]interface{}{"Name": "Ryan"})
+}
+
+func TestUnsupportedStatement(t *testing.T) {
+	o, db := New(mock.BasicSchema())
+	_, err := o.RawConn.Exec("CREATE TABLE people (PersonID integer)")
+	if err == nil || !strings.Contains(err.Error(), "unsupported statement") {
+		t.Fatal("expected an unsupported statement error, got", err)
+	}
+	if st := db.Statements(); len(st) != 1 {
+		t.Fatal("expected the statement to be recorded", st)
+	}
+}
+
+func TestBulkInsert(t *testing.T) {
+	ctx := context.Background()
+	o, db := New(mock.BasicSchema())
+
+	var objs object.Array
+	for _, name := range []string{"Ann", "Bob"} {
+		obj := object.New("people")
+		obj.Set("Name", name)
+		objs = append(objs, obj)
+	}
+	if _, err := o.BulkInsert(ctx, nil, objs); err != nil {
+		t.Fatal(err)
+	}
+	if objs[0].Get("PersonID") != int64(1) || objs[1].Get("PersonID") != int64(2) {
+		t.Fatal("expected the RETURNING keys to be set", objs[0].Get("PersonID"), objs[1].Get("PersonID"))
+	}
+	db.AssertSaved(t, "people", map[string]interface{}{"PersonID": 2, "Name": "Bob"})
+}
+
+func TestInsertSuppliedKey(t *testing.T) {
+	ctx := context.Background()
+	sch := mock.WidgetSchema()
+	// A string key the database does not generate, so LastInsertId is 0
+	sch.Tables[mock.GadgetsObjectType].Columns["GadgetID"].IsUUID = false
+	o, db := New(sch)
+
+	gadget := object.New(mock.GadgetsObjectType)
+	gadget.Set("GadgetID", "g-1")
+	gadget.Set("Name", "sprocket")
+	res, err := o.InsertWithResult(ctx, nil, gadget)
+	if err != nil {
+		t.Fatal("expected the insert to succeed, got", err)
+	}
+	if res.KeySource != orm.KeyFromCaller || res.PrimaryKey != "g-1" || gadget.Get("GadgetID") != "g-1" {
+		t.Fatal("expected the supplied key to be kept, got", res)
+	}
+	db.AssertSaved(t, mock.GadgetsObjectType, map[string]interface{}{"GadgetID": "g-1", "Name": "sprocket"})
+}
+
+func TestDeleteManyReturning(t *testing.T) {
+	ctx := context.Background()
+	o, db := New(mock.BasicSchema())
+	for _, name := range []string{"Ann", "Bob", "Ann"} {
+		if err := db.Seed("people", map[string]interface{}{"Name": name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, err := o.DeleteManyReturning(ctx, nil, "people", map[string]interface{}{"Name": "Ann"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || deleted[0].Get("PersonID") != int64(1) || deleted[1].Get("PersonID") != int64(3) {
+		t.Fatal("expected both of Ann's rows to be returned", deleted)
+	}
+	if rows := db.Rows("people"); len(rows) != 1 {
+		t.Fatal("expected only Bob to be left", rows)
+	}
+	// The rows are returned by the DELETE itself
+	stmts := db.Statements()
+	if len(stmts) != 1 || !strings.HasPrefix(stmts[0].SQL, "DELETE") || !strings.Contains(stmts[0].SQL, "RETURNING") {
+		t.Fatal("expected a single DELETE ... RETURNING, got", stmts)
+	}
+}
+
+func TestFoldedColumnNames(t *testing.T) {
+	ctx := context.Background()
+	o, db := New(mock.BasicSchema())
+	if err := db.Seed("people", map[string]interface{}{"Name": "Ann"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The columns are reported as they are written, as a driver folding
+	// identifiers to upper or lower case would report them
+	for _, sqlStr := range []string{"SELECT PERSONID, NAME FROM people", "SELECT personid, name FROM people"} {
+		objs, err := o.RawQueryAs(ctx, "people", sqlStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objs) != 1 || objs[0].Get("PersonID") != int64(1) || objs[0].Get("Name") != "Ann" {
+			t.Fatal("expected the columns to land in the schema's fields, got", objs)
+		}
+	}
+
+	objs, err := o.RawQuery(ctx, "SELECT NAME FROM people")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0].Get("NAME") != "Ann" {
+		t.Fatal("expected RawQuery to keep the reported names, got", objs)
+	}
+}
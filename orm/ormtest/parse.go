@@ -0,0 +1,717 @@
+package ormtest
+
+import (
+	"database/sql/driver"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokQuotedIdent
+	tokNumber
+	tokString
+	tokPlaceholder
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits a statement into tokens. Placeholders may be written as
+// ?, $1 or :name, and are bound in the order they appear.
+func tokenize(query string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(query) && isIdentPart(query[j]) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, query[i:j]})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(query) && (query[j] >= '0' && query[j] <= '9' || query[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{tokNumber, query[i:j]})
+			i = j
+		case c == '\'':
+			var sb strings.Builder
+			j := i + 1
+			for {
+				if j >= len(query) {
+					return nil, errors.New("ormtest: unterminated string in " + query)
+				}
+				if query[j] == '\'' {
+					if j+1 < len(query) && query[j+1] == '\'' {
+						sb.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(query[j])
+				j++
+			}
+			toks = append(toks, token{tokString, sb.String()})
+			i = j + 1
+		case c == '"' || c == '`' || c == '[':
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			j := strings.IndexByte(query[i+1:], end)
+			if j < 0 {
+				return nil, errors.New("ormtest: unterminated identifier in " + query)
+			}
+			toks = append(toks, token{tokQuotedIdent, query[i+1 : i+1+j]})
+			i += j + 2
+		case c == '?' || c == '$' || c == ':':
+			j := i + 1
+			for j < len(query) && isIdentPart(query[j]) {
+				j++
+			}
+			toks = append(toks, token{tokPlaceholder, query[i:j]})
+			i = j
+		case strings.HasPrefix(query[i:], "<>") || strings.HasPrefix(query[i:], "!=") ||
+			strings.HasPrefix(query[i:], "<=") || strings.HasPrefix(query[i:], ">="):
+			toks = append(toks, token{tokPunct, query[i : i+2]})
+			i += 2
+		case strings.IndexByte("(),*=<>.", c) >= 0:
+			toks = append(toks, token{tokPunct, query[i : i+1]})
+			i++
+		default:
+			return nil, errors.Errorf("ormtest: unexpected character %q in %s", c, query)
+		}
+	}
+	return append(toks, token{kind: tokEOF}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// The parsed statements. Columns are resolved to the schema's column names
+// and placeholders to their arguments as they are parsed.
+
+type insertStmt struct {
+	table     string
+	schTable  *schema.Table
+	columns   []string
+	rows      [][]driver.Value
+	returning []string
+}
+
+type selectStmt struct {
+	table    string
+	schTable *schema.Table
+	columns  []selectColumn
+	where    condition
+	orders   []order
+	limit    int64 // -1 when there is no LIMIT
+	offset   int64
+}
+
+type selectColumn struct {
+	column  string       // empty for a literal
+	literal driver.Value // selected by SELECT 1
+	text    string       // the name the column is reported with
+}
+
+type order struct {
+	column     string
+	descending bool
+}
+
+type updateStmt struct {
+	table    string
+	schTable *schema.Table
+	set      []assignment
+	where    condition
+}
+
+type assignment struct {
+	column string
+	value  driver.Value
+}
+
+type deleteStmt struct {
+	table     string
+	schTable  *schema.Table
+	where     condition
+	returning []string
+}
+
+// savepointStmt is SAVEPOINT, RELEASE SAVEPOINT or ROLLBACK TO SAVEPOINT.
+type savepointStmt struct {
+	op   string
+	name string
+}
+
+// condition is a WHERE clause, an OR of groups of ANDed predicates. A nil
+// condition matches every row.
+type condition [][]predicate
+
+type predicate struct {
+	left, right operand
+	op          string
+	list        []operand // for IN and NOT IN
+}
+
+// operand is a column of the row, or a value.
+type operand struct {
+	column string
+	value  driver.Value
+}
+
+type parser struct {
+	d     *DB
+	query string
+	toks  []token
+	pos   int
+	args  []driver.Value
+	bound int
+
+	schTable *schema.Table
+}
+
+// parse parses a statement, binding args to its placeholders.
+func (d *DB) parse(query string, args []driver.Value) (interface{}, error) {
+	toks, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{d: d, query: query, toks: toks, args: args}
+
+	var st interface{}
+	switch {
+	case p.accept("INSERT"):
+		st, err = p.parseInsert()
+	case p.accept("SELECT"):
+		st, err = p.parseSelect()
+	case p.accept("UPDATE"):
+		st, err = p.parseUpdate()
+	case p.accept("DELETE"):
+		st, err = p.parseDelete()
+	case p.accept("SAVEPOINT"):
+		st, err = p.parseSavepoint("SAVEPOINT")
+	case p.accept("RELEASE"):
+		p.accept("SAVEPOINT")
+		st, err = p.parseSavepoint("RELEASE")
+	case p.accept("ROLLBACK"):
+		if err := p.expect("TO"); err != nil {
+			return nil, err
+		}
+		p.accept("SAVEPOINT")
+		st, err = p.parseSavepoint("ROLLBACK TO")
+	default:
+		return nil, p.unsupported()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.unsupported()
+	}
+	if p.bound != len(args) {
+		return nil, errors.Errorf("ormtest: %s has %d placeholders but was given %d arguments", query, p.bound, len(args))
+	}
+	return st, nil
+}
+
+func (p *parser) parseInsert() (*insertStmt, error) {
+	if err := p.expect("INTO"); err != nil {
+		return nil, err
+	}
+	st := &insertStmt{}
+	var err error
+	if st.table, st.schTable, err = p.parseTable(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if st.columns, err = p.parseColumnList(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("VALUES"); err != nil {
+		return nil, err
+	}
+	for {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var vals []driver.Value
+		for {
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if len(vals) != len(st.columns) {
+			return nil, errors.Errorf("ormtest: %d values for %d columns in %s", len(vals), len(st.columns), p.query)
+		}
+		st.rows = append(st.rows, vals)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if p.accept("RETURNING") {
+		if st.returning, err = p.parseColumnList(); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func (p *parser) parseSelect() (*selectStmt, error) {
+	st := &selectStmt{limit: -1}
+	// The columns are resolved once the table is known
+	var items []token
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokPunct && t.text == "*", t.kind == tokNumber, t.kind == tokQuotedIdent:
+		case t.kind == tokIdent && !isKeyword(t.text, "FROM"):
+			if p.peek().text == "(" {
+				return nil, p.unsupported()
+			}
+		default:
+			return nil, p.unsupported()
+		}
+		items = append(items, t)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	var err error
+	if st.table, st.schTable, err = p.parseTable(); err != nil {
+		return nil, err
+	}
+
+	for _, t := range items {
+		switch {
+		case t.kind == tokPunct:
+			for _, name := range st.schTable.ColumnNames() {
+				f := st.schTable.GetColumn(name)
+				st.columns = append(st.columns, selectColumn{column: f.Name, text: f.Name})
+			}
+		case t.kind == tokNumber:
+			v, err := parseNumber(t.text)
+			if err != nil {
+				return nil, err
+			}
+			st.columns = append(st.columns, selectColumn{literal: v, text: t.text})
+		default:
+			col, err := p.column(t.text)
+			if err != nil {
+				return nil, err
+			}
+			// Reported as written, as SQLite does
+			st.columns = append(st.columns, selectColumn{column: col, text: t.text})
+		}
+	}
+
+	if st.where, err = p.parseWhere(); err != nil {
+		return nil, err
+	}
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			col, err := p.parseColumn()
+			if err != nil {
+				return nil, err
+			}
+			o := order{column: col}
+			if p.accept("DESC") {
+				o.descending = true
+			} else {
+				p.accept("ASC")
+			}
+			st.orders = append(st.orders, o)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("LIMIT") {
+		if st.limit, err = p.parseCount(); err != nil {
+			return nil, err
+		}
+		if p.accept("OFFSET") {
+			if st.offset, err = p.parseCount(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return st, nil
+}
+
+func (p *parser) parseUpdate() (*updateStmt, error) {
+	st := &updateStmt{}
+	var err error
+	if st.table, st.schTable, err = p.parseTable(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("SET"); err != nil {
+		return nil, err
+	}
+	for {
+		col, err := p.parseColumn()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		st.set = append(st.set, assignment{column: col, value: v})
+		if !p.accept(",") {
+			break
+		}
+	}
+	if st.where, err = p.parseWhere(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func (p *parser) parseDelete() (*deleteStmt, error) {
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	st := &deleteStmt{}
+	var err error
+	if st.table, st.schTable, err = p.parseTable(); err != nil {
+		return nil, err
+	}
+	if st.where, err = p.parseWhere(); err != nil {
+		return nil, err
+	}
+	if p.accept("RETURNING") {
+		if st.returning, err = p.parseColumnList(); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func (p *parser) parseSavepoint(op string) (*savepointStmt, error) {
+	t := p.next()
+	if t.kind != tokIdent && t.kind != tokQuotedIdent {
+		return nil, p.unsupported()
+	}
+	return &savepointStmt{op: op, name: t.text}, nil
+}
+
+// parseTable parses a table name, and an alias if one follows it.
+func (p *parser) parseTable() (string, *schema.Table, error) {
+	t := p.next()
+	if t.kind != tokIdent && t.kind != tokQuotedIdent {
+		return "", nil, p.unsupported()
+	}
+	name, schTable, err := p.d.physicalTable(t.text)
+	if err != nil {
+		return "", nil, err
+	}
+	p.schTable = schTable
+	if a := p.peek(); a.kind == tokIdent && !isKeyword(a.text, "WHERE", "ORDER", "LIMIT", "SET", "VALUES", "RETURNING") {
+		p.next()
+	}
+	return name, schTable, nil
+}
+
+func (p *parser) parseColumnList() ([]string, error) {
+	var cols []string
+	for {
+		col, err := p.parseColumn()
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+		if !p.accept(",") {
+			return cols, nil
+		}
+	}
+}
+
+func (p *parser) parseColumn() (string, error) {
+	t := p.next()
+	if t.kind != tokIdent && t.kind != tokQuotedIdent {
+		return "", p.unsupported()
+	}
+	name := t.text
+	if p.accept(".") {
+		// A column qualified by the table or its alias
+		t = p.next()
+		if t.kind != tokIdent && t.kind != tokQuotedIdent {
+			return "", p.unsupported()
+		}
+		name = t.text
+	}
+	return p.column(name)
+}
+
+// column resolves a column name of the current table.
+func (p *parser) column(name string) (string, error) {
+	f := columnByName(p.schTable, name)
+	if f == nil {
+		return "", errors.New("ormtest: no such column: " + name)
+	}
+	return f.Name, nil
+}
+
+// columnByName returns the column of a table with the given name, as it
+// appears in generated SQL.
+func columnByName(schTable *schema.Table, name string) *schema.Column {
+	for _, f := range schTable.Columns {
+		if f.Name == name {
+			return f
+		}
+	}
+	for _, f := range schTable.Columns {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseWhere() (condition, error) {
+	if !p.accept("WHERE") {
+		return nil, nil
+	}
+	cond := condition{nil}
+	for {
+		pred, err := p.parsePredicate()
+		if err != nil {
+			return nil, err
+		}
+		last := len(cond) - 1
+		cond[last] = append(cond[last], pred)
+		if p.accept("OR") {
+			cond = append(cond, nil)
+		} else if !p.accept("AND") {
+			return cond, nil
+		}
+	}
+}
+
+func (p *parser) parsePredicate() (predicate, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return predicate{}, err
+	}
+	if p.accept("IS") {
+		op := "IS NULL"
+		if p.accept("NOT") {
+			op = "IS NOT NULL"
+		}
+		if err := p.expect("NULL"); err != nil {
+			return predicate{}, err
+		}
+		return predicate{left: left, op: op}, nil
+	}
+
+	op := ""
+	if p.accept("NOT") {
+		op = "NOT "
+	}
+	switch {
+	case p.accept("IN"):
+		if err := p.expect("("); err != nil {
+			return predicate{}, err
+		}
+		pred := predicate{left: left, op: op + "IN"}
+		for {
+			o, err := p.parseOperand()
+			if err != nil {
+				return predicate{}, err
+			}
+			pred.list = append(pred.list, o)
+			if !p.accept(",") {
+				break
+			}
+		}
+		return pred, p.expect(")")
+	case p.accept("LIKE"):
+		op += "LIKE"
+	case op != "":
+		return predicate{}, p.unsupported()
+	default:
+		t := p.next()
+		if t.kind != tokPunct || strings.IndexByte("=<>!", t.text[0]) < 0 {
+			return predicate{}, p.unsupported()
+		}
+		op = t.text
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return predicate{}, err
+	}
+	return predicate{left: left, right: right, op: op}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.peek()
+	if t.kind == tokQuotedIdent || t.kind == tokIdent && !isKeyword(t.text, "NULL", "TRUE", "FALSE") {
+		col, err := p.parseColumn()
+		return operand{column: col}, err
+	}
+	v, err := p.parseValue()
+	return operand{value: v}, err
+}
+
+// parseValue parses a placeholder or a literal. An expression the database
+// would evaluate, such as CURRENT_TIMESTAMP, is taken as its text.
+func (p *parser) parseValue() (driver.Value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokPlaceholder:
+		if p.bound >= len(p.args) {
+			return nil, errors.Errorf("ormtest: %s has more placeholders than the %d arguments given", p.query, len(p.args))
+		}
+		v := p.args[p.bound]
+		p.bound++
+		return v, nil
+	case tokNumber:
+		return parseNumber(t.text)
+	case tokString:
+		return t.text, nil
+	case tokIdent:
+		switch {
+		case isKeyword(t.text, "NULL"):
+			return nil, nil
+		case isKeyword(t.text, "TRUE"):
+			return true, nil
+		case isKeyword(t.text, "FALSE"):
+			return false, nil
+		}
+		text := t.text
+		if p.accept("(") {
+			text += "("
+			for depth := 1; depth > 0; {
+				t := p.next()
+				switch {
+				case t.kind == tokEOF:
+					return nil, p.unsupported()
+				case t.text == "(":
+					depth++
+				case t.text == ")":
+					depth--
+				}
+				if t.kind == tokString {
+					text += "'" + strings.Replace(t.text, "'", "''", -1) + "'"
+				} else {
+					text += t.text
+				}
+			}
+		}
+		return text, nil
+	}
+	return nil, p.unsupported()
+}
+
+func (p *parser) parseCount() (int64, error) {
+	t := p.next()
+	if t.kind != tokNumber {
+		return 0, p.unsupported()
+	}
+	n, err := strconv.ParseUint(t.text, 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "ormtest")
+	}
+	if n > 1<<63-1 {
+		// LIMIT 18446744073709551615 stands for no limit
+		return -1, nil
+	}
+	return int64(n), nil
+}
+
+func parseNumber(text string) (driver.Value, error) {
+	if strings.Contains(text, ".") {
+		f, err := strconv.ParseFloat(text, 64)
+		return f, errors.Wrap(err, "ormtest")
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	return n, errors.Wrap(err, "ormtest")
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the keyword or punctuation s.
+func (p *parser) accept(s string) bool {
+	t := p.peek()
+	if (t.kind == tokIdent || t.kind == tokPunct) && strings.EqualFold(t.text, s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.unsupported()
+	}
+	return nil
+}
+
+func (p *parser) unsupported() error {
+	return errors.New("ormtest: unsupported statement: " + p.query)
+}
+
+func isKeyword(text string, keywords ...string) bool {
+	for _, k := range keywords {
+		if strings.EqualFold(text, k) {
+			return true
+		}
+	}
+	return false
+}
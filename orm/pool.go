@@ -0,0 +1,39 @@
+package orm
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PoolConfig holds connection pool limits for a *sql.DB. A zero field leaves
+// the database's current setting alone.
+//
+// Pool limits interact with per-statement timeouts: the context passed to an
+// ORM method also bounds the time spent waiting for a free connection, so
+// with a small MaxOpenConns a busy pool can make statements hit their
+// deadline before they reach the database. ConnMaxLifetime and
+// ConnMaxIdleTime only retire idle connections and never interrupt a running
+// statement.
+type PoolConfig struct {
+	MaxOpenConns    int           // maximum open connections, negative for no limit
+	MaxIdleConns    int           // maximum idle connections, negative to keep none
+	ConnMaxLifetime time.Duration // maximum age of a connection, negative for no limit
+	ConnMaxIdleTime time.Duration // maximum idle time of a connection, negative for no limit
+}
+
+// ConfigurePool applies cfg to db. It is usually called once, right after
+// sql.Open and before orm.New.
+func ConfigurePool(db *sql.DB, cfg PoolConfig) {
+	if cfg.MaxOpenConns != 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime != 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime != 0 {
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+}
@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
+)
+
+// Query executes a query built with the query package, returning the
+// matching objects.
+func (o ORM) Query(ctx context.Context, q *query.Query) (object.Array, error) {
+	return o.queryCore(ctx, nil, q)
+}
+
+// QueryTx executes a query built with the query package inside a
+// transaction.
+func (o ORM) QueryTx(ctx context.Context, tx *sql.Tx, q *query.Query) (object.Array, error) {
+	return o.queryCore(ctx, tx, q)
+}
+
+func (o ORM) queryCore(ctx context.Context, tx *sql.Tx, q *query.Query) (objs object.Array, err error) {
+	ctx, span := o.startSpan(ctx, "Query", q.Table)
+	defer endSpan(span, &err)
+	return o.runQuery(ctx, tx, q)
+}
+
+func (o ORM) runQuery(ctx context.Context, tx *sql.Tx, q *query.Query) (object.Array, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	sg := o.sqlGen
+	sqlStr, columnNames, bindArgs, err := sg.BindingQuery(sg, o.s, q)
+
+	if sg.Tracing {
+		fmt.Println("Query/sqlStr=", sqlStr, "columnNames=", columnNames, "bindArgs=", bindArgs)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.queryObjects(ctx, tx, q.Table, sqlStr, aliasColumnNames(o.s.GetTable(q.Table), columnNames), bindArgs)
+}
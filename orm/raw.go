@@ -0,0 +1,110 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+)
+
+// RawQuery is an escape hatch for SQL the query builder cannot express. It
+// executes sqlStr with args bound to its placeholders, in the dialect's own
+// placeholder syntax, and returns one clean object per row with an empty
+// Type, holding a value for every non-NULL result column keyed by the
+// column's name. The SQL bypasses the schema entirely: nothing is validated,
+// aliased or converted, so []byte values come back as strings and decimal
+// and boolean columns as the driver returns them. Since the SQL may write,
+// it always runs against the writer rather than a read replica.
+func (o ORM) RawQuery(ctx context.Context, sqlStr string, args ...interface{}) (object.Array, error) {
+	return o.RawQueryAs(ctx, "", sqlStr, args...)
+}
+
+// RawQueryAs is RawQuery, returning objects of type objType. When objType
+// is a schema table, the result columns are keyed by the names of the
+// columns they match, ignoring case, so that PERSONID or personid, as
+// drivers that fold identifiers report them, land in PersonID.
+func (o ORM) RawQueryAs(ctx context.Context, objType string, sqlStr string, args ...interface{}) (objs object.Array, err error) {
+	ctx, span := o.startSpan(ctx, "RawQuery", objType)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	objs, err = o.rawQuery(ctx, nil, objType, sqlStr, args)
+	o.observe("Retrieve", objType, start, err)
+	return objs, err
+}
+
+func (o ORM) rawQuery(ctx context.Context, tx *sql.Tx, objType string, sqlStr string, args []interface{}) (object.Array, error) {
+	if o.sqlGen.Tracing {
+		fmt.Println("RawQuery/sqlStr=", sqlStr, "args=", args)
+	}
+	stmt, err := stmtFromDbOrTx(ctx, o, tx, sqlStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "RawQuery")
+	}
+	defer func() {
+		err := stmt.Close()
+		if err != nil {
+			fmt.Println(err) // TODO logger implementation
+		}
+	}()
+
+	res, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "RawQuery")
+	}
+	defer func() {
+		err := res.Close()
+		if err != nil {
+			fmt.Println(err) // TODO logger implementation
+		}
+	}()
+
+	columnNames, err := res.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if objTable := o.s.GetTable(objType); objType != "" && objTable != nil {
+		for i, name := range columnNames {
+			if match, ok := objTable.MatchColumnName(name); ok {
+				columnNames[i] = match
+			}
+		}
+	}
+	return scanGeneric(ctx, res, objType, columnNames)
+}
+
+// RawExec executes the statement sqlStr with args bound to its placeholders,
+// inside tx if it is not nil, and returns the number of rows affected. Like
+// RawQuery, it bypasses the schema and any validation.
+func (o ORM) RawExec(ctx context.Context, tx *sql.Tx, sqlStr string, args ...interface{}) (rowsAff int64, err error) {
+	ctx, span := o.startSpan(ctx, "RawExec", "")
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	rowsAff, err = o.rawExec(ctx, tx, sqlStr, args)
+	o.observe("Exec", "", start, err)
+	return rowsAff, err
+}
+
+func (o ORM) rawExec(ctx context.Context, tx *sql.Tx, sqlStr string, args []interface{}) (int64, error) {
+	if o.sqlGen.Tracing {
+		fmt.Println("RawExec/sqlStr=", sqlStr, "args=", args)
+	}
+	stmt, err := stmtFromDbOrTx(ctx, o, tx, sqlStr)
+	if err != nil {
+		return 0, errors.Wrap(err, "RawExec")
+	}
+	defer func() {
+		stmtErr := stmt.Close()
+		if stmtErr != nil {
+			fmt.Println(stmtErr) // TODO: logger implementation
+		}
+	}()
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "RawExec")
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/object"
+)
+
+// ErrRowMissing is returned by Refresh when the row an object was loaded from
+// no longer exists.
+var ErrRowMissing = errors.New("dyndao: row no longer exists")
+
+// Refresh re-reads the row for obj, located by its primary key, and
+// overwrites obj's values for the table's DefaultColumns with those in the
+// database. Other values and the children of obj are left alone. Afterwards
+// obj is clean. ErrRowMissing is returned if the row has been deleted.
+func (o ORM) Refresh(ctx context.Context, obj *object.Object) error {
+	return o.refreshCore(ctx, nil, obj)
+}
+
+// RefreshTx is Refresh inside a transaction.
+func (o ORM) RefreshTx(ctx context.Context, tx *sql.Tx, obj *object.Object) error {
+	return o.refreshCore(ctx, tx, obj)
+}
+
+func (o ORM) refreshCore(ctx context.Context, tx *sql.Tx, obj *object.Object) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	objTable := o.s.GetTable(obj.Type)
+	if objTable == nil {
+		return errors.New("Refresh: unknown object table " + obj.Type)
+	}
+
+	queryVals := make(map[string]interface{})
+	for _, pk := range objTable.PrimaryKeyColumns() {
+		v := obj.Get(objTable.GetColumnAlias(pk))
+		if v == nil {
+			v = obj.Get(pk)
+		}
+		if v == nil {
+			return errors.New("Refresh: missing primary key " + pk + " for " + obj.Type)
+		}
+		queryVals[pk] = v
+	}
+
+	retObj, err := o.writerOnly().retrieveCore(ctx, tx, obj.Type, nil, queryVals)
+	if err != nil {
+		return errors.Wrap(err, "Refresh")
+	}
+	if retObj == nil {
+		return ErrRowMissing
+	}
+
+	// Drop the retrieved columns first, so that columns absent from retObj do
+	// not keep their stale values.
+	for _, c := range objTable.DefaultColumns() {
+		delete(obj.KV, c)
+		delete(obj.KV, objTable.GetColumnAlias(objTable.GetColumnName(c)))
+	}
+	for k, v := range retObj.KV {
+		obj.KV[k] = v
+	}
+	obj.ResetChangedColumns()
+	obj.MarkDirty(false)
+	return nil
+}
@@ -0,0 +1,164 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// RetrieveJoined retrieves the objects matching queryVals together with their
+// direct children, joining each child table to the matching objects in a
+// statement of its own, rather than running one query per object and child
+// table as FleshenChildren does. As each child table is joined separately,
+// the rows read are the sum of the children rather than their product. Each
+// returned object's Children holds the child objects for every child table
+// that has matching rows.
+func (o ORM) RetrieveJoined(ctx context.Context, rootTable string, queryVals map[string]interface{}) (objs object.Array, err error) {
+	ctx, span := o.startSpan(ctx, "RetrieveJoined", rootTable)
+	defer endSpan(span, &err)
+	return o.retrieveJoined(ctx, rootTable, queryVals)
+}
+
+func (o ORM) retrieveJoined(ctx context.Context, rootTable string, queryVals map[string]interface{}) (object.Array, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	objTable := o.s.GetTable(rootTable)
+	if objTable == nil {
+		return nil, errors.New("RetrieveJoined: unknown object table " + rootTable)
+	}
+	queryObj, err := o.encodeObject(o.makeQueryObj(objTable, queryVals))
+	if err != nil {
+		return nil, err
+	}
+
+	childNames := make([]string, 0, len(objTable.Children))
+	for name := range objTable.Children {
+		childNames = append(childNames, name)
+	}
+	sort.Strings(childNames)
+	if len(childNames) == 0 {
+		// Without children, a single statement retrieves the objects alone
+		childNames = []string{""}
+	}
+
+	var roots object.Array
+	rootsByKey := make(map[string]*object.Object)
+	for _, childName := range childNames {
+		err := o.eachJoinedRow(ctx, objTable, queryObj, childName, func(rootObj, childObj *object.Object) error {
+			rootKey := joinedKey(objTable, rootObj)
+			root, ok := rootsByKey[rootKey]
+			if !ok {
+				root = rootObj
+				rootsByKey[rootKey] = root
+				roots = append(roots, root)
+			}
+			if childObj != nil {
+				root.Children[childName] = append(root.Children[childName], childObj)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return roots, nil
+}
+
+// eachJoinedRow runs the statement joining the objects matching queryObj to
+// their children of table childName, and calls fn with the root and child
+// object of every row. childObj is nil for a root without children.
+func (o ORM) eachJoinedRow(ctx context.Context, objTable *schema.Table, queryObj *object.Object, childName string, fn func(rootObj, childObj *object.Object) error) error {
+	sg := o.sqlGen
+	sqlStr, joinColumns, bindArgs, err := sg.BindingRetrieveJoined(sg, o.s, queryObj, childName)
+
+	if sg.Tracing {
+		fmt.Println("RetrieveJoined/sqlStr=", sqlStr, "joinColumns=", joinColumns, "bindArgs=", bindArgs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Each table's columns are keyed by their aliases, as in every other
+	// retrieval
+	columnNames := make([]string, len(joinColumns))
+	keys := make([]string, len(joinColumns))
+	for i, jc := range joinColumns {
+		columnNames[i] = jc.Alias
+		keys[i] = aliasColumnNames(o.s.GetTable(jc.Table), []string{jc.Column})[0]
+	}
+
+	var childTable *schema.Table
+	if childName != "" {
+		childTable = o.s.GetTable(childName)
+	}
+
+	return o.eachObject(ctx, nil, objTable.Name, sqlStr, columnNames, bindArgs, func(row *object.Object) error {
+		// Split the flat row into the root and the child object
+		rootObj := object.New(objTable.Name)
+		rootObj.MarkDirty(false)
+		var childObj *object.Object
+		if childTable != nil {
+			childObj = object.New(childName)
+			childObj.MarkDirty(false)
+		}
+		for i, jc := range joinColumns {
+			v, ok := row.KV[jc.Alias]
+			if !ok {
+				continue
+			}
+			if jc.Child {
+				childObj.KV[keys[i]] = v
+			} else {
+				rootObj.KV[keys[i]] = v
+			}
+		}
+
+		convertValues(objTable, rootObj)
+		if err := o.decodeValues(objTable, rootObj); err != nil {
+			return err
+		}
+		// Columns of an unmatched LEFT JOIN are all NULL
+		if childObj != nil && !hasJoinedKey(childTable, childObj) {
+			childObj = nil
+		}
+		if childObj != nil {
+			convertValues(childTable, childObj)
+			if err := o.decodeValues(childTable, childObj); err != nil {
+				return err
+			}
+		}
+		return fn(rootObj, childObj)
+	})
+}
+
+// joinedKey renders the primary key of obj, used to group joined rows.
+func joinedKey(tbl *schema.Table, obj *object.Object) string {
+	cols := tbl.PrimaryKeyColumns()
+	vals := make([]string, len(cols))
+	for i, c := range cols {
+		vals[i] = fmt.Sprint(obj.Get(c))
+	}
+	return strings.Join(vals, "\x00")
+}
+
+// hasJoinedKey reports whether obj has a non-NULL value for every primary key
+// column of tbl.
+func hasJoinedKey(tbl *schema.Table, obj *object.Object) bool {
+	for _, c := range tbl.PrimaryKeyColumns() {
+		if v := obj.Get(c); v == nil || obj.ValueIsNULL(v) {
+			return false
+		}
+	}
+	return true
+}
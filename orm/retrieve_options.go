@@ -0,0 +1,72 @@
+package orm
+
+import (
+	"github.com/rbastic/dyndao/object"
+	sg "github.com/rbastic/dyndao/sqlgen"
+)
+
+// RetrieveOption changes what Retrieve and RetrieveMany select.
+type RetrieveOption func(*retrieveOptions)
+
+type retrieveOptions struct {
+	allColumns bool
+	nulls      NullSemantics
+}
+
+// WithAllColumns makes Retrieve and RetrieveMany select every column of the
+// table, rather than only its EssentialColumns.
+func WithAllColumns() RetrieveOption {
+	return func(ro *retrieveOptions) {
+		ro.allColumns = true
+	}
+}
+
+// NullSemantics says what a nil or NULL SQLValue query value matches.
+type NullSemantics int
+
+const (
+	// NullMatchesNull, the default, matches the rows where the column is
+	// NULL, by rendering IS NULL.
+	NullMatchesNull NullSemantics = iota
+	// NullStandard compares the column with NULL as the SQL standard does:
+	// col = NULL is unknown, so no row matches.
+	NullStandard
+)
+
+// WithNullSemantics makes Retrieve and RetrieveMany treat nil and NULL query
+// values, including invalid sql.Null* ones, as described by ns. Without it, they use NullMatchesNull.
+func WithNullSemantics(ns NullSemantics) RetrieveOption {
+	return func(ro *retrieveOptions) {
+		ro.nulls = ns
+	}
+}
+
+// applyRetrieveOptions returns the columns to select from table for opts, or
+// nil for the table's DefaultColumns, and the query values to use.
+func (o ORM) applyRetrieveOptions(table string, queryVals map[string]interface{}, opts []RetrieveOption) ([]string, map[string]interface{}) {
+	var ro retrieveOptions
+	for _, opt := range opts {
+		opt(&ro)
+	}
+
+	if ro.nulls == NullStandard {
+		vals := make(map[string]interface{}, len(queryVals))
+		for k, v := range queryVals {
+			if object.IsNULL(v) {
+				v = sg.BoundNULL{}
+			}
+			vals[k] = v
+		}
+		queryVals = vals
+	}
+
+	if !ro.allColumns {
+		return nil, queryVals
+	}
+	objTable := o.s.GetTable(table)
+	if objTable == nil {
+		// Left for renderRetrieve to report
+		return nil, queryVals
+	}
+	return objTable.ColumnNames(), queryVals
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"github.com/rbastic/dyndao/object"
@@ -30,46 +31,41 @@ func pkQueryValsFromKV(obj *object.Object, sch *schema.Schema, parentTableName s
 	return qv, nil
 }
 
+// recurseAndSave saves obj and every object nested under it, in the order
+// given by saveOrder, setting the keys of each object from the objects saved
+// before it that it is related to.
 func (o ORM) recurseAndSave(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
-	rowsAff, err := o.Save(ctx, tx, obj)
+	order, err := o.saveOrder(obj)
 	if err != nil {
 		return 0, err
 	}
 
-	table := o.s.GetTable(obj.Type)
-	pkVal := obj.Get(table.Primary)
-
-	for _, v := range obj.Children {
-		for _, childObj := range v {
-			// set the primary key in the child object, if it exists in the child object's table
-			childTable, ok := o.s.Tables[childObj.Type]
-			if !ok {
-				return 0, fmt.Errorf("recurseAndSave: Unknown child object type %s for parent type %s", childObj.Type, obj.Type)
-			}
-			// TODO: support propagation of additional primary keys
-			// that are saved from previous recursive saves ...
-			// check if the child schema table contains the
-			// parent's primary key field as a name
-			_, ok = childTable.Columns[table.Primary]
-			if ok {
-				// set in the child object if the table contains the primary
-				childObj.Set(table.Primary, pkVal)
+	saved := make(map[*object.Object]bool, len(order))
+	var rowsAff int64
+	for _, node := range order {
+		for _, n := range node.neighbors {
+			if !saved[n] {
+				continue
 			}
-
-			// TODO: Likely we can just use pkQueryValsFromKV here,
-			// but I would like to add some test cases and verify
-			// the functionality under different scenarios.
-			aff, err := o.recurseAndSave(ctx, tx, childObj)
-			if err != nil {
-				return rowsAff + aff, err
+			if err := o.setSavedKeys(n, node.obj); err != nil {
+				return rowsAff, err
 			}
 		}
+		aff, err := o.Save(ctx, tx, node.obj)
+		rowsAff += aff
+		if err != nil {
+			return rowsAff, err
+		}
+		saved[node.obj] = true
 	}
-	return rowsAff, err
+	return rowsAff, nil
 }
 
 // SaveAllInsideTx will attempt to save an entire nested object structure inside of a single transaction.
 func (o ORM) SaveAllInsideTx(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
 		return 0, ctx.Err()
@@ -89,7 +85,17 @@ func (o ORM) SaveAllInsideTx(ctx context.Context, tx *sql.Tx, obj *object.Object
 // SaveAll will attempt to save an entire nested object structure inside of a single transaction.
 // It begins the transaction, attempts to recursively save the object and all of it's children,
 // and any of the children's children, and then will finally rollback/commit as necessary.
+//
+// The objects are saved table by table, in a topological order of the
+// schema's parent/child relationships, so that each object is saved after
+// the objects it references and receives their keys, however deeply they are
+// nested. A child shared by two parents, as in a diamond-shaped schema,
+// should appear under both, as the same object, to receive both keys. A
+// cycle between the tables is reported as an error before anything is saved.
 func (o ORM) SaveAll(ctx context.Context, obj *object.Object) (int64, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
 		return 0, ctx.Err()
@@ -102,19 +108,13 @@ func (o ORM) SaveAll(ctx context.Context, obj *object.Object) (int64, error) {
 	}
 	rowsAff, err := o.SaveAllInsideTx(ctx, tx, obj)
 	if err != nil {
-		rollErr := tx.Rollback()
-		if rollErr != nil {
-			return 0, errors.Wrap(err, rollErr.Error())
-		}
+		// SaveAllInsideTx has already rolled back
 		return 0, err
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		rollErr := tx.Rollback()
-		if rollErr != nil {
-			return 0, errors.Wrap(err, rollErr.Error())
-		}
+		// A failed commit leaves nothing to roll back
 		return 0, err
 	}
 	return rowsAff, nil
@@ -124,6 +124,9 @@ func (o ORM) SaveAll(ctx context.Context, obj *object.Object) (int64, error) {
 // save any of the children. If given a transaction, it will use that to
 // attempt to insert the data.
 func (o ORM) SaveButErrorIfUpdate(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
 		return 0, ctx.Err()
@@ -135,7 +138,7 @@ func (o ORM) SaveButErrorIfUpdate(ctx context.Context, tx *sql.Tx, obj *object.O
 		return 0, errors.New("SaveButErrorIfUpdate: unknown object table " + obj.Type)
 	}
 	// skip if object is clean
-	if !obj.IsDirty() {
+	if !o.NeedsSave(obj) {
 		return 0, nil
 	}
 	// retrieve primary key value
@@ -162,6 +165,9 @@ func (o ORM) SaveButErrorIfUpdate(ctx context.Context, tx *sql.Tx, obj *object.O
 // situations where an INSERT would compromise the integrity of the data.  If
 // given a transaction, it will use that to attempt to insert the data.
 func (o ORM) SaveButErrorIfInsert(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
 		return 0, ctx.Err()
@@ -173,7 +179,7 @@ func (o ORM) SaveButErrorIfInsert(ctx context.Context, tx *sql.Tx, obj *object.O
 		return 0, errors.New("SaveButErrorIfInsert: unknown object table " + obj.Type)
 	}
 	// skip objects that are saved
-	if !obj.IsDirty() {
+	if !o.NeedsSave(obj) {
 		return 0, nil
 	}
 	// ensure we have a primary key
@@ -195,51 +201,133 @@ func (o ORM) SaveButErrorIfInsert(ctx context.Context, tx *sql.Tx, obj *object.O
 	return o.Update(ctx, tx, obj)
 }
 
+// NeedsSave reports whether Save would issue any SQL for obj. Objects which
+// are not dirty, such as freshly retrieved or already saved objects, are
+// skipped.
+func (o ORM) NeedsSave(obj *object.Object) bool {
+	return obj.IsDirty()
+}
+
+// SaveAction says what Save did with an object.
+type SaveAction string
+
+const (
+	// Inserted means a new row was INSERTed for the object.
+	Inserted SaveAction = "inserted"
+	// Updated means the object's row was UPDATEd.
+	Updated SaveAction = "updated"
+	// Unchanged means the object did not need saving, and no SQL was run.
+	Unchanged SaveAction = "unchanged"
+)
+
+// SaveOutcome describes the outcome of a Save.
+type SaveOutcome struct {
+	Action       SaveAction
+	RowsAffected int64
+	// Changed holds the fields the save wrote, with their values, captured
+	// before the object's ChangedFields were reset, for audit trails and
+	// retries: every field for an insert, and the ChangedFields for an
+	// update (every field when there are none, since Update then writes the
+	// whole row). It is nil when Action is Unchanged.
+	Changed map[string]interface{}
+}
+
 // Save function will INSERT or UPDATE a record. It does not attempt to
 // save any of the children. If given a transaction, it will use that to
-// attempt to insert the data.
+// attempt to insert the data. Save returns (0, nil) without issuing any SQL
+// when NeedsSave is false.
 func (o ORM) Save(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+	res, err := o.SaveResult(ctx, tx, obj)
+	return res.RowsAffected, err
+}
+
+// SaveResult is Save, but also reports whether the object was inserted,
+// updated or left unchanged, for callers that emit change events. On error,
+// Action is still the statement that was attempted.
+func (o ORM) SaveResult(ctx context.Context, tx *sql.Tx, obj *object.Object) (res SaveOutcome, err error) {
+	ctx, span := o.startSpan(ctx, "Save", obj.Type)
+	defer endSpan(span, &err)
+	return o.save(ctx, tx, obj)
+}
+
+func (o ORM) save(ctx context.Context, tx *sql.Tx, obj *object.Object) (SaveOutcome, error) {
+	res := SaveOutcome{Action: Unchanged}
 	select {
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return res, ctx.Err()
 	default:
 	}
 	objTable := o.s.GetTable(obj.Type)
 	// skip if object has invalid type
 	if objTable == nil {
-		return 0, errors.New("Save: unknown object table " + obj.Type)
+		return res, errors.New("Save: unknown object table " + obj.Type)
 	}
 	// skip if object is saved
-	if !obj.IsDirty() {
-		return 0, nil
+	if !o.NeedsSave(obj) {
+		return res, nil
+	}
+	if o.ValidateBeforeSave {
+		err := o.Validate(obj)
+		if err != nil {
+			return res, err
+		}
 	}
 	// retrieve primary key value
 	pk := objTable.Primary
 	if pk == "" {
-		return 0, errors.New("Save: empty primary key for " + obj.Type)
+		return res, errors.New("Save: empty primary key for " + obj.Type)
 	}
 	// skip if primary key has no field configuration in table schema
 	fieldMap := objTable.Columns
 	f := fieldMap[pk]
 	if f == nil {
-		return 0, errors.New("Save: empty field " + pk + " for " + obj.Type)
+		return res, errors.New("Save: empty field " + pk + " for " + obj.Type)
 	}
 	// Check the primary key to see if we should insert or update
+	var err error
 	_, ok := obj.KV[f.Name]
+	res.Changed = obj.ChangedFields()
+	if !ok || len(res.Changed) == 0 {
+		for k, v := range obj.KV {
+			res.Changed[k] = v
+		}
+	}
 	if !ok {
-		return o.Insert(ctx, tx, obj)
+		res.Action = Inserted
+		res.RowsAffected, err = o.Insert(ctx, tx, obj)
+	} else {
+		res.Action = Updated
+		res.RowsAffected, err = o.Update(ctx, tx, obj)
 	}
-	return o.Update(ctx, tx, obj)
+	return res, err
 }
 
 // use transaction if needed, otherwise just execute a non-transactionalized operation
 func stmtFromDbOrTx(ctx context.Context, o ORM, tx *sql.Tx, sqlStr string) (*sql.Stmt, error) {
+	return prepareStmt(ctx, o, o.RawConn, tx, sqlStr)
+}
+
+// stmtFromReaderOrTx is stmtFromDbOrTx for statements which only read, which
+// are sent to one of the ORM's readers when they are not part of a
+// transaction.
+func stmtFromReaderOrTx(ctx context.Context, o ORM, tx *sql.Tx, sqlStr string) (*sql.Stmt, error) {
+	if tx != nil {
+		return prepareStmt(ctx, o, nil, tx, sqlStr)
+	}
+	return prepareStmt(ctx, o, o.readerDB(), nil, sqlStr)
+}
+
+func prepareStmt(ctx context.Context, o ORM, db *sql.DB, tx *sql.Tx, sqlStr string) (*sql.Stmt, error) {
 	var stmt *sql.Stmt
 	var err error
+	if o.prepared != nil {
+		atomic.AddInt64(o.prepared, 1)
+	}
+	o.traceSQL(ctx, sqlStr)
 	if tx != nil {
 		stmt, err = tx.PrepareContext(ctx, sqlStr)
 	} else {
-		stmt, err = o.RawConn.PrepareContext(ctx, sqlStr)
+		stmt, err = db.PrepareContext(ctx, sqlStr)
 	}
 	return stmt, err
 }
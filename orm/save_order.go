@@ -0,0 +1,214 @@
+package orm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rbastic/dyndao/object"
+)
+
+// saveNode is an object of the graph saved by SaveAll, along with the
+// objects it is nested under or holds as children.
+type saveNode struct {
+	obj       *object.Object
+	neighbors []*object.Object
+}
+
+// saveOrder returns the objects of the graph rooted at obj in the order
+// SaveAll saves them: ordered by a topological sort of their tables, so that
+// every table is saved after the tables it depends on, and otherwise in the
+// order they are nested. An object that appears more than once in the graph,
+// such as a child shared by two parents, is saved once. Tables depend on
+// their parents in the schema, and on the tables their objects are nested
+// under when the schema doesn't relate the two. A cycle between the tables
+// is reported as an error.
+func (o ORM) saveOrder(obj *object.Object) ([]*saveNode, error) {
+	nodes := make(map[*object.Object]*saveNode)
+	var order []*saveNode
+	deps := make(map[string]map[string]bool) // table -> the tables saved before it
+
+	addDep := func(table, dep string) {
+		if deps[table] == nil {
+			deps[table] = make(map[string]bool)
+		}
+		if table != dep {
+			deps[table][dep] = true
+		}
+	}
+
+	var walk func(obj *object.Object) error
+	walk = func(obj *object.Object) error {
+		if nodes[obj] != nil {
+			return nil
+		}
+		objTable := o.s.GetTableName(obj.Type)
+		if o.s.GetTable(objTable) == nil {
+			return fmt.Errorf("SaveAll: unknown object table %s", obj.Type)
+		}
+		node := &saveNode{obj: obj}
+		nodes[obj] = node
+		order = append(order, node)
+		addDep(objTable, objTable)
+
+		names := make([]string, 0, len(obj.Children))
+		for name := range obj.Children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, childObj := range obj.Children[name] {
+				if err := walk(childObj); err != nil {
+					return err
+				}
+				child := nodes[childObj]
+				node.neighbors = append(node.neighbors, childObj)
+				child.neighbors = append(child.neighbors, obj)
+
+				childTable := o.s.GetTableName(childObj.Type)
+				if !containsString(o.s.ParentTableNames(objTable), childTable) {
+					addDep(childTable, objTable)
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(obj); err != nil {
+		return nil, err
+	}
+
+	for table := range deps {
+		for _, parent := range o.s.ParentTableNames(table) {
+			if _, ok := deps[parent]; ok {
+				addDep(table, parent)
+			}
+		}
+	}
+	rank, err := tableSaveRanks(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return rank[o.s.GetTableName(order[i].obj.Type)] < rank[o.s.GetTableName(order[j].obj.Type)]
+	})
+	return order, nil
+}
+
+// AffectedTables returns the schema tables SaveAll writes to when saving obj
+// and the objects nested under it, each once, in the order it saves them.
+// No SQL is run. Every object counts, including the clean ones SaveAll
+// skips, so that the tables are the same before and after the save. The
+// error is the one SaveAll would report for the graph, such as a cycle
+// between its tables.
+func (o ORM) AffectedTables(obj *object.Object) ([]string, error) {
+	order, err := o.saveOrder(obj)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, node := range order {
+		table := o.s.GetTableName(node.obj.Type)
+		if !containsString(tables, table) {
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
+// tableSaveRanks numbers the tables of deps so that every table comes after
+// the tables it depends on, or reports a cycle between them.
+func tableSaveRanks(deps map[string]map[string]bool) (map[string]int, error) {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	rank := make(map[string]int, len(names))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, p := range path {
+				if p == name {
+					path = append(path[i:], name)
+					break
+				}
+			}
+			return fmt.Errorf("SaveAll: cyclic dependency between tables %s", strings.Join(path, " -> "))
+		}
+		state[name] = visiting
+		path = append(path, name)
+		parents := make([]string, 0, len(deps[name]))
+		for parent := range deps[name] {
+			parents = append(parents, parent)
+		}
+		sort.Strings(parents)
+		for _, parent := range parents {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		rank[name] = len(rank)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return rank, nil
+}
+
+// setSavedKeys copies the keys of saved, which has just been saved, into
+// obj, which is related to it in the graph and saved after it. The columns
+// are those of the relationship when the schema names them, and otherwise
+// saved's primary key, if obj's table has a column of that name.
+func (o ORM) setSavedKeys(saved, obj *object.Object) error {
+	savedTable := o.s.GetTable(saved.Type)
+	objTable := o.s.GetTable(obj.Type)
+
+	if ct := savedTable.Children[o.s.GetTableName(obj.Type)]; ct != nil {
+		local, foreign, err := ct.KeyColumns()
+		if err != nil {
+			return err
+		}
+		if len(local) > 0 {
+			for i := range local {
+				obj.Set(local[i], saved.Get(foreign[i]))
+			}
+			return nil
+		}
+	}
+	if savedTable == objTable {
+		// Its own primary key would overwrite obj's
+		return nil
+	}
+	if _, ok := objTable.Columns[savedTable.Primary]; ok {
+		obj.Set(savedTable.Primary, saved.Get(savedTable.Primary))
+	}
+	return nil
+}
+
+// containsString reports whether names holds name.
+func containsString(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rbastic/dyndao/object"
+)
+
+// ErrParentNotSaved is reported by SaveAllPartial for the objects that would
+// have received the keys of an object that failed to save, such as its
+// children, which are not attempted.
+var ErrParentNotSaved = errors.New("dyndao: parent object was not saved")
+
+// ChildSaveResult is the outcome of saving a single object, other than the
+// one given, in SaveAllPartial. Err is nil if the object was saved.
+type ChildSaveResult struct {
+	Object *object.Object
+	Err    error
+}
+
+// SaveAllResult reports what SaveAllPartial saved. Children holds one entry
+// for every other object of the saved graph, in the order they were
+// attempted.
+type SaveAllResult struct {
+	RowsAffected int64
+	Children     []ChildSaveResult
+}
+
+// Failed returns the entries of Children that were not saved.
+func (r *SaveAllResult) Failed() []ChildSaveResult {
+	var failed []ChildSaveResult
+	for _, c := range r.Children {
+		if c.Err != nil {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// SaveAllPartial is SaveAll for imports that should tolerate bad children.
+// The objects are saved in the order SaveAll saves them, receiving the keys
+// of the objects saved before them in the same way. obj itself must save, or
+// nothing is saved and the error is returned. Each of the other objects is
+// saved under its own SAVEPOINT: one that fails is rolled back on its own
+// and reported in the result, and the objects related to it that would have
+// received its keys, such as its children, are skipped with
+// ErrParentNotSaved, while everything else is committed. An error is only
+// returned for obj, for the savepoint statements themselves and for the
+// commit, in which case nothing is saved.
+func (o ORM) SaveAllPartial(ctx context.Context, obj *object.Object) (*SaveAllResult, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	order, err := o.saveOrder(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := o.RawConn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &SaveAllResult{}
+	if err := o.saveAllPartial(ctx, tx, obj, order, res); err != nil {
+		return nil, rollback(tx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "SaveAllPartial: commit")
+	}
+	return res, nil
+}
+
+// saveAllPartial saves the objects of order, the save order of the graph
+// rooted at root, for SaveAllPartial.
+func (o ORM) saveAllPartial(ctx context.Context, tx *sql.Tx, root *object.Object, order []*saveNode, res *SaveAllResult) error {
+	saved := make(map[*object.Object]bool, len(order))
+	failed := make(map[*object.Object]error)
+	for _, node := range order {
+		var depErr error
+		for _, n := range node.neighbors {
+			if err, ok := failed[n]; ok {
+				depErr = err
+				break
+			}
+		}
+		if depErr != nil {
+			if node.obj == root {
+				return errors.Wrap(depErr, "SaveAllPartial: an object "+root.Type+" depends on was not saved")
+			}
+			failed[node.obj] = ErrParentNotSaved
+			res.Children = append(res.Children, ChildSaveResult{Object: node.obj, Err: ErrParentNotSaved})
+			continue
+		}
+
+		for _, n := range node.neighbors {
+			if !saved[n] {
+				continue
+			}
+			if err := o.setSavedKeys(n, node.obj); err != nil {
+				return err
+			}
+		}
+
+		if node.obj == root {
+			aff, err := o.Save(ctx, tx, root)
+			if err != nil {
+				return err
+			}
+			res.RowsAffected += aff
+			saved[root] = true
+			continue
+		}
+
+		savepoint := fmt.Sprintf("dyndao_sp%d", len(res.Children))
+		if err := o.execSavepoint(ctx, tx, o.sqlGen.Savepoint(savepoint)); err != nil {
+			return err
+		}
+		aff, err := o.Save(ctx, tx, node.obj)
+		if err != nil {
+			if spErr := o.execSavepoint(ctx, tx, o.sqlGen.RollbackToSavepoint(savepoint)); spErr != nil {
+				return spErr
+			}
+			failed[node.obj] = err
+			res.Children = append(res.Children, ChildSaveResult{Object: node.obj, Err: err})
+			continue
+		}
+		if err := o.execSavepoint(ctx, tx, o.sqlGen.ReleaseSavepoint(savepoint)); err != nil {
+			return err
+		}
+		res.RowsAffected += aff
+		saved[node.obj] = true
+		res.Children = append(res.Children, ChildSaveResult{Object: node.obj})
+	}
+	return nil
+}
+
+// execSavepoint runs a savepoint statement, doing nothing for the empty
+// statements some dialects render.
+func (o ORM) execSavepoint(ctx context.Context, tx *sql.Tx, sqlStr string) error {
+	if sqlStr == "" {
+		return nil
+	}
+	o.traceSQL(ctx, sqlStr)
+	if o.sqlGen.Tracing {
+		fmt.Println("SaveAllPartial/sqlStr=", sqlStr)
+	}
+	if _, err := tx.ExecContext(ctx, sqlStr); err != nil {
+		return errors.Wrap(err, "SaveAllPartial/"+sqlStr)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"io"
 	"os"
 	// TODO: Use log15 instead of fmt?
 	"fmt"
@@ -9,12 +10,21 @@ import (
 	"database/sql"
 	"github.com/pkg/errors"
 	"github.com/rbastic/dyndao/schema"
+	"strings"
 )
 
 // CreateTables executes a CreateTable operation for every table specified in
-// the schema.
+// the schema. Parent tables are created before their children so that any
+// FOREIGN KEY constraints can reference them.
 func (o ORM) CreateTables(ctx context.Context) error {
-	for tName := range o.s.Tables {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	tableNames, err := o.s.TableCreationOrder()
+	if err != nil {
+		return errors.Wrap(err, "CreateTables")
+	}
+	for _, tName := range tableNames {
 		err := o.CreateTable(ctx, o.s, tName)
 		if err != nil {
 			return err
@@ -24,22 +34,132 @@ func (o ORM) CreateTables(ctx context.Context) error {
 	return nil
 }
 
-// DropTables executes a DropTable operation for every table specified in the
-// schema.
-func (o ORM) DropTables(ctx context.Context) error {
-	for tName := range o.s.Tables {
-		err := o.DropTable(ctx, tName)
+// ExportDDL writes the statements CreateTables would run to w, each ended by
+// a semicolon, for review or for running by hand. See RenderSchemaDDL.
+func (o ORM) ExportDDL(w io.Writer) error {
+	stmts, err := o.sqlGen.RenderSchemaDDL(o.s)
+	if err != nil {
+		return errors.Wrap(err, "ExportDDL")
+	}
+	for _, sqlStr := range stmts {
+		if _, err := fmt.Fprintf(w, "%s;\n\n", sqlStr); err != nil {
+			return errors.Wrap(err, "ExportDDL")
+		}
+	}
+	return nil
+}
+
+// CreateTablesIfNotExists is CreateTables for idempotent bootstrapping: tables
+// that already exist, according to TableExists, are left alone (along with
+// their indexes), and only the missing ones are created.
+func (o ORM) CreateTablesIfNotExists(ctx context.Context) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	tableNames, err := o.s.TableCreationOrder()
+	if err != nil {
+		return errors.Wrap(err, "CreateTablesIfNotExists")
+	}
+	for _, tName := range tableNames {
+		exists, err := o.TableExists(ctx, tName)
 		if err != nil {
+			return errors.Wrap(err, "CreateTablesIfNotExists")
+		}
+		if exists {
+			continue
+		}
+		if err := o.CreateTable(ctx, o.s, tName); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// TableExists reports whether the schema table tableName exists in the
+// database. It is looked up by its unqualified name, amongst the tables
+// visible to the connection, whatever its SchemaName.
+func (o ORM) TableExists(ctx context.Context, tableName string) (bool, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	name := tableName
+	if tbl := o.s.GetTable(tableName); tbl != nil {
+		name = o.s.RealTableName(tbl.Name, tableName)
+	}
+	sqlStr, bindArgs := o.sqlGen.BindingTableExists(o.sqlGen, name)
+
+	stmt, err := o.RawConn.PrepareContext(ctx, sqlStr)
+	if err != nil {
+		return false, errors.Wrap(err, "TableExists/PrepareContext ("+sqlStr+")")
+	}
+	defer func() {
+		stmtErr := stmt.Close()
+		if stmtErr != nil {
+			fmt.Println(stmtErr) // TODO: logging implementation
+		}
+	}()
+
+	var one interface{}
+	err = stmt.QueryRowContext(ctx, bindArgs...).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "TableExists")
+	}
+	return true, nil
+}
 
+// DropTables executes a DropTable operation for every table specified in the
+// schema. Child tables are dropped before their parents so that FOREIGN KEY
+// constraints never refer to a dropped table.
+func (o ORM) DropTables(ctx context.Context) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	return o.dropTables(ctx, false)
+}
+
+// DropTablesIfExists is DropTables for idempotent teardown: tables that don't
+// exist, according to TableExists, are skipped.
+func (o ORM) DropTablesIfExists(ctx context.Context) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	return o.dropTables(ctx, true)
+}
+
+func (o ORM) dropTables(ctx context.Context, ifExists bool) error {
+	tableNames, err := o.s.TableDropOrder()
+	if err != nil {
+		return errors.Wrap(err, "DropTables")
+	}
+	for _, tName := range tableNames {
+		if ifExists {
+			exists, err := o.TableExists(ctx, tName)
+			if err != nil {
+				return errors.Wrap(err, "DropTablesIfExists")
+			}
+			if !exists {
+				continue
+			}
+		}
+		if err := o.DropTable(ctx, tName); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // CreateTable will execute a CreateTable operation for the specified table in
-// a given schema.
+// a given schema, followed by a CreateIndex operation for each of the
+// table's Indexes. It fails if the schema has no such table. Unlike
+// CreateTables, it creates a single table, such as one a migration adds to
+// the schema.
 func (o ORM) CreateTable(ctx context.Context, sch *schema.Schema, tableName string) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
 	sqlStr, err := o.sqlGen.CreateTable(o.sqlGen, sch, tableName)
 	if err != nil {
 		return err
@@ -55,13 +175,109 @@ func (o ORM) CreateTable(ctx context.Context, sch *schema.Schema, tableName stri
 	if err != nil {
 		return errors.Wrap(err, "CreateTable")
 	}
+
+	tbl := sch.GetTable(tableName)
+	for _, idx := range tbl.Indexes {
+		sqlStr, err := o.sqlGen.CreateIndex(o.sqlGen, sch, tableName, idx)
+		if err != nil {
+			return err
+		}
+		_, err = prepareAndExecSQL(ctx, o.RawConn, sqlStr)
+		if err != nil {
+			return errors.Wrap(err, "CreateTable/CreateIndex")
+		}
+	}
 	return nil
 }
 
+// AddColumn adds the column col to the schema table tableName, with an
+// ALTER TABLE statement, and then to the ORM's schema, so that later
+// operations write and retrieve it; it is added to the EssentialColumns too,
+// if the table has them. The table must not already have a column
+// of that name. A NOT NULL column needs a DefaultValue on most databases
+// unless the table is empty. The schema is changed in place, so AddColumn
+// should not run alongside other operations on the table.
+func (o ORM) AddColumn(ctx context.Context, tableName string, col *schema.Column) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	tbl := o.s.GetTable(tableName)
+	if tbl == nil {
+		return errors.New("AddColumn: unknown object table " + tableName)
+	}
+	if col == nil || col.Name == "" {
+		return errors.New("AddColumn: no column name given for table " + tableName)
+	}
+	if tbl.GetColumn(col.Name) != nil {
+		return errors.New("AddColumn: table " + tableName + " already has a column " + col.Name)
+	}
+
+	sqlStr, err := o.sqlGen.AddColumn(o.sqlGen, o.s, tableName, col)
+	if err != nil {
+		return err
+	}
+	if _, err := prepareAndExecSQL(ctx, o.RawConn, sqlStr); err != nil {
+		return errors.Wrap(err, "AddColumn")
+	}
+
+	if tbl.Columns == nil {
+		tbl.Columns = make(map[string]*schema.Column)
+	}
+	tbl.Columns[col.Name] = col
+	if len(tbl.EssentialColumns) > 0 {
+		tbl.EssentialColumns = append(tbl.EssentialColumns, col.Name)
+	}
+	return nil
+}
+
+// DropColumn drops the column colName, which may be an alias, from the
+// schema table tableName, with an ALTER TABLE statement, and then removes it
+// from the ORM's schema, see Table.RemoveColumn. Primary key columns, and
+// columns the schema still refers to, see Schema.ColumnReferences, can't be
+// dropped. As with AddColumn, the schema is changed in place.
+func (o ORM) DropColumn(ctx context.Context, tableName string, colName string) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	tbl := o.s.GetTable(tableName)
+	if tbl == nil {
+		return errors.New("DropColumn: unknown object table " + tableName)
+	}
+	f := tbl.GetColumn(colName)
+	if f == nil {
+		return errors.New("DropColumn: unknown column " + colName + " in table " + tableName)
+	}
+	for _, pk := range tbl.PrimaryKeyColumns() {
+		if tbl.GetColumnName(pk) == f.Name {
+			return errors.New("DropColumn: cannot drop primary key column " + colName + " of " + tableName)
+		}
+	}
+	if refs := o.s.ColumnReferences(tableName, colName); len(refs) > 0 {
+		return errors.New("DropColumn: cannot drop column " + colName + " of " + tableName + ", it is " + strings.Join(refs, ", "))
+	}
+
+	sqlStr, err := o.sqlGen.DropColumn(o.sqlGen, o.s, tableName, colName)
+	if err != nil {
+		return err
+	}
+	if _, err := prepareAndExecSQL(ctx, o.RawConn, sqlStr); err != nil {
+		return errors.Wrap(err, "DropColumn")
+	}
+
+	return tbl.RemoveColumn(colName)
+}
+
 // DropTable will execute a DropTable operation for the specified table in
 // a given schema.
 func (o ORM) DropTable(ctx context.Context, tableName string) error {
-	sqlStr := o.sqlGen.DropTable(tableName)
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	name := tableName
+	if tbl := o.s.GetTable(tableName); tbl != nil {
+		name = o.s.QualifiedTableName(tbl.Name, tableName, o.sqlGen.QuoteIdentifier)
+	}
+	sqlStr := o.sqlGen.DropTable(name)
 	_, err := prepareAndExecSQL(ctx, o.RawConn, sqlStr)
 	if err != nil {
 		return errors.Wrap(err, "DropTable")
@@ -69,6 +285,43 @@ func (o ORM) DropTable(ctx context.Context, tableName string) error {
 	return nil
 }
 
+// Truncate deletes every row of the schema table tableName at once, which is
+// much faster than deleting them one at a time. It renders TRUNCATE TABLE, or
+// DELETE FROM on databases without it. Many databases commit an open
+// transaction when truncating, so Truncate never runs inside one.
+func (o ORM) Truncate(ctx context.Context, tableName string) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	return o.truncate(ctx, tableName, false)
+}
+
+// TruncateCascade is Truncate for a table referenced by foreign keys, also
+// truncating the tables that refer to it. It returns an error on databases
+// that cannot cascade a truncate.
+func (o ORM) TruncateCascade(ctx context.Context, tableName string) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	return o.truncate(ctx, tableName, true)
+}
+
+func (o ORM) truncate(ctx context.Context, tableName string, cascade bool) error {
+	tbl := o.s.GetTable(tableName)
+	if tbl == nil {
+		return errors.New("Truncate: unknown table " + tableName)
+	}
+	sqlStr, err := o.sqlGen.Truncate(o.s.QualifiedTableName(tbl.Name, tableName, o.sqlGen.QuoteIdentifier), cascade)
+	if err != nil {
+		return errors.Wrap(err, "Truncate")
+	}
+	_, err = prepareAndExecSQL(ctx, o.RawConn, sqlStr)
+	if err != nil {
+		return errors.Wrap(err, "Truncate")
+	}
+	return nil
+}
+
 func prepareAndExecSQL(ctx context.Context, db *sql.DB, sqlStr string) (sql.Result, error) {
 	stmt, err := db.PrepareContext(ctx, sqlStr)
 	if err != nil {
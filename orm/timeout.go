@@ -0,0 +1,57 @@
+package orm
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout returns a copy of the ORM whose operations are bounded by d,
+// for the odd call that needs longer, or shorter, than the ORM's Timeout:
+//
+//	objs, err := o.WithTimeout(10*time.Minute).RetrieveMany(ctx, "orders", nil)
+//
+// The timeout is applied on top of the context passed to the operation, so
+// whichever deadline comes first wins: it can shorten a deadline the caller
+// set, but never extend it. It covers the whole operation, including the
+// operations it runs in turn (the Insert or Update of a Save, the statements
+// of a Transact). A d of zero removes the ORM's Timeout.
+func (o ORM) WithTimeout(d time.Duration) ORM {
+	o.Timeout = d
+	return o
+}
+
+// applyTimeout derives a context bounded by the ORM's Timeout, unless there
+// is none or the context already has a deadline that comes first, such as
+// the one an enclosing operation applied. A nested operation with a shorter
+// Timeout is thus still bounded by it.
+func (o ORM) applyTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.Timeout <= 0 {
+		return ctx, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Add(o.Timeout).Before(deadline) {
+		return ctx, nil
+	}
+	return context.WithTimeout(ctx, o.Timeout)
+}
+
+// boundContext applies the ORM's Timeout, see applyTimeout, to an operation
+// that starts no span of its own, such as one running several others. The
+// returned cancel func is never nil.
+func (o ORM) boundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := o.applyTimeout(ctx)
+	if cancel == nil {
+		return ctx, func() {}
+	}
+	return ctx, cancel
+}
+
+// timeoutSpan releases the operation's timeout when it ends.
+type timeoutSpan struct {
+	Span
+	cancel context.CancelFunc
+}
+
+func (s timeoutSpan) End(err error) {
+	s.Span.End(err)
+	s.cancel()
+}
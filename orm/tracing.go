@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Tracer starts a span around an ORM operation. op names the operation
+// ("Save", "Insert", "BulkInsert", "Update", "BulkUpdate", "Delete",
+// "Retrieve", "RetrieveEach", "RetrieveJoined", "Query", "RawQuery",
+// "RawExec" or "Transact") and table is the table it works on, empty for
+// Transact and RawExec. The returned context carries the span, and is passed
+// on to the operations run within it, so that their spans are children of
+// this one: the Insert or Update of a Save, or the operations a
+// RunInTxContext function runs with the context it is given. This mirrors the
+// OpenTelemetry API, which an implementation would usually wrap.
+type Tracer interface {
+	StartSpan(ctx context.Context, op string, table string) (context.Context, Span)
+}
+
+// Span is a single traced operation, started by a Tracer.
+type Span interface {
+	// SetSQL records a statement run within the span. It is only called when
+	// the ORM's TraceSQL is set.
+	SetSQL(sqlStr string)
+	// End finishes the span, with the error the operation returned, if any.
+	End(err error)
+}
+
+type spanKey struct{}
+
+type noopSpan struct{}
+
+func (noopSpan) SetSQL(string) {}
+func (noopSpan) End(error)     {}
+
+// startSpan starts a span for op if the ORM has a Tracer, and applies the
+// ORM's Timeout. Ending the span releases the timeout.
+func (o ORM) startSpan(ctx context.Context, op string, table string) (context.Context, Span) {
+	ctx, cancel := o.applyTimeout(ctx)
+	var span Span = noopSpan{}
+	if o.Tracer != nil {
+		ctx, span = o.Tracer.StartSpan(ctx, op, table)
+		ctx = context.WithValue(ctx, spanKey{}, span)
+	}
+	if cancel != nil {
+		span = timeoutSpan{Span: span, cancel: cancel}
+	}
+	return ctx, span
+}
+
+// traceSQL records sqlStr on the span carried by ctx, if SQL tracing is on.
+func (o ORM) traceSQL(ctx context.Context, sqlStr string) {
+	if !o.TraceSQL {
+		return
+	}
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		span.SetSQL(sqlStr)
+	}
+}
+
+// endSpan must be deferred directly, so that it can end the span for an
+// operation that panics before passing the panic on.
+func endSpan(span Span, err *error) {
+	if p := recover(); p != nil {
+		span.End(fmt.Errorf("panic: %v", p))
+		panic(p)
+	}
+	span.End(*err)
+}
@@ -10,23 +10,119 @@ import (
 	//"github.com/rbastic/dyndao/
 	"fmt"
 	"runtime/debug"
+	"time"
 )
 
 type TxFuncType func(*sql.Tx) error
 
+// RollbackError is returned by Transact, TransactRethrow and RunInTx when a
+// transaction failed and rolling it back failed too, leaving the transaction
+// in an unknown state. errors.Is and errors.As match both Err and
+// RollbackErr.
+type RollbackError struct {
+	Err         error // why the transaction was rolled back
+	RollbackErr error // why the rollback failed
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%s (rollback failed: %s)", e.Err, e.RollbackErr)
+}
+
+// Unwrap returns both errors.
+func (e *RollbackError) Unwrap() []error {
+	return []error{e.Err, e.RollbackErr}
+}
+
+// rollback rolls tx back after err, returning a RollbackError if that fails.
+func rollback(tx *sql.Tx, err error) error {
+	if rollbackErr := tx.Rollback(); rollbackErr != nil {
+		log15.Error("[Transact]", "Rollback", rollbackErr, "cause", err)
+		return &RollbackError{Err: err, RollbackErr: rollbackErr}
+	}
+	return err
+}
+
 // Transact is meant to group operations into transactions, simplify error
 // handling, and recover from any panics.  See:
 // http://stackoverflow.com/questions/16184238/database-sql-tx-detecting-commit-or-rollback
 // Please note this function has been changed from the above post to use
 // contexts
 func (o *ORM) Transact(ctx context.Context, txFunc TxFuncType, opts *sql.TxOptions) error {
+	return o.transact(ctx, opts, func(_ context.Context, tx *sql.Tx) error {
+		return txFunc(tx)
+	})
+}
+
+// retryBackoff is how long TransactWithRetry waits before its first retry,
+// waiting that much longer before each of the next ones.
+const retryBackoff = 10 * time.Millisecond
+
+// TransactWithRetry is Transact, running txFunc again in a new transaction
+// while it fails with an error the generator's IsRetryable deems transient,
+// such as a deadlock or a serialization failure, up to attempts runs in all.
+// txFunc must therefore be safe to run more than once. The error from the
+// last run is returned, or ctx's own if it is done before the next one.
+func (o *ORM) TransactWithRetry(ctx context.Context, attempts int, txFunc TxFuncType, opts *sql.TxOptions) error {
+	var err error
+	for i := 0; i == 0 || i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(i) * retryBackoff):
+			}
+		}
+		err = o.transact(ctx, opts, func(_ context.Context, tx *sql.Tx) error {
+			return txFunc(tx)
+		})
+		if err == nil || !o.sqlGen.IsRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// RunInTx runs fn in a transaction, with a TxORM bound to it, using the same
+// machinery as Transact: the transaction is committed if fn returns nil, and
+// rolled back if it returns an error or panics. fn must not Commit or Rollback
+// the TxORM itself. The error from fn, the panic, or the failure to commit is
+// returned, along with any failure to roll back.
+func (o *ORM) RunInTx(ctx context.Context, fn func(*TxORM) error) error {
+	return o.RunInTxContext(ctx, func(_ context.Context, tx *TxORM) error {
+		return fn(tx)
+	})
+}
+
+// RunInTxContext is RunInTx, passing fn the context of the transaction,
+// which carries its span and its Timeout. Operations fn runs with it are
+// traced as children of the transaction:
+//
+//	err := o.RunInTxContext(ctx, func(ctx context.Context, tx *orm.TxORM) error {
+//		_, err := tx.Save(ctx, obj)
+//		return err
+//	})
+func (o *ORM) RunInTxContext(ctx context.Context, fn func(context.Context, *TxORM) error) error {
+	return o.transact(ctx, nil, func(ctx context.Context, tx *sql.Tx) error {
+		return fn(ctx, &TxORM{o: *o, tx: tx})
+	})
+}
+
+// transact is the body of Transact. err is named so that the deferred commit
+// or rollback, and any recovered panic, decide what is returned.
+func (o *ORM) transact(ctx context.Context, opts *sql.TxOptions, txFunc func(context.Context, *sql.Tx) error) (err error) {
+	ctx, span := o.startSpan(ctx, "Transact", "")
 	tx, err := o.RawConn.BeginTx(ctx, opts)
 	if err != nil {
 		log15.Error("[Transact]", "BeginTx", err)
+		span.End(err)
 		return err
 	}
 
 	defer func() {
+		// The span ends last, with the outcome of the commit or rollback
+		defer func() {
+			span.End(err)
+		}()
 		if p := recover(); p != nil {
 			switch p := p.(type) {
 			case error:
@@ -38,43 +134,39 @@ func (o *ORM) Transact(ctx context.Context, txFunc TxFuncType, opts *sql.TxOptio
 			err = fmt.Errorf("%s [Transact/defer/panic %s]", err, debug.Stack())
 		}
 		if err != nil {
-			rollbackErr := tx.Rollback()
-
-			if rollbackErr != nil {
-				err = errors.Wrap(err, rollbackErr.Error())
-			}
+			err = rollback(tx, err)
 			return
 		}
-		err2 := tx.Commit()
-		if err2 != nil {
-			err = errors.Wrap(err, err2.Error())
+		if err2 := tx.Commit(); err2 != nil {
+			err = errors.Wrap(err2, "Transact: commit")
 		}
 	}()
 
-	err = txFunc(tx)
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	err = txFunc(ctx, tx)
+	return err
 }
 
-func (o *ORM) TransactRethrow(ctx context.Context, txFunc TxFuncType, opts *sql.TxOptions) error {
+// TransactRethrow is Transact, except that a panic in txFunc is re-raised
+// after the transaction is rolled back.
+func (o *ORM) TransactRethrow(ctx context.Context, txFunc TxFuncType, opts *sql.TxOptions) (err error) {
+	ctx, span := o.startSpan(ctx, "Transact", "")
 	tx, err := o.RawConn.BeginTx(ctx, opts)
 	if err != nil {
+		span.End(err)
 		return err
 	}
 
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
+			span.End(fmt.Errorf("panic: %v", p))
 			panic(p)
 		} else if err != nil {
-			tx.Rollback()
+			err = rollback(tx, err)
 		} else {
 			err = tx.Commit()
 		}
+		span.End(err)
 	}()
 	err = txFunc(tx)
 	return err
@@ -0,0 +1,150 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
+)
+
+// TxORM is an ORM handle bound to a single transaction. Every operation runs
+// inside the transaction, so that no part of a unit of work can accidentally
+// run outside of it. A TxORM must be finished with Commit or Rollback, after
+// which its operations return sql.ErrTxDone.
+type TxORM struct {
+	o  ORM
+	tx *sql.Tx
+}
+
+// Begin starts a transaction and returns a handle bound to it.
+func (o ORM) Begin(ctx context.Context) (*TxORM, error) {
+	return o.BeginTx(ctx, nil)
+}
+
+// BeginTx is Begin with transaction options.
+func (o ORM) BeginTx(ctx context.Context, opts *sql.TxOptions) (*TxORM, error) {
+	tx, err := o.RawConn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "Begin")
+	}
+	return &TxORM{o: o, tx: tx}, nil
+}
+
+// Tx returns the underlying transaction.
+func (t *TxORM) Tx() *sql.Tx {
+	return t.tx
+}
+
+// Commit commits the transaction.
+func (t *TxORM) Commit() error {
+	return t.tx.Commit()
+}
+
+// Rollback aborts the transaction.
+func (t *TxORM) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Save is ORM.Save inside the transaction.
+func (t *TxORM) Save(ctx context.Context, obj *object.Object) (int64, error) {
+	return t.o.Save(ctx, t.tx, obj)
+}
+
+// SaveResult is ORM.SaveResult inside the transaction.
+func (t *TxORM) SaveResult(ctx context.Context, obj *object.Object) (SaveOutcome, error) {
+	return t.o.SaveResult(ctx, t.tx, obj)
+}
+
+// SaveAll is ORM.SaveAll inside the transaction. Unlike ORM.SaveAll it does
+// not commit or roll back, that is left to the caller.
+func (t *TxORM) SaveAll(ctx context.Context, obj *object.Object) (int64, error) {
+	return t.o.recurseAndSave(ctx, t.tx, obj)
+}
+
+// Insert is ORM.Insert inside the transaction.
+func (t *TxORM) Insert(ctx context.Context, obj *object.Object) (int64, error) {
+	return t.o.Insert(ctx, t.tx, obj)
+}
+
+// BulkInsert is ORM.BulkInsert inside the transaction.
+func (t *TxORM) BulkInsert(ctx context.Context, objs object.Array) (int64, error) {
+	return t.o.BulkInsert(ctx, t.tx, objs)
+}
+
+// BulkUpdate is ORM.BulkUpdate inside the transaction.
+func (t *TxORM) BulkUpdate(ctx context.Context, objs object.Array) (int64, error) {
+	return t.o.BulkUpdate(ctx, t.tx, objs)
+}
+
+// Update is ORM.Update inside the transaction.
+func (t *TxORM) Update(ctx context.Context, obj *object.Object) (int64, error) {
+	return t.o.Update(ctx, t.tx, obj)
+}
+
+// UpdateFields is ORM.UpdateFields inside the transaction.
+func (t *TxORM) UpdateFields(ctx context.Context, obj *object.Object, fields ...string) (int64, error) {
+	return t.o.UpdateFields(ctx, t.tx, obj, fields...)
+}
+
+// Delete is ORM.Delete inside the transaction.
+func (t *TxORM) Delete(ctx context.Context, obj *object.Object) (int64, error) {
+	return t.o.Delete(ctx, t.tx, obj)
+}
+
+// DeleteMany is ORM.DeleteMany inside the transaction.
+func (t *TxORM) DeleteMany(ctx context.Context, table string, whereVals map[string]interface{}) (int64, error) {
+	return t.o.DeleteMany(ctx, t.tx, table, whereVals)
+}
+
+// DeleteManyReturning is ORM.DeleteManyReturning inside the transaction.
+func (t *TxORM) DeleteManyReturning(ctx context.Context, table string, whereVals map[string]interface{}) (object.Array, error) {
+	return t.o.DeleteManyReturning(ctx, t.tx, table, whereVals)
+}
+
+// RawQuery is ORM.RawQuery inside the transaction.
+func (t *TxORM) RawQuery(ctx context.Context, sqlStr string, args ...interface{}) (object.Array, error) {
+	return t.o.rawQuery(ctx, t.tx, "", sqlStr, args)
+}
+
+// RawExec is ORM.RawExec inside the transaction.
+func (t *TxORM) RawExec(ctx context.Context, sqlStr string, args ...interface{}) (int64, error) {
+	return t.o.RawExec(ctx, t.tx, sqlStr, args...)
+}
+
+// Retrieve is ORM.Retrieve inside the transaction.
+func (t *TxORM) Retrieve(ctx context.Context, table string, queryVals map[string]interface{}, opts ...RetrieveOption) (*object.Object, error) {
+	return t.o.RetrieveTx(ctx, t.tx, table, queryVals, opts...)
+}
+
+// Refresh is ORM.Refresh inside the transaction.
+func (t *TxORM) Refresh(ctx context.Context, obj *object.Object) error {
+	return t.o.RefreshTx(ctx, t.tx, obj)
+}
+
+// RetrieveMany is ORM.RetrieveMany inside the transaction.
+func (t *TxORM) RetrieveMany(ctx context.Context, table string, queryVals map[string]interface{}, opts ...RetrieveOption) (object.Array, error) {
+	return t.o.RetrieveManyTx(ctx, t.tx, table, queryVals, opts...)
+}
+
+// RetrieveEach is ORM.RetrieveEach inside the transaction.
+func (t *TxORM) RetrieveEach(ctx context.Context, table string, queryVals map[string]interface{}, fn func(*object.Object) error) error {
+	return t.o.RetrieveEachTx(ctx, t.tx, table, queryVals, fn)
+}
+
+// OpenCursor is ORM.OpenCursor inside the transaction.
+func (t *TxORM) OpenCursor(ctx context.Context, table string, queryVals map[string]interface{}) (*Cursor, error) {
+	return t.o.OpenCursorTx(ctx, t.tx, table, queryVals)
+}
+
+// Exists is ORM.Exists inside the transaction.
+func (t *TxORM) Exists(ctx context.Context, table string, queryVals map[string]interface{}) (bool, error) {
+	return t.o.ExistsTx(ctx, t.tx, table, queryVals)
+}
+
+// Query is ORM.Query inside the transaction.
+func (t *TxORM) Query(ctx context.Context, q *query.Query) (object.Array, error) {
+	return t.o.QueryTx(ctx, t.tx, q)
+}
@@ -11,10 +11,17 @@ import (
 )
 
 // Update function will UPDATE a record ...
-func (o ORM) Update(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
-	sg := o.sqlGen
-	tracing := sg.Tracing
+func (o ORM) Update(ctx context.Context, tx *sql.Tx, obj *object.Object) (rowsAff int64, err error) {
+	ctx, span := o.startSpan(ctx, "Update", obj.Type)
+	defer endSpan(span, &err)
+	start := o.startObserve()
+	rowsAff, err = o.update(ctx, tx, obj)
+	o.observe("Update", obj.Type, start, err)
+	return rowsAff, err
+}
 
+func (o ORM) update(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+	tracing := o.sqlGen.Tracing
 	errorString := "Update error"
 
 	select {
@@ -30,8 +37,34 @@ func (o ORM) Update(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 		}
 		return 0, err
 	}
+	objTable := o.s.GetTable(obj.Type)
+	if objTable == nil {
+		return 0, errors.New("Update: unknown object table " + obj.Type)
+	}
+	if err := o.applyLengthPolicy(objTable, obj); err != nil {
+		return 0, err
+	}
 
-	sqlStr, bindArgs, bindWhere, err := sg.BindingUpdate(sg, o.s, obj)
+	rowsAff, err := o.execUpdate(ctx, tx, obj)
+	if err != nil {
+		return 0, err
+	}
+	if err := o.finishUpdate(obj); err != nil {
+		return 0, err
+	}
+	return rowsAff, nil
+}
+
+// execUpdate runs the UPDATE for obj, without calling the hooks.
+func (o ORM) execUpdate(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64, error) {
+	sg := o.sqlGen
+	tracing := sg.Tracing
+
+	enc, err := o.encodeObject(obj)
+	if err != nil {
+		return 0, err
+	}
+	sqlStr, bindArgs, bindWhere, err := sg.BindingUpdate(sg, o.s, enc)
 	if err != nil {
 		if tracing {
 			fmt.Println("Update/sqlStr, err=", err)
@@ -67,21 +100,73 @@ func (o ORM) Update(ctx context.Context, tx *sql.Tx, obj *object.Object) (int64,
 		return 0, errors.Wrap(err, "Update")
 	}
 
-	rowsAff, err := res.RowsAffected()
-	if err != nil {
-		return 0, err
-	}
+	return res.RowsAffected()
+}
 
-	err = o.CallAfterUpdateHookIfNeeded(obj)
+// finishUpdate calls the AfterUpdate hook for an updated object and marks it
+// as saved.
+func (o ORM) finishUpdate(obj *object.Object) error {
+	err := o.CallAfterUpdateHookIfNeeded(obj)
 	if err != nil {
-		if tracing {
-			log15.Error(errorString, "BeforeAfterUpdateHookError", err)
+		if o.sqlGen.Tracing {
+			log15.Error("Update error", "BeforeAfterUpdateHookError", err)
 		}
-		return 0, err
+		return err
 	}
 
 	obj.MarkDirty(false)      // Note that the object has been recently saved
 	obj.ResetChangedColumns() // Reset the 'changed fields', if any
+	return nil
+}
+
+// UpdateFields will UPDATE only the named columns of a record, with their
+// current values in obj, whether or not they have changed. Every field must
+// exist in the schema table, must not be part of the primary key and must
+// have a value in obj. On success the object is marked clean, even if it had
+// other unsaved changes.
+func (o ORM) UpdateFields(ctx context.Context, tx *sql.Tx, obj *object.Object, fields ...string) (int64, error) {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	objTable := o.s.GetTable(obj.Type)
+	if objTable == nil {
+		return 0, errors.New("UpdateFields: unknown object table " + obj.Type)
+	}
+	if len(fields) == 0 {
+		return 0, errors.New("UpdateFields: no fields given for " + obj.Type)
+	}
 
+	pkCols := make(map[string]bool)
+	for _, pk := range objTable.PrimaryKeyColumns() {
+		pkCols[pk] = true
+	}
+	changed := make(map[string]interface{}, len(fields))
+	for _, k := range fields {
+		f := objTable.GetColumn(k)
+		if f == nil {
+			return 0, errors.New("UpdateFields: unknown field " + k + " in table " + obj.Type)
+		}
+		if f.IsIdentity || pkCols[f.Name] {
+			return 0, errors.New("UpdateFields: cannot update primary key field " + k + " of " + obj.Type)
+		}
+		if f.IsReadOnly() {
+			return 0, errors.New("UpdateFields: cannot update read-only field " + k + " of " + obj.Type)
+		}
+		v, ok := obj.KV[k]
+		if !ok {
+			return 0, errors.New("UpdateFields: no value for field " + k + " of " + obj.Type)
+		}
+		changed[k] = v
+	}
+
+	// BindingUpdate only touches the changed columns, so limit them to the
+	// requested fields for the duration of the update.
+	prevChanged := obj.ChangedColumns
+	obj.ChangedColumns = changed
+	rowsAff, err := o.Update(ctx, tx, obj)
+	if err != nil {
+		obj.ChangedColumns = prevChanged
+		return 0, err
+	}
 	return rowsAff, nil
 }
@@ -0,0 +1,170 @@
+package orm
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// ValidationErrors is returned by Validate, listing every problem found with
+// an object rather than just the first one.
+type ValidationErrors []error
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, err := range v {
+		msgs[i] = err.Error()
+	}
+	return "dyndao: object failed validation: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks an object against the schema metadata for its table before
+// it is sent to the database. It flags unknown fields, NOT NULL columns (other
+// than identity, read-only and DefaultValue columns) that are missing or
+// NULL, string values longer than the column's Length, non-numeric values in
+// numeric columns, non-boolean values in boolean columns and decimal values
+// with more integer digits than the column's Precision allows, and values
+// outside a column's AllowedValues. Missing columns
+// are only flagged when the object would be inserted. If any problems are
+// found, a ValidationErrors is returned.
+func (o ORM) Validate(obj *object.Object) error {
+	objTable := o.s.GetTable(obj.Type)
+	if objTable == nil {
+		return errors.New("Validate: unknown object table " + obj.Type)
+	}
+
+	var errs ValidationErrors
+
+	keys := make([]string, 0, len(obj.KV))
+	for k := range obj.KV {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	present := make(map[string]bool)
+	for _, k := range keys {
+		f := objTable.GetColumn(k)
+		if f == nil {
+			errs = append(errs, fmt.Errorf("unknown field %s", k))
+			continue
+		}
+		present[f.Name] = true
+		if f.IsReadOnly() {
+			// Never written, so the database's value needs no checking
+			continue
+		}
+		errs = append(errs, o.validateValue(f, obj.KV[k])...)
+	}
+
+	_, pkPresent := obj.KV[objTable.Primary]
+	colNames := make([]string, 0, len(objTable.Columns))
+	for name := range objTable.Columns {
+		colNames = append(colNames, name)
+	}
+	sort.Strings(colNames)
+	for _, name := range colNames {
+		f := objTable.Columns[name]
+		if pkPresent || f.AllowNull || f.IsIdentity || f.IsReadOnly() || f.DefaultValue != "" || present[f.Name] {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("missing value for NOT NULL column %s", f.Name))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (o ORM) validateValue(f *schema.Column, v interface{}) []error {
+	var errs []error
+
+	if sv, ok := v.(*object.SQLValue); ok && !object.IsNULL(sv) {
+		// Raw SQL is the database's problem.
+		return nil
+	}
+	if object.IsNULL(v) {
+		if !f.AllowNull && !f.IsIdentity {
+			errs = append(errs, fmt.Errorf("NULL value for NOT NULL column %s", f.Name))
+		}
+		return errs
+	}
+	// Check what a driver.Valuer, such as a sql.NullInt64, is bound as
+	if dv, ok := v.(driver.Valuer); ok {
+		if val, err := dv.Value(); err == nil {
+			v = val
+		}
+	}
+
+	if len(f.AllowedValues) > 0 {
+		if s, ok := v.(string); !ok || !isAllowedValue(f, s) {
+			errs = append(errs, fmt.Errorf("value %v is not one of the allowed values (%s) of column %s", v, strings.Join(f.AllowedValues, ", "), f.Name))
+		}
+		return errs
+	}
+
+	if f.IsBoolean() {
+		if _, ok := v.(bool); !ok && !isNumericValue(v) {
+			errs = append(errs, fmt.Errorf("non-boolean value %v for boolean column %s", v, f.Name))
+		}
+		return errs
+	}
+
+	if f.IsDecimal() {
+		d, ok, err := f.DecimalString(v)
+		if !ok {
+			errs = append(errs, fmt.Errorf("non-decimal value %v for decimal column %s", v, f.Name))
+		} else if err != nil {
+			errs = append(errs, err)
+		} else if f.Precision > 0 {
+			intPart := strings.TrimLeft(strings.SplitN(d, ".", 2)[0], "-0")
+			if len(intPart) > f.Precision-f.Scale {
+				errs = append(errs, fmt.Errorf("value %s exceeds precision %d,%d of column %s", d, f.Precision, f.Scale, f.Name))
+			}
+		}
+		return errs
+	}
+
+	dbType := o.sqlGen.ColumnDBType(o.sqlGen, f)
+	if f.IsNumber || o.sqlGen.IsNumberType(dbType) || o.sqlGen.IsFloatingType(dbType) {
+		if !isNumericValue(v) {
+			errs = append(errs, fmt.Errorf("non-numeric value %v for numeric column %s", v, f.Name))
+		}
+		return errs
+	}
+
+	if s, ok := v.(string); ok && f.Length > 0 {
+		if n := utf8.RuneCountInString(s); n > f.Length {
+			errs = append(errs, lengthError(f, n))
+		}
+	}
+	return errs
+}
+
+func isAllowedValue(f *schema.Column, s string) bool {
+	for _, v := range f.AllowedValues {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumericValue(v interface{}) bool {
+	switch t := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(t, 64)
+		return err == nil
+	default:
+		return false
+	}
+}
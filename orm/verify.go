@@ -0,0 +1,155 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaMismatch describes one difference between the configured schema and
+// the live database. Column is empty for a missing table.
+type SchemaMismatch struct {
+	Table   string
+	Column  string
+	Problem string
+}
+
+func (m SchemaMismatch) String() string {
+	if m.Column == "" {
+		return m.Table + ": " + m.Problem
+	}
+	return m.Table + "." + m.Column + ": " + m.Problem
+}
+
+// SchemaMismatchError is returned by VerifySchema when the database does not
+// match the schema. It lists every mismatch found.
+type SchemaMismatchError struct {
+	Mismatches []SchemaMismatch
+}
+
+func (e *SchemaMismatchError) Error() string {
+	lines := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		lines[i] = m.String()
+	}
+	return "dyndao: schema does not match the database:\n\t" + strings.Join(lines, "\n\t")
+}
+
+// VerifySchema checks that every table in the schema exists in the database
+// with every one of its columns, and that each column's type is of the same
+// broad kind (string, numeric, timestamp or binary) as the schema's, so that
+// a service can fail fast at startup rather than with opaque SQL errors later.
+// Columns the database has beyond those in the schema are ignored. If
+// anything differs, a *SchemaMismatchError is returned.
+func (o ORM) VerifySchema(ctx context.Context) error {
+	ctx, cancel := o.boundContext(ctx)
+	defer cancel()
+
+	tableNames := make([]string, 0, len(o.s.Tables))
+	for name := range o.s.Tables {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+
+	var mismatches []SchemaMismatch
+	for _, name := range tableNames {
+		m, err := o.verifyTable(ctx, name)
+		if err != nil {
+			return errors.Wrap(err, "VerifySchema")
+		}
+		mismatches = append(mismatches, m...)
+	}
+	if mismatches != nil {
+		return &SchemaMismatchError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+func (o ORM) verifyTable(ctx context.Context, name string) ([]SchemaMismatch, error) {
+	tbl := o.s.GetTable(name)
+	tableName := o.s.RealTableName(tbl.Name, name)
+
+	exists, err := o.TableExists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return []SchemaMismatch{{Table: tableName, Problem: "table does not exist"}}, nil
+	}
+
+	// Selecting no rows is enough for the driver to describe the columns
+	sqlStr := fmt.Sprintf("SELECT * FROM %s WHERE 1=0", o.s.QualifiedTableName(tbl.Name, name, o.sqlGen.QuoteIdentifier))
+	rows, err := o.RawConn.QueryContext(ctx, sqlStr)
+	if err != nil {
+		return nil, errors.Wrap(err, sqlStr)
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err) // TODO logger implementation
+		}
+	}()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	dbTypes := make(map[string]string)
+	for _, ct := range columnTypes {
+		dbTypes[strings.ToUpper(ct.Name())] = ct.DatabaseTypeName()
+	}
+
+	colNames := make([]string, 0, len(tbl.Columns))
+	for k := range tbl.Columns {
+		colNames = append(colNames, k)
+	}
+	sort.Strings(colNames)
+
+	var mismatches []SchemaMismatch
+	g := o.sqlGen
+	for _, k := range colNames {
+		col := tbl.Columns[k]
+		dbType, ok := dbTypes[strings.ToUpper(col.Name)]
+		if !ok {
+			mismatches = append(mismatches, SchemaMismatch{Table: tableName, Column: col.Name, Problem: "column does not exist"})
+			continue
+		}
+		want := g.ColumnDBType(g, col)
+		wantKind, gotKind := o.typeKind(want), o.typeKind(dbType)
+		if wantKind != "" && gotKind != "" && wantKind != gotKind {
+			mismatches = append(mismatches, SchemaMismatch{
+				Table:   tableName,
+				Column:  col.Name,
+				Problem: fmt.Sprintf("expected a %s type like %s, but the database has %s", wantKind, want, dbType),
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+// typeKind returns the broad kind of a column type, or "" if the generator
+// doesn't recognise it. Drivers report types in their own way (INT for
+// INTEGER, or TINYINT for BOOLEAN), so only kinds are compared.
+func (o ORM) typeKind(dbType string) string {
+	if i := strings.IndexByte(dbType, '('); i >= 0 {
+		dbType = dbType[:i]
+	}
+	dbType = strings.ToUpper(strings.TrimSpace(dbType))
+
+	g := o.sqlGen
+	switch {
+	case dbType == "":
+		return ""
+	case g.IsStringType(dbType):
+		return "string"
+	case g.IsNumberType(dbType), g.IsFloatingType(dbType), g.IsDecimalType(dbType), g.IsBooleanType(dbType):
+		return "numeric"
+	case g.IsTimestampType(dbType):
+		return "timestamp"
+	case g.IsLOBType(dbType), g.IsBinaryType(dbType):
+		return "binary"
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"errors"
+	"strings"
+)
+
+// AggregateFuncs lists the aggregate functions that an Aggregate may use.
+var AggregateFuncs = map[string]bool{
+	"COUNT": true,
+	"SUM":   true,
+	"AVG":   true,
+	"MIN":   true,
+	"MAX":   true,
+}
+
+// Aggregate describes an aggregate expression such as COUNT(*) or
+// SUM(Amount). Column may be "*" for COUNT only. The result is stored under
+// Alias, or under the name returned by Name if Alias is empty.
+type Aggregate struct {
+	Func   string
+	Column string
+	Alias  string
+}
+
+// Count is COUNT(column), use "*" to count rows.
+func Count(column string) Aggregate { return Aggregate{Func: "COUNT", Column: column} }
+
+// Sum is SUM(column).
+func Sum(column string) Aggregate { return Aggregate{Func: "SUM", Column: column} }
+
+// Avg is AVG(column).
+func Avg(column string) Aggregate { return Aggregate{Func: "AVG", Column: column} }
+
+// Min is MIN(column).
+func Min(column string) Aggregate { return Aggregate{Func: "MIN", Column: column} }
+
+// Max is MAX(column).
+func Max(column string) Aggregate { return Aggregate{Func: "MAX", Column: column} }
+
+// As returns a copy of the aggregate stored under the given alias.
+func (a Aggregate) As(alias string) Aggregate {
+	a.Alias = alias
+	return a
+}
+
+// Name returns the alias of the aggregate, defaulting to the lower case
+// function name for COUNT(*) and to 'func_Column' otherwise, e.g. sum_Amount.
+func (a Aggregate) Name() string {
+	if a.Alias != "" {
+		return a.Alias
+	}
+	fn := strings.ToLower(a.Func)
+	if a.Column == "*" {
+		return fn
+	}
+	return fn + "_" + a.Column
+}
+
+// Validate checks the aggregate function and column.
+func (a Aggregate) Validate() error {
+	fn := strings.ToUpper(a.Func)
+	if !AggregateFuncs[fn] {
+		return errors.New("dyndao/query: unsupported aggregate function " + a.Func)
+	}
+	if a.Column == "" {
+		return errors.New("dyndao/query: aggregate " + fn + " has no column")
+	}
+	if a.Column == "*" && fn != "COUNT" {
+		return errors.New("dyndao/query: only COUNT accepts *")
+	}
+	return nil
+}
+
+// Having is a HAVING predicate comparing an aggregate to a value.
+type Having struct {
+	Aggregate Aggregate
+	Operator  string
+	Value     interface{}
+}
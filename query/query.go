@@ -0,0 +1,170 @@
+// Package query is a fluent builder for SELECT queries against a single table
+// in a dyndao schema. A Query only describes what should be retrieved, the SQL
+// itself is rendered by the active SQL generator (see sqlgen.BindingQuery) and
+// executed with orm.Query:
+//
+//	q := query.New().From("people").Where("Name", "=", "Joe").And("Age", ">", 18).OrderBy("Name").Limit(10)
+//	objs, err := o.Query(ctx, q)
+//
+// A Query selecting a single column, without an order or a limit, may be the
+// value of an IN or NOT IN condition, where it is rendered as a subquery:
+//
+//	inCA := query.New().Select("PersonID").From("addresses").Where("State", "=", "CA")
+//	q := query.New().From("people").Where("PersonID", "IN", inCA)
+package query
+
+import (
+	"errors"
+	"strings"
+)
+
+// Operators lists the comparison operators that a Condition may use. IN and
+// NOT IN take a slice of values, or a subquery.
+var Operators = map[string]bool{
+	"=":        true,
+	"<>":       true,
+	"!=":       true,
+	"<":        true,
+	"<=":       true,
+	">":        true,
+	">=":       true,
+	"LIKE":     true,
+	"NOT LIKE": true,
+	"IN":       true,
+	"NOT IN":   true,
+}
+
+// Condition is a single 'Column Operator Value' test in a WHERE clause. Or
+// joins it to the previous condition with OR rather than AND.
+type Condition struct {
+	Column   string
+	Operator string
+	Value    interface{}
+	Or       bool
+}
+
+// Order is a single column in an ORDER BY clause.
+type Order struct {
+	Column     string
+	Descending bool
+}
+
+// Query describes a SELECT against a single table. Columns are the columns
+// selected, the table's DefaultColumns when empty. Limit and Offset are
+// ignored when zero.
+type Query struct {
+	Table      string
+	Columns    []string
+	Conditions []Condition
+	Orders     []Order
+	LimitRows  int64
+	OffsetRows int64
+
+	err error
+}
+
+// New is an empty Query constructor
+func New() *Query {
+	return &Query{}
+}
+
+// From sets the table (or table alias) to query.
+func (q *Query) From(table string) *Query {
+	q.Table = table
+	return q
+}
+
+// Select sets the columns to retrieve.
+func (q *Query) Select(columns ...string) *Query {
+	q.Columns = columns
+	return q
+}
+
+// Where adds a condition joined to any previous condition with AND.
+func (q *Query) Where(column string, operator string, value interface{}) *Query {
+	return q.addCondition(column, operator, value, false)
+}
+
+// And is a synonym for Where, for readability.
+func (q *Query) And(column string, operator string, value interface{}) *Query {
+	return q.addCondition(column, operator, value, false)
+}
+
+// Or adds a condition joined to the previous condition with OR. AND is
+// evaluated before OR, as it is in SQL.
+func (q *Query) Or(column string, operator string, value interface{}) *Query {
+	return q.addCondition(column, operator, value, true)
+}
+
+func (q *Query) addCondition(column string, operator string, value interface{}, or bool) *Query {
+	op := strings.ToUpper(strings.TrimSpace(operator))
+	if !Operators[op] {
+		q.setErr(errors.New("dyndao/query: unsupported operator " + operator))
+	}
+	if sub, ok := value.(*Query); ok {
+		if op != "IN" && op != "NOT IN" {
+			q.setErr(errors.New("dyndao/query: a subquery needs IN or NOT IN, not " + operator))
+		}
+		if len(sub.Columns) != 1 {
+			q.setErr(errors.New("dyndao/query: a subquery must Select a single column"))
+		}
+	}
+	q.Conditions = append(q.Conditions, Condition{Column: column, Operator: op, Value: value, Or: or})
+	return q
+}
+
+// OrderBy adds an ascending ORDER BY column.
+func (q *Query) OrderBy(column string) *Query {
+	q.Orders = append(q.Orders, Order{Column: column})
+	return q
+}
+
+// OrderByDesc adds a descending ORDER BY column.
+func (q *Query) OrderByDesc(column string) *Query {
+	q.Orders = append(q.Orders, Order{Column: column, Descending: true})
+	return q
+}
+
+// Limit restricts the number of rows returned.
+func (q *Query) Limit(n int64) *Query {
+	if n < 0 {
+		q.setErr(errors.New("dyndao/query: negative limit"))
+	}
+	q.LimitRows = n
+	return q
+}
+
+// Offset skips the given number of rows before returning any.
+func (q *Query) Offset(n int64) *Query {
+	if n < 0 {
+		q.setErr(errors.New("dyndao/query: negative offset"))
+	}
+	q.OffsetRows = n
+	return q
+}
+
+func (q *Query) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// Err returns the first error encountered while building the query, or any
+// of its subqueries, such as an unsupported operator, or an error if no table
+// was given.
+func (q *Query) Err() error {
+	if q.err != nil {
+		return q.err
+	}
+	if q.Table == "" {
+		return errors.New("dyndao/query: no table given, call From")
+	}
+	for _, c := range q.Conditions {
+		if sub, ok := c.Value.(*Query); ok {
+			if err := sub.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package query
+
+import (
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	q := New().From("people").Where("Name", "=", "Joe").And("Age", ">", 18).Or("Age", "like", "%1").OrderBy("Name").OrderByDesc("Age").Limit(10).Offset(5)
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if q.Table != "people" || len(q.Conditions) != 3 || len(q.Orders) != 2 || q.LimitRows != 10 || q.OffsetRows != 5 {
+		t.Fatal("unexpected query", q)
+	}
+	if q.Conditions[2].Operator != "LIKE" || !q.Conditions[2].Or || q.Conditions[1].Or {
+		t.Fatal("unexpected conditions", q.Conditions)
+	}
+	if !q.Orders[1].Descending {
+		t.Fatal("expected the second order to be descending")
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	if New().Where("Name", "=", "Joe").Err() == nil {
+		t.Fatal("expected an error for a missing table")
+	}
+	if New().From("people").Where("Name", "~", "Joe").Err() == nil {
+		t.Fatal("expected an error for an unsupported operator")
+	}
+	if New().From("people").Limit(-1).Err() == nil {
+		t.Fatal("expected an error for a negative limit")
+	}
+
+	inner := New().Select("PersonID").From("addresses")
+	if New().From("people").Where("PersonID", "=", inner).Err() == nil {
+		t.Fatal("expected an error for a subquery compared with =")
+	}
+	if New().From("people").Where("PersonID", "IN", New().From("addresses")).Err() == nil {
+		t.Fatal("expected an error for a subquery selecting every column")
+	}
+	if New().From("people").Where("PersonID", "IN", New().Select("PersonID")).Err() == nil {
+		t.Fatal("expected the subquery's error to be reported")
+	}
+	if err := New().From("people").Where("PersonID", "NOT IN", inner).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,177 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationErrors lists every inconsistency found by Schema.Validate.
+type ValidationErrors []error
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, err := range v {
+		msgs[i] = err.Error()
+	}
+	return "dyndao/schema: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks that the schema is consistent with itself: every table has
+// a Name, every Primary, ForeignKeys, EssentialColumns, ColumnOrder,
+// ColumnAliases and Indexes entry names a column of its table, every column
+// has a DBType or a known LogicalType and a DefaultValue among its
+// AllowedValues, every ParentTables entry and alias names a table, and every
+// child relationship refers to existing tables and columns. All problems are
+// returned together as ValidationErrors, or nil if there are none. The
+// package function Validate is the same check.
+func (s *Schema) Validate() error {
+	var errs ValidationErrors
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	for _, alias := range sortedKeys(s.TableAliases) {
+		if s.Tables[s.TableAliases[alias]] == nil {
+			add("table alias %s refers to unknown table %s", alias, s.TableAliases[alias])
+		}
+	}
+
+	names := make([]string, 0, len(s.Tables))
+	for name := range s.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tbl := s.Tables[name]
+		if tbl == nil {
+			add("table %s is nil", name)
+			continue
+		}
+		if tbl.Name == "" {
+			add("table %s has an empty Name", name)
+		}
+		hasColumn := func(c string) bool {
+			return c != "" && tbl.GetColumn(c) != nil
+		}
+
+		if tbl.Primary == "" {
+			add("table %s has no Primary", name)
+		} else if !hasColumn(tbl.Primary) {
+			add("table %s Primary %s is not a column", name, tbl.Primary)
+		}
+		for _, fk := range tbl.ForeignKeys {
+			if !hasColumn(fk) {
+				add("table %s ForeignKeys entry %s is not a column", name, fk)
+			}
+		}
+		for _, c := range tbl.EssentialColumns {
+			if !hasColumn(c) {
+				add("table %s EssentialColumns entry %s is not a column", name, c)
+			}
+		}
+		for _, c := range tbl.ColumnOrder {
+			if !hasColumn(c) {
+				add("table %s ColumnOrder entry %s is not a column", name, c)
+			}
+		}
+		columnNames := make([]string, 0, len(tbl.Columns))
+		for c := range tbl.Columns {
+			columnNames = append(columnNames, c)
+		}
+		sort.Strings(columnNames)
+		for _, c := range columnNames {
+			col := tbl.Columns[c]
+			if col == nil {
+				add("table %s column %s is nil", name, c)
+				continue
+			}
+			if col.LogicalType != "" && !IsLogicalType(col.LogicalType) {
+				add("table %s column %s has unknown LogicalType %s", name, c, col.LogicalType)
+			}
+			if col.DBType == "" && col.LogicalType == "" {
+				add("table %s column %s has neither a DBType nor a LogicalType", name, c)
+			}
+			if col.DefaultValue != "" && len(col.AllowedValues) > 0 && !containsString(col.AllowedValues, col.DefaultValue) {
+				add("table %s column %s DefaultValue %s is not one of its AllowedValues", name, c, col.DefaultValue)
+			}
+		}
+		for _, alias := range sortedKeys(tbl.ColumnAliases) {
+			if tbl.Columns[tbl.ColumnAliases[alias]] == nil {
+				add("table %s column alias %s refers to unknown column %s", name, alias, tbl.ColumnAliases[alias])
+			}
+		}
+		for _, idx := range tbl.Indexes {
+			for _, c := range idx.Columns {
+				if !hasColumn(c) {
+					add("table %s index %s column %s is not a column", name, idx.Name, c)
+				}
+			}
+		}
+		for _, pt := range tbl.ParentTables {
+			if s.GetTable(pt) == nil {
+				add("table %s ParentTables entry %s is not a table", name, pt)
+			}
+		}
+
+		childNames := make([]string, 0, len(tbl.Children))
+		for childName := range tbl.Children {
+			childNames = append(childNames, childName)
+		}
+		sort.Strings(childNames)
+
+		for _, childName := range childNames {
+			child := tbl.Children[childName]
+			childTbl := s.GetTable(childName)
+			if childTbl == nil {
+				add("table %s child %s is not a table", name, childName)
+				continue
+			}
+			if child == nil {
+				continue
+			}
+			if child.ParentTable != "" && s.GetTable(child.ParentTable) == nil {
+				add("table %s child %s ParentTable %s is not a table", name, childName, child.ParentTable)
+			}
+			localCols, foreignCols, err := child.KeyColumns()
+			if err != nil {
+				add("table %s child %s: %s", name, childName, err.Error())
+				continue
+			}
+			for _, c := range localCols {
+				if childTbl.GetColumn(c) == nil {
+					add("table %s child %s local column %s is not a column of %s", name, childName, c, childName)
+				}
+			}
+			for _, c := range foreignCols {
+				if !hasColumn(c) {
+					add("table %s child %s foreign column %s is not a column of %s", name, childName, c, name)
+				}
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
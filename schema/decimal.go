@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecimalString renders v as an exact decimal string for a decimal column,
+// rounded to the column's Scale if it has one. It accepts *big.Rat, big.Rat,
+// decimal strings, floats and integers of any size, along with a
+// driver.Valuer such as a sql.NullFloat64 which binds as one of those; floats
+// are rendered with the fewest digits that represent them, so 19.99 becomes
+// "19.99" rather than the nearest binary fraction. The bool result is false
+// if v is of any other type, or is NULL, in which case the caller should bind
+// it as it would otherwise.
+func (c *Column) DecimalString(v interface{}) (string, bool, error) {
+	if dv, ok := v.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(dv); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "", false, nil
+		}
+		val, err := dv.Value()
+		if err != nil || val == nil {
+			return "", false, nil
+		}
+		v = val
+	}
+
+	var s string
+	switch t := v.(type) {
+	case *big.Rat:
+		if t == nil {
+			return "", false, nil
+		}
+		return c.formatRat(t), true, nil
+	case big.Rat:
+		return c.formatRat(&t), true, nil
+	case string:
+		s = strings.TrimSpace(t)
+	case float64:
+		s = strconv.FormatFloat(t, 'f', -1, 64)
+	case float32:
+		s = strconv.FormatFloat(float64(t), 'f', -1, 32)
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(rv.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(rv.Uint(), 10)
+		default:
+			return "", false, nil
+		}
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return "", true, fmt.Errorf("invalid decimal value %q for column %s", s, c.Name)
+	}
+	if c.Scale > 0 {
+		return r.FloatString(c.Scale), true, nil
+	}
+	if strings.ContainsAny(s, "eE/") {
+		return c.formatRat(r), true, nil
+	}
+	return s, true, nil
+}
+
+// Uint64Value returns the value to bind for v in the column. database/sql
+// only binds uint64 values that fit in an int64, so larger values are bound
+// as exact decimal strings in decimal columns and Oracle NUMBER columns, and
+// as they are in unsigned columns, whose drivers accept them. Any other
+// column cannot hold them, and an error is returned.
+func (c *Column) Uint64Value(v uint64) (interface{}, error) {
+	switch {
+	case v <= math.MaxInt64:
+		return int64(v), nil
+	case c.IsDecimal() || strings.HasPrefix(strings.ToUpper(c.DBType), "NUMBER"):
+		return strconv.FormatUint(v, 10), nil
+	case c.IsUnsigned():
+		return v, nil
+	}
+	return nil, fmt.Errorf("value %d for column %s exceeds the range of a signed 64-bit integer", v, c.Name)
+}
+
+// formatRat renders r with the column's Scale, or with up to 30 decimal
+// places if it has none.
+func (c *Column) formatRat(r *big.Rat) string {
+	if c.Scale > 0 {
+		return r.FloatString(c.Scale)
+	}
+	if r.IsInt() {
+		return r.FloatString(0)
+	}
+	s := strings.TrimRight(r.FloatString(30), "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Equal reports whether the schema describes the same tables as other, and
+// lists the differences when it doesn't: the tables and columns found on
+// only one side, and every field that differs, such as
+// "table people: column Name: DBType varchar != text", with s's value
+// first. Nil and empty maps and slices are treated as equal.
+func (s *Schema) Equal(other *Schema) (bool, []string) {
+	var diffs []string
+	compareValues("", reflect.ValueOf(s), reflect.ValueOf(other), &diffs)
+	return len(diffs) == 0, diffs
+}
+
+// mapEntryLabels names the entries of the schema's maps in differences.
+var mapEntryLabels = map[string]string{
+	"Tables":        "table",
+	"TableAliases":  "table alias",
+	"Columns":       "column",
+	"ColumnAliases": "column alias",
+	"Children":      "child",
+}
+
+// compareValues adds the differences between a and b, found at path, to
+// diffs.
+func compareValues(path string, a, b reflect.Value, diffs *[]string) {
+	add := func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		if path != "" {
+			msg = path + " " + msg
+		}
+		*diffs = append(*diffs, msg)
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil():
+			add("is nil")
+		case b.IsNil():
+			add("is nil in other")
+		default:
+			compareValues(path, a.Elem(), b.Elem(), diffs)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			name := a.Type().Field(i).Name
+			av, bv := a.Field(i), b.Field(i)
+			if av.Kind() == reflect.Map {
+				label, ok := mapEntryLabels[name]
+				if !ok {
+					label = name
+				}
+				compareMaps(path, label, av, bv, diffs)
+				continue
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + ": " + name
+			}
+			compareValues(fieldPath, av, bv, diffs)
+		}
+
+	case reflect.Slice:
+		if a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+		if a.Len() != b.Len() {
+			add("%v != %v", a.Interface(), b.Interface())
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			if a.Index(i).Kind() == reflect.Struct {
+				compareValues(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i), diffs)
+			} else if a.Index(i).Interface() != b.Index(i).Interface() {
+				add("%v != %v", a.Interface(), b.Interface())
+				return
+			}
+		}
+
+	default:
+		if a.Interface() != b.Interface() {
+			add("%v != %v", a.Interface(), b.Interface())
+		}
+	}
+}
+
+// compareMaps compares two maps keyed by name, reporting the entries found
+// on one side only and the differences between the others.
+func compareMaps(path, label string, a, b reflect.Value, diffs *[]string) {
+	prefix := ""
+	if path != "" {
+		prefix = path + ": "
+	}
+
+	keys := make(map[string]bool)
+	for _, k := range a.MapKeys() {
+		keys[k.String()] = true
+	}
+	for _, k := range b.MapKeys() {
+		keys[k.String()] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		entryPath := prefix + label + " " + k
+		av := a.MapIndex(reflect.ValueOf(k))
+		bv := b.MapIndex(reflect.ValueOf(k))
+		switch {
+		case !bv.IsValid():
+			*diffs = append(*diffs, entryPath+" is missing from other")
+		case !av.IsValid():
+			*diffs = append(*diffs, entryPath+" is only in other")
+		default:
+			compareValues(entryPath, av, bv, diffs)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// LoadJSON parses a schema definition from r and checks that every table can
+// be used by the ORM, see checkLoaded.
+func LoadJSON(r io.Reader) (*Schema, error) {
+	sch := DefaultSchema()
+	if err := json.NewDecoder(r).Decode(sch); err != nil {
+		return nil, fmt.Errorf("dyndao/schema: LoadJSON: %s", err.Error())
+	}
+	if err := checkLoaded(sch); err != nil {
+		return nil, err
+	}
+	return sch, nil
+}
+
+// LoadJSONFile is LoadJSON for the file at path.
+func LoadJSONFile(path string) (*Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("dyndao/schema: LoadJSONFile: %s", err.Error())
+	}
+	defer f.Close()
+
+	sch, err := LoadJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s (file %s)", err.Error(), path)
+	}
+	return sch, nil
+}
+
+// checkLoaded verifies the fields that a schema definition loaded from a file
+// must provide: at least one table, and for every table a Columns map holding
+// a definition for its Primary column. Tables are checked in name order so
+// that the reported error is deterministic. A missing Children map is
+// replaced by an empty one.
+func checkLoaded(sch *Schema) error {
+	if len(sch.Tables) == 0 {
+		return errors.New("dyndao/schema: schema defines no Tables")
+	}
+
+	names := make([]string, 0, len(sch.Tables))
+	for name := range sch.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tbl := sch.Tables[name]
+		if tbl == nil {
+			return fmt.Errorf("dyndao/schema: table %s is null", name)
+		}
+		if tbl.Primary == "" {
+			return fmt.Errorf("dyndao/schema: table %s has no Primary", name)
+		}
+		if tbl.Columns == nil {
+			return fmt.Errorf("dyndao/schema: table %s has no Columns", name)
+		}
+		for colName, col := range tbl.Columns {
+			if col == nil {
+				return fmt.Errorf("dyndao/schema: table %s column %s is null", name, colName)
+			}
+		}
+		if tbl.GetColumn(tbl.Primary) == nil {
+			return fmt.Errorf("dyndao/schema: table %s Primary %s is not in Columns", name, tbl.Primary)
+		}
+		if tbl.Children == nil {
+			tbl.Children = make(map[string]*ChildTable)
+		}
+	}
+	return nil
+}
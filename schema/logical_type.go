@@ -0,0 +1,68 @@
+package schema
+
+import "strings"
+
+// Logical column types. A Column may set LogicalType instead of DBType, in
+// which case each SQL generator maps it to the matching column type for its
+// dialect, so that the same schema can create tables on any adapter.
+const (
+	LogicalString    = "string" // Bounded string, Length is required by most dialects
+	LogicalText      = "text"   // Unbounded string
+	LogicalInt       = "int"
+	LogicalFloat     = "float"
+	LogicalDecimal   = "decimal" // Fixed precision number, see Column.Precision and Column.Scale
+	LogicalBlob      = "blob"
+	LogicalTimestamp = "timestamp"
+	LogicalBool      = "bool"
+)
+
+// LogicalTypes lists every logical column type.
+var LogicalTypes = []string{
+	LogicalString,
+	LogicalText,
+	LogicalInt,
+	LogicalFloat,
+	LogicalDecimal,
+	LogicalBlob,
+	LogicalTimestamp,
+	LogicalBool,
+}
+
+// IsLogicalType reports whether t is one of the logical column types.
+func IsLogicalType(t string) bool {
+	for _, lt := range LogicalTypes {
+		if t == lt {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBoolean reports whether the column holds booleans, either through its
+// LogicalType or a boolean DBType.
+func (c *Column) IsBoolean() bool {
+	if c.LogicalType == LogicalBool {
+		return true
+	}
+	switch strings.ToUpper(c.DBType) {
+	case "BOOLEAN", "BOOL", "BIT":
+		return true
+	}
+	return false
+}
+
+// IsDecimal reports whether the column holds fixed precision numbers, either
+// through its LogicalType, a DECIMAL or NUMERIC DBType, or a Scale.
+func (c *Column) IsDecimal() bool {
+	if c.LogicalType == LogicalDecimal || c.Scale > 0 {
+		return true
+	}
+	dbType := strings.ToUpper(c.DBType)
+	return strings.HasPrefix(dbType, "DECIMAL") || strings.HasPrefix(dbType, "NUMERIC")
+}
+
+// IsUnsigned reports whether the column's DBType is unsigned, as in MySQL's
+// BIGINT UNSIGNED.
+func (c *Column) IsUnsigned() bool {
+	return strings.Contains(strings.ToUpper(c.DBType), "UNSIGNED")
+}
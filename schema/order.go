@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// KeyColumns returns the child ('local') and parent ('foreign') columns that
+// make up the relationship, using LocalColumns and ForeignColumns when
+// MultiKey is set and LocalColumn and ForeignColumn otherwise.
+func (c *ChildTable) KeyColumns() ([]string, []string, error) {
+	if c.MultiKey {
+		if len(c.LocalColumns) != len(c.ForeignColumns) {
+			return nil, nil, errors.New("dyndao: ChildTable LocalColumns and ForeignColumns differ in length")
+		}
+		return c.LocalColumns, c.ForeignColumns, nil
+	}
+	if c.LocalColumn == "" && c.ForeignColumn == "" {
+		return nil, nil, nil
+	}
+	if c.LocalColumn == "" || c.ForeignColumn == "" {
+		return nil, nil, errors.New("dyndao: ChildTable requires both LocalColumn and ForeignColumn")
+	}
+	return []string{c.LocalColumn}, []string{c.ForeignColumn}, nil
+}
+
+// ParentTableNames returns the sorted names of every table that table n
+// depends on, either through its own ParentTables or by being listed in
+// another table's Children.
+func (s *Schema) ParentTableNames(n string) []string {
+	n = s.GetTableName(n)
+	seen := make(map[string]bool)
+
+	if tbl := s.GetTable(n); tbl != nil {
+		for _, pt := range tbl.ParentTables {
+			seen[s.GetTableName(pt)] = true
+		}
+	}
+	for name, tbl := range s.Tables {
+		for childName := range tbl.Children {
+			if s.GetTableName(childName) == n {
+				seen[name] = true
+			}
+		}
+	}
+	delete(seen, n) // self-references don't affect ordering
+
+	parents := make([]string, 0, len(seen))
+	for name := range seen {
+		parents = append(parents, name)
+	}
+	sort.Strings(parents)
+	return parents
+}
+
+// TableCreationOrder returns the schema's table names ordered so that every
+// parent table comes before any of its children. Tables must be created in
+// this order when FOREIGN KEY constraints are generated.
+func (s *Schema) TableCreationOrder() ([]string, error) {
+	names := make([]string, 0, len(s.Tables))
+	for name := range s.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	order := make([]string, 0, len(names))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dyndao: schema has a cyclic parent/child relationship involving table %s", name)
+		}
+		state[name] = visiting
+		for _, parent := range s.ParentTableNames(name) {
+			if _, ok := s.Tables[parent]; !ok {
+				continue
+			}
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// TableDropOrder returns the schema's table names ordered so that every child
+// table comes before its parents, the reverse of TableCreationOrder. Tables
+// must be dropped in this order when FOREIGN KEY constraints are generated.
+func (s *Schema) TableDropOrder() ([]string, error) {
+	order, err := s.TableCreationOrder()
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order, nil
+}
@@ -0,0 +1,42 @@
+package infoschema
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/adapters/core"
+	"github.com/rbastic/dyndao/adapters/mysql"
+	"github.com/rbastic/dyndao/schema"
+)
+
+// TestDefaultRoundTrip checks that the defaults read back from
+// information_schema render the DDL they came from.
+func TestDefaultRoundTrip(t *testing.T) {
+	sch := schema.DefaultSchema()
+	sch.Tables["widgets"] = schema.DefaultTable()
+	col := func(name string, dataType string, def string) {
+		setTableCol(sch, "widgets", sql.NullString{String: name, Valid: true}, dataType, sql.NullString{String: def, Valid: true}, "NO", "", "")
+	}
+	col("Color", "varchar", "'blue'")
+	col("Name", "varchar", "it's")
+	col("Created", "timestamp", "CURRENT_TIMESTAMP(6)")
+	col("Updated", "datetime", "now()")
+	col("Label", "varchar", "now()")
+
+	g := mysql.New(core.New())
+	tbl := sch.GetTable("widgets")
+	tests := map[string]string{
+		"Color":   "DEFAULT 'blue'",
+		"Name":    "DEFAULT 'it''s'",
+		"Created": "DEFAULT CURRENT_TIMESTAMP(6)",
+		"Updated": "DEFAULT now()",
+		"Label":   "DEFAULT 'now()'",
+	}
+	for name, want := range tests {
+		ddl := g.RenderCreateColumn(g, tbl.GetColumn(name))
+		if !strings.Contains(ddl, want+" ") {
+			t.Error("expected", name, "to be rendered with", want, "got", ddl)
+		}
+	}
+}
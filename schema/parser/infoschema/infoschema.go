@@ -11,7 +11,6 @@
 
 /*
 	TODO: foreign key identification
-	TODO: Indexes
 	TODO: Constraints
 	TODO: interface type for infoschema package (so that we
 	have an interface to implement an oracle-alike version for,
@@ -55,6 +54,10 @@ func LoadSchema(ctx context.Context, db *sql.DB, dbName string) (*schema.Schema,
 	if err != nil {
 		return nil, err
 	}
+	err = ParseIndexes(ctx, db, dbName, sch)
+	if err != nil {
+		return nil, err
+	}
 	SetDefaultEssentialColumns(sch)
 	return sch, nil
 }
@@ -147,11 +150,67 @@ func setTableCol(sch *schema.Schema, tblName string, colName sql.NullString, dat
 	}
 	df.IsIdentity = isIdentity
 
-	// TODO: IsNumber, need a SQL generator for that, unless we deprecate
-	// IsNumber, I think.  See issue #49 on github.
+	// IsNumber and the like depend on the dialect's types, see
+	// SQLGenerator.InferColumns.
 	tbl.Columns[colName.String] = df
 }
 
+// TODO: STATISTICS is what MySQL and MariaDB provide, it isn't part of
+// the information_schema standard.
+func getIndexMetaSQL(db string) string {
+	return fmt.Sprintf(`
+SELECT TABLE_NAME, INDEX_NAME, COLUMN_NAME, NON_UNIQUE
+FROM INFORMATION_SCHEMA.STATISTICS
+WHERE TABLE_SCHEMA='%s' AND INDEX_NAME <> 'PRIMARY'
+ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX
+	`, db)
+}
+
+// ParseIndexes loads all non-primary index definitions from a given schema into the relevant tables.
+func ParseIndexes(ctx context.Context, db *sql.DB, dbName string, sch *schema.Schema) error {
+	rows, err := db.QueryContext(ctx, getIndexMetaSQL(dbName))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = rows.Close()
+	}()
+
+	for rows.Next() {
+		var tblName string
+		var indexName string
+		var colName string
+		var nonUnique int64
+
+		err := rows.Scan(&tblName, &indexName, &colName, &nonUnique)
+		if err != nil {
+			return err
+		}
+
+		// Mutates the schema.Table for the given tblName and indexName
+		setTableIndex(sch, tblName, indexName, colName, nonUnique == 0)
+	}
+
+	err = rows.Err()
+	return err
+}
+
+func setTableIndex(sch *schema.Schema, tblName string, indexName string, colName string, unique bool) {
+	tbl, ok := sch.Tables[tblName]
+	if !ok {
+		return
+	}
+
+	// Rows arrive ordered by index, so a multi-column index continues the
+	// most recently added one.
+	n := len(tbl.Indexes)
+	if n > 0 && tbl.Indexes[n-1].Name == indexName {
+		tbl.Indexes[n-1].Columns = append(tbl.Indexes[n-1].Columns, colName)
+		return
+	}
+	tbl.Indexes = append(tbl.Indexes, schema.Index{Name: indexName, Columns: []string{colName}, Unique: unique})
+}
+
 // SetDefaultEssentialColumns configures the EssentialColumns
 // for each schema.Table to be the entire list of field names.
 func SetDefaultEssentialColumns(sch *schema.Schema) {
@@ -2,7 +2,9 @@ package schema
 
 import (
 	"encoding/json"
-	//"fmt"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 // DefaultSchema returns an empty schema ready to be populated
@@ -34,7 +36,7 @@ func (s *Schema) ToJSONBytes() ([]byte, error) {
 // FromJSON unmarshals a JSON string into a Schema object.
 func FromJSON(jsonStr string) (*Schema, error) {
 	sch := DefaultSchema()
-	err := json.Unmarshal([]byte(jsonStr), &sch)
+	err := json.Unmarshal([]byte(jsonStr), sch)
 	if err != nil {
 		return nil, err
 	}
@@ -44,13 +46,45 @@ func FromJSON(jsonStr string) (*Schema, error) {
 // FromJSONBytes unmarshals a JSON byte array into a Schema object.
 func FromJSONBytes(jsonBytes []byte) (*Schema, error) {
 	sch := DefaultSchema()
-	err := json.Unmarshal(jsonBytes, &sch)
+	err := json.Unmarshal(jsonBytes, sch)
 	if err != nil {
 		return nil, err
 	}
 	return sch, nil
 }
 
+// RealTableName is GetTableName with the schema's TablePrefix applied, giving
+// the name of the table in the database.
+func (s *Schema) RealTableName(override string, ourDefault string) string {
+	return s.TablePrefix + GetTableName(override, ourDefault)
+}
+
+// TableSchemaName returns the schema (owner) qualifying the table n, or its
+// alias: the table's SchemaName, or else the schema's.
+func (s *Schema) TableSchemaName(n string) string {
+	if t := s.GetTable(n); t != nil && t.SchemaName != "" {
+		return t.SchemaName
+	}
+	return s.SchemaName
+}
+
+// QualifiedTableName is RealTableName qualified by TableSchemaName, such as
+// hr.people or sales.hr.people, with each segment quoted on its own by
+// quote. The name of a table without a schema is returned as RealTableName
+// returns it.
+func (s *Schema) QualifiedTableName(override string, ourDefault string, quote func(string) string) string {
+	name := s.RealTableName(override, ourDefault)
+	owner := s.TableSchemaName(ourDefault)
+	if owner == "" {
+		return name
+	}
+	segments := append(strings.Split(owner, "."), name)
+	for i := range segments {
+		segments[i] = quote(segments[i])
+	}
+	return strings.Join(segments, ".")
+}
+
 // GetTableName returns the correct Table name in a potentially aliased environment.
 func (s *Schema) GetTableName(n string) string {
 	if s.TableAliases != nil {
@@ -105,6 +139,256 @@ func (t *Table) GetColumn(n string) *Column {
 	return t.Columns[n]
 }
 
+// MatchColumnName returns the name of the column n refers to, for names
+// reported by a database driver: n itself or the real column name if n is an
+// alias, or else the column whose name matches n ignoring case, since Oracle
+// reports unquoted identifiers in upper case and PostgreSQL in lower case. It
+// returns false if no column matches.
+func (t *Table) MatchColumnName(n string) (string, bool) {
+	if f := t.GetColumn(n); f != nil {
+		return t.GetColumnName(n), true
+	}
+	for k := range t.Columns {
+		if strings.EqualFold(k, n) {
+			return k, true
+		}
+	}
+	for alias, realName := range t.ColumnAliases {
+		if strings.EqualFold(alias, n) {
+			return realName, true
+		}
+	}
+	return "", false
+}
+
+// GetColumnAlias returns the alias for the real column name n, or n itself if
+// the column has no alias. If several aliases refer to the same column, the
+// alphabetically first one is returned.
+func (t *Table) GetColumnAlias(n string) string {
+	alias := ""
+	for k, realName := range t.ColumnAliases {
+		if realName == n && (alias == "" || k < alias) {
+			alias = k
+		}
+	}
+	if alias == "" {
+		return n
+	}
+	return alias
+}
+
+// PrimaryKeyColumns returns the columns which identify a single row: the
+// Primary column, followed by the ForeignKeys if the table is MultiKey.
+func (t *Table) PrimaryKeyColumns() []string {
+	if !t.MultiKey {
+		return []string{t.Primary}
+	}
+	keys := make([]string, 0, 1+len(t.ForeignKeys))
+	keys = append(keys, t.Primary)
+	keys = append(keys, t.ForeignKeys...)
+	return keys
+}
+
+// ColumnNames returns the names of every column of the table, in
+// ColumnOrder and then sorted.
+func (t *Table) ColumnNames() []string {
+	names := make([]string, 0, len(t.Columns))
+	listed := make(map[string]bool, len(t.ColumnOrder))
+	for _, name := range t.ColumnOrder {
+		if _, ok := t.Columns[name]; ok && !listed[name] {
+			names = append(names, name)
+			listed[name] = true
+		}
+	}
+	rest := make([]string, 0, len(t.Columns)-len(names))
+	for name := range t.Columns {
+		if !listed[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// SortColumns sorts names, which may be column aliases, into the order of
+// ColumnNames. Names that are not columns of the table go last, sorted.
+func (t *Table) SortColumns(names []string) {
+	rank := make(map[string]int, len(t.Columns))
+	for i, name := range t.ColumnNames() {
+		rank[name] = i
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, iok := rank[t.GetColumnName(names[i])]
+		rj, jok := rank[t.GetColumnName(names[j])]
+		switch {
+		case iok && jok && ri != rj:
+			return ri < rj
+		case iok != jok:
+			return iok
+		}
+		return names[i] < names[j]
+	})
+}
+
+// DefaultColumns returns the columns selected when a retrieval does not name
+// any: the EssentialColumns if the table has them, or else every column.
+// The EssentialColumns are put in ColumnOrder when the table has one.
+func (t *Table) DefaultColumns() []string {
+	if len(t.EssentialColumns) == 0 {
+		return t.ColumnNames()
+	}
+	if len(t.ColumnOrder) == 0 {
+		return t.EssentialColumns
+	}
+	cols := append([]string(nil), t.EssentialColumns...)
+	t.SortColumns(cols)
+	return cols
+}
+
+// RemoveColumn removes the column n, which may be an alias, from the table,
+// along with its aliases and its entries in ColumnOrder and EssentialColumns.
+// A column the table still refers to, see ColumnReferences, is not removed
+// and an error is returned instead: it would leave the table inconsistent,
+// and removing the only EssentialColumns entry would silently make
+// DefaultColumns select every column. References from the other tables of a
+// schema are checked by Schema.ColumnReferences.
+func (t *Table) RemoveColumn(n string) error {
+	name := t.GetColumnName(n)
+	if refs := t.columnReferences(name); len(refs) > 0 {
+		return fmt.Errorf("dyndao/schema: cannot remove column %s of table %s, it is %s", name, t.Name, strings.Join(refs, ", "))
+	}
+	delete(t.Columns, name)
+	for alias, realName := range t.ColumnAliases {
+		if realName == name {
+			delete(t.ColumnAliases, alias)
+		}
+	}
+	t.ColumnOrder = removeName(t.ColumnOrder, name)
+	t.EssentialColumns = removeName(t.EssentialColumns, name)
+	return nil
+}
+
+// ColumnReferences describes what in the schema refers to the column of the
+// table, which may be aliases: the table's Primary, ForeignKeys, Indexes and
+// only EssentialColumns entry, and the key columns of the child relations of
+// the table and of its parents. It returns nil if nothing does, in which case
+// the column can be removed.
+func (s *Schema) ColumnReferences(table string, column string) []string {
+	tbl := s.GetTable(table)
+	if tbl == nil {
+		return nil
+	}
+	name := tbl.GetColumnName(column)
+	refs := tbl.columnReferences(name)
+
+	tableName := s.GetTableName(table)
+	parents := make([]string, 0, len(s.Tables))
+	for parentName := range s.Tables {
+		parents = append(parents, parentName)
+	}
+	sort.Strings(parents)
+	for _, parentName := range parents {
+		parent := s.Tables[parentName]
+		child := parent.Children[tableName]
+		if child == nil {
+			continue
+		}
+		local, _, err := child.KeyColumns()
+		if err != nil {
+			continue
+		}
+		if local == nil {
+			// The child holds the parent's primary key
+			local = []string{parent.Primary}
+		}
+		for _, c := range local {
+			if c == name {
+				refs = append(refs, "a key column of child "+tableName+" of table "+parentName)
+				break
+			}
+		}
+	}
+	return refs
+}
+
+// columnReferences describes what in the table refers to its column name.
+func (t *Table) columnReferences(name string) []string {
+	var refs []string
+	for _, pk := range t.PrimaryKeyColumns() {
+		if t.GetColumnName(pk) == name {
+			refs = append(refs, "a primary key column")
+			break
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		if t.GetColumnName(fk) == name {
+			refs = append(refs, "a ForeignKeys entry")
+			break
+		}
+	}
+	for _, idx := range t.Indexes {
+		for _, c := range idx.Columns {
+			if t.GetColumnName(c) == name {
+				refs = append(refs, "a column of index "+idx.Name)
+				break
+			}
+		}
+	}
+	if len(t.EssentialColumns) == 1 && t.GetColumnName(t.EssentialColumns[0]) == name {
+		refs = append(refs, "the only EssentialColumns entry")
+	}
+	children := make([]string, 0, len(t.Children))
+	for childName := range t.Children {
+		children = append(children, childName)
+	}
+	sort.Strings(children)
+	for _, childName := range children {
+		_, foreign, err := t.Children[childName].KeyColumns()
+		if err != nil {
+			continue
+		}
+		for _, c := range foreign {
+			if t.GetColumnName(c) == name {
+				refs = append(refs, "a key column of child "+childName)
+				break
+			}
+		}
+	}
+	return refs
+}
+
+func removeName(names []string, name string) []string {
+	var kept []string
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// IsReadOnly reports whether the column is populated by the database, either
+// as a generated column computed from its Generated expression or because it
+// is flagged ReadOnly. Read-only columns are retrieved like any other, but
+// are never bound on INSERT or UPDATE.
+func (c *Column) IsReadOnly() bool {
+	return c.ReadOnly || c.Generated != ""
+}
+
+// UsesLastInsertID reports whether the primary key for a newly inserted row is
+// assigned by the database. It is false when the caller supplies the primary
+// key or when the primary key is a generated UUID.
+func (t *Table) UsesLastInsertID() bool {
+	if t.CallerSuppliesPK {
+		return false
+	}
+	pk := t.GetColumn(t.Primary)
+	if pk != nil && pk.IsUUID {
+		return false
+	}
+	return true
+}
+
 // DefaultTable returns an empty table ready to be populated
 func DefaultTable() *Table {
 	fieldsMap := make(map[string]*Column)
@@ -116,6 +400,8 @@ func DefaultTable() *Table {
 		Columns:          fieldsMap,
 		EssentialColumns: nil,
 		Children:         childrenMap,
+		Indexes:          nil,
+		Checks:           nil,
 	}
 	return tbl
 }
@@ -129,6 +415,8 @@ func DefaultColumn() *Column {
 		IsNumber:     false,
 		DBType:       "",
 		IsIdentity:   false,
+		IsUUID:       false,
+		Check:        "",
 	}
 	return fld
 }
@@ -143,6 +431,8 @@ func DefaultChildTable() *ChildTable {
 
 		LocalColumns:   nil,
 		ForeignColumns: nil,
+
+		OnDeleteCascade: false,
 	}
 	return chld
 }
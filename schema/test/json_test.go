@@ -2,24 +2,72 @@ package test
 
 import (
 	"encoding/json"
-	"fmt"
-	"github.com/rbastic/dyndao/schema/test/mock"
+	"reflect"
 	"testing"
+
+	"github.com/rbastic/dyndao/schema"
+	"github.com/rbastic/dyndao/schema/test/mock"
 )
 
+// aliasedSchema is the nested mock with every alias and flag field set, so
+// that a round trip exercises all of them.
+func aliasedSchema() *schema.Schema {
+	sch := mock.NestedSchema()
+	sch.Name = "mock"
+	sch.TableAliases = map[string]string{"person": "people"}
+
+	people := sch.Tables["people"]
+	people.AliasName = "person"
+	people.ColumnAliases = map[string]string{"FullName": "Name"}
+
+	addresses := sch.Tables["addresses"]
+	addresses.CallerSuppliesPK = true
+	addresses.ParentTables = []string{"people"}
+	addresses.Checks = []string{"Zip <> ''"}
+	child := people.Children["addresses"]
+	child.ParentTable = "people"
+	child.LocalColumn = "PersonID"
+	child.ForeignColumn = "PersonID"
+	child.OnDeleteCascade = true
+	return sch
+}
+
 func TestJSONMarshalUnmarshal(t *testing.T) {
-	sch := mock.BasicSchema()
-	buf, err := json.Marshal(sch)
-	if err != nil {
-		t.Fatal(err)
+	for name, sch := range map[string]*schema.Schema{
+		"basic":   mock.BasicSchema(),
+		"aliased": aliasedSchema(),
+		"widgets": mock.WidgetSchema(),
+	} {
+		buf, err := json.Marshal(sch)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded *schema.Schema
+		err = json.Unmarshal(buf, &decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sch, decoded) {
+			t.Errorf("%s: schema did not survive a JSON round trip:\n%s", name, string(buf))
+		}
+
+		fromJSON, err := schema.FromJSONBytes(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sch, fromJSON) {
+			t.Errorf("%s: FromJSONBytes did not reproduce the schema", name)
+		}
 	}
-	// TODO: Fix this test.
-	fmt.Println("Marshalled buf=", string(buf))
+}
 
-	err = json.Unmarshal(buf, &sch)
+func TestFromJSONNull(t *testing.T) {
+	sch, err := schema.FromJSON("null")
 	if err != nil {
 		t.Fatal(err)
 	}
-	// TODO: Fix this test.
-	fmt.Println("Unmarshalled sch=", sch)
+	if sch == nil || sch.Tables == nil {
+		t.Fatal("expected an empty schema rather than nil")
+	}
 }
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/schema"
+	"github.com/rbastic/dyndao/schema/test/mock"
+)
+
+func TestLoadJSONFile(t *testing.T) {
+	buf, err := mock.NestedSchema().ToJSONBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, buf, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sch, err := schema.LoadJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	people := sch.GetTable(mock.PeopleObjectType)
+	if people == nil || people.Primary != "PersonID" || people.GetColumn("Name") == nil {
+		t.Fatal("unexpected people table", people)
+	}
+	if _, ok := people.Children[mock.AddressesObjectType]; !ok {
+		t.Fatal("expected people to keep its addresses child")
+	}
+
+	if _, err := schema.LoadJSONFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
+
+func TestLoadJSONErrors(t *testing.T) {
+	cases := map[string]string{
+		`{"Tables": {}}`: "no Tables",
+		`{"Tables": {"people": {"Name": "people", "Columns": {"PersonID": {"Name": "PersonID"}}}}}`: "table people has no Primary",
+		`{"Tables": {"people": {"Name": "people", "Primary": "PersonID"}}}`:                         "table people has no Columns",
+		`{"Tables": {"people": {"Primary": "PersonID", "Columns": {"Name": {"Name": "Name"}}}}}`:    "Primary PersonID is not in Columns",
+		`{"Tables": [}`: "LoadJSON",
+	}
+	for doc, expected := range cases {
+		_, err := schema.LoadJSON(strings.NewReader(doc))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected an error containing %q, got %v", doc, expected, err)
+		}
+	}
+}
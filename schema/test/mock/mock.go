@@ -125,6 +125,8 @@ func addressTable() *schema.Table {
 
 	tbl.EssentialColumns = []string{"AddressID", "PersonID", "Address1", "Address2", "City", "State", "Zip"}
 
+	tbl.Indexes = []schema.Index{{Name: "addresses_PersonID_idx", Columns: []string{"PersonID"}}}
+
 	tbl.ParentTables = []string{"people"}
 	return tbl
 }
@@ -174,3 +176,254 @@ func DefaultPersonWithAddress() *object.Object {
 	return obj
 }
 
+const WidgetsObjectType string = "widgets"
+
+// Widget table, used for exercising column constraints
+func widgetsTable() *schema.Table {
+	tbl := schema.DefaultTable()
+	tbl.Name = "widgets"
+	tbl.Primary = "WidgetID"
+	tbl.Columns["WidgetID"] = primaryColumn("WidgetID")
+
+	age := fkColumn("Age")
+	age.Check = "Age >= 0"
+	tbl.Columns["Age"] = age
+
+	color := schema.DefaultColumn()
+	color.Name = "Color"
+	color.DBType = "varchar"
+	color.Length = 30
+	color.DefaultValue = "blue"
+	tbl.Columns["Color"] = color
+
+	created := schema.DefaultColumn()
+	created.Name = "Created"
+	created.DBType = "datetime"
+	created.AllowNull = true
+	tbl.Columns["Created"] = created
+
+	active := schema.DefaultColumn()
+	active.Name = "Active"
+	active.LogicalType = schema.LogicalBool
+	active.AllowNull = true
+	tbl.Columns["Active"] = active
+
+	price := schema.DefaultColumn()
+	price.Name = "Price"
+	price.LogicalType = schema.LogicalDecimal
+	price.Precision = 10
+	price.Scale = 2
+	price.AllowNull = true
+	tbl.Columns["Price"] = price
+
+	nextAge := schema.DefaultColumn()
+	nextAge.Name = "NextAge"
+	nextAge.LogicalType = schema.LogicalInt
+	nextAge.Generated = "Age + 1"
+	nextAge.AllowNull = true
+	tbl.Columns["NextAge"] = nextAge
+
+	tbl.EssentialColumns = []string{"WidgetID", "Age", "Color", "Created", "Active", "NextAge"}
+	return tbl
+}
+
+const GadgetsObjectType string = "gadgets"
+
+// Gadget table, which uses a UUID primary key
+func gadgetsTable() *schema.Table {
+	tbl := schema.DefaultTable()
+	tbl.Name = "gadgets"
+	tbl.Primary = "GadgetID"
+
+	id := schema.DefaultColumn()
+	id.Name = "GadgetID"
+	id.DBType = "varchar"
+	id.Length = 36
+	id.IsUUID = true
+	tbl.Columns["GadgetID"] = id
+
+	name := schema.DefaultColumn()
+	name.Name = "Name"
+	name.DBType = "varchar"
+	name.Length = 30
+	tbl.Columns["Name"] = name
+
+	tbl.EssentialColumns = []string{"GadgetID", "Name"}
+	return tbl
+}
+
+const PartsObjectType string = "parts"
+
+// Part table, which has a two-column primary key supplied by the caller
+func partsTable() *schema.Table {
+	tbl := schema.DefaultTable()
+	tbl.Name = "parts"
+	tbl.MultiKey = true
+	tbl.CallerSuppliesPK = true
+	tbl.Primary = "PartNo"
+	tbl.ForeignKeys = []string{"Revision"}
+
+	tbl.Columns["PartNo"] = fkColumn("PartNo")
+	tbl.Columns["Revision"] = fkColumn("Revision")
+
+	desc := schema.DefaultColumn()
+	desc.Name = "Description"
+	desc.DBType = "varchar"
+	desc.Length = 30
+	tbl.Columns["Description"] = desc
+
+	tbl.EssentialColumns = []string{"PartNo", "Revision", "Description"}
+
+	docs := schema.DefaultChildTable()
+	docs.MultiKey = true
+	docs.LocalColumns = []string{"PartNumber", "PartRevision"}
+	docs.ForeignColumns = []string{"PartNo", "Revision"}
+	tbl.Children["partdocs"] = docs
+	return tbl
+}
+
+const PartDocsObjectType string = "partdocs"
+
+// Part document table, a child of parts through a composite foreign key
+// whose columns are named differently from the parent's
+func partDocsTable() *schema.Table {
+	tbl := schema.DefaultTable()
+	tbl.Name = "partdocs"
+	tbl.Primary = "DocID"
+	tbl.ParentTables = []string{"parts"}
+
+	tbl.Columns["DocID"] = primaryColumn("DocID")
+	tbl.Columns["PartNumber"] = fkColumn("PartNumber")
+	tbl.Columns["PartRevision"] = fkColumn("PartRevision")
+	tbl.Columns["Title"] = titleColumn("Title")
+	return tbl
+}
+
+// WidgetSchema is the mock for standalone tables that exercise column
+// constraints and primary key generation
+func WidgetSchema() *schema.Schema {
+	sch := schema.DefaultSchema()
+	sch.Tables["widgets"] = widgetsTable()
+	sch.Tables["gadgets"] = gadgetsTable()
+	sch.Tables["parts"] = partsTable()
+	sch.Tables["partdocs"] = partDocsTable()
+	return sch
+}
+
+const LibrariesObjectType string = "libraries"
+const ShelvesObjectType string = "shelves"
+const BooksObjectType string = "books"
+const MembersObjectType string = "members"
+
+// titleColumn uses a logical type, leaving the column type to the dialect
+func titleColumn(name string) *schema.Column {
+	fld := schema.DefaultColumn()
+	fld.Name = name
+	fld.LogicalType = schema.LogicalString
+	fld.Length = 30
+	return fld
+}
+
+// LibrarySchema is the mock for a three level hierarchy: libraries have
+// shelves, which have books. Libraries also have members, who may have been
+// referred by another member.
+func LibrarySchema() *schema.Schema {
+	sch := schema.DefaultSchema()
+
+	libraries := schema.DefaultTable()
+	libraries.Name = "libraries"
+	libraries.Primary = "LibraryID"
+	libraries.Columns["LibraryID"] = primaryColumn("LibraryID")
+	libraries.Columns["Name"] = titleColumn("Name")
+	libraries.EssentialColumns = []string{"LibraryID", "Name"}
+	libraries.Children["shelves"] = schema.DefaultChildTable()
+	libraries.Children["members"] = schema.DefaultChildTable()
+	sch.Tables["libraries"] = libraries
+
+	shelves := schema.DefaultTable()
+	shelves.Name = "shelves"
+	shelves.Primary = "ShelfID"
+	shelves.Columns["ShelfID"] = primaryColumn("ShelfID")
+	shelves.Columns["LibraryID"] = fkColumn("LibraryID")
+	shelves.Columns["Label"] = titleColumn("Label")
+	shelves.EssentialColumns = []string{"ShelfID", "LibraryID", "Label"}
+	shelves.Children["books"] = schema.DefaultChildTable()
+	sch.Tables["shelves"] = shelves
+
+	books := schema.DefaultTable()
+	books.Name = "books"
+	books.Primary = "BookID"
+	books.Columns["BookID"] = primaryColumn("BookID")
+	books.Columns["ShelfID"] = fkColumn("ShelfID")
+	books.Columns["Title"] = titleColumn("Title")
+	books.EssentialColumns = []string{"BookID", "ShelfID", "Title"}
+	sch.Tables["books"] = books
+
+	members := schema.DefaultTable()
+	members.Name = "members"
+	members.Primary = "MemberID"
+	members.Columns["MemberID"] = primaryColumn("MemberID")
+	members.Columns["LibraryID"] = fkColumn("LibraryID")
+	members.Columns["Name"] = titleColumn("Name")
+	referrer := fkColumn("ReferrerID")
+	referrer.AllowNull = true
+	members.Columns["ReferrerID"] = referrer
+	members.EssentialColumns = []string{"MemberID", "LibraryID", "Name", "ReferrerID"}
+	referrals := schema.DefaultChildTable()
+	referrals.LocalColumn = "ReferrerID"
+	referrals.ForeignColumn = "MemberID"
+	members.Children["members"] = referrals
+	sch.Tables["members"] = members
+
+	return sch
+}
+
+const ProjectsObjectType string = "projects"
+const TasksObjectType string = "tasks"
+const MilestonesObjectType string = "milestones"
+const DeliverablesObjectType string = "deliverables"
+
+// DiamondSchema is the mock for a diamond-shaped hierarchy: projects have
+// tasks and milestones, and deliverables belong to both a task and a
+// milestone.
+func DiamondSchema() *schema.Schema {
+	sch := schema.DefaultSchema()
+
+	projects := schema.DefaultTable()
+	projects.Name = "projects"
+	projects.Primary = "ProjectID"
+	projects.Columns["ProjectID"] = primaryColumn("ProjectID")
+	projects.Columns["Name"] = titleColumn("Name")
+	projects.Children["tasks"] = schema.DefaultChildTable()
+	projects.Children["milestones"] = schema.DefaultChildTable()
+	sch.Tables["projects"] = projects
+
+	tasks := schema.DefaultTable()
+	tasks.Name = "tasks"
+	tasks.Primary = "TaskID"
+	tasks.Columns["TaskID"] = primaryColumn("TaskID")
+	tasks.Columns["ProjectID"] = fkColumn("ProjectID")
+	tasks.Columns["Name"] = titleColumn("Name")
+	tasks.Children["deliverables"] = schema.DefaultChildTable()
+	sch.Tables["tasks"] = tasks
+
+	milestones := schema.DefaultTable()
+	milestones.Name = "milestones"
+	milestones.Primary = "MilestoneID"
+	milestones.Columns["MilestoneID"] = primaryColumn("MilestoneID")
+	milestones.Columns["ProjectID"] = fkColumn("ProjectID")
+	milestones.Columns["Name"] = titleColumn("Name")
+	milestones.Children["deliverables"] = schema.DefaultChildTable()
+	sch.Tables["milestones"] = milestones
+
+	deliverables := schema.DefaultTable()
+	deliverables.Name = "deliverables"
+	deliverables.Primary = "DeliverableID"
+	deliverables.Columns["DeliverableID"] = primaryColumn("DeliverableID")
+	deliverables.Columns["TaskID"] = fkColumn("TaskID")
+	deliverables.Columns["MilestoneID"] = fkColumn("MilestoneID")
+	deliverables.Columns["Name"] = titleColumn("Name")
+	sch.Tables["deliverables"] = deliverables
+
+	return sch
+}
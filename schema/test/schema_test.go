@@ -1,9 +1,13 @@
 package test
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/rbastic/dyndao/schema"
 	"github.com/rbastic/dyndao/schema/test/mock"
+	"math"
+	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -30,3 +34,319 @@ func TestDefaultChildTable(t *testing.T) {
 func TestSchemaBasic(t *testing.T) {
 	_ = mock.BasicSchema()
 }
+
+func TestMatchColumnName(t *testing.T) {
+	people := mock.BasicSchema().GetTable(mock.PeopleObjectType)
+	people.ColumnAliases = map[string]string{"Nick": "Name"}
+	for name, expected := range map[string]string{
+		"PersonID": "PersonID",
+		"PERSONID": "PersonID",
+		"personid": "PersonID",
+		"Nick":     "Name",
+		"NICK":     "Name",
+	} {
+		if got, ok := people.MatchColumnName(name); !ok || got != expected {
+			t.Fatalf("expected %s to match %s, got %s", name, expected, got)
+		}
+	}
+	if _, ok := people.MatchColumnName("Nope"); ok {
+		t.Fatal("expected an unknown column not to match")
+	}
+}
+
+func TestTableCreationOrder(t *testing.T) {
+	sch := mock.NestedSchema()
+	order, err := sch.TableCreationOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 2 || order[0] != "people" || order[1] != "addresses" {
+		t.Fatal("unexpected table creation order", order)
+	}
+
+	// A parent that lists itself as a child of its own child is a cycle.
+	sch.Tables["addresses"].Children["people"] = schema.DefaultChildTable()
+	_, err = sch.TableCreationOrder()
+	if err == nil {
+		t.Fatal("expected an error for a cyclic schema")
+	}
+}
+
+func TestTableDropOrder(t *testing.T) {
+	order, err := mock.LibrarySchema().TableDropOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos := make(map[string]int)
+	for i, name := range order {
+		pos[name] = i
+	}
+	if len(order) != 4 || pos["books"] > pos["shelves"] || pos["shelves"] > pos["libraries"] || pos["members"] > pos["libraries"] {
+		t.Fatal("expected children to be dropped before their parents", order)
+	}
+}
+
+func TestDefaultColumns(t *testing.T) {
+	widgets := mock.WidgetSchema().GetTable(mock.WidgetsObjectType)
+	if cols := widgets.DefaultColumns(); strings.Join(cols, ",") != strings.Join(widgets.EssentialColumns, ",") {
+		t.Fatal("expected the EssentialColumns, got", cols)
+	}
+
+	widgets.EssentialColumns = nil
+	cols := widgets.DefaultColumns()
+	if strings.Join(cols, ",") != "Active,Age,Color,Created,NextAge,Price,WidgetID" {
+		t.Fatal("expected every column, sorted, got", cols)
+	}
+}
+
+func TestColumnOrder(t *testing.T) {
+	widgets := mock.WidgetSchema().GetTable(mock.WidgetsObjectType)
+	widgets.ColumnOrder = []string{"WidgetID", "Price", "Color"}
+	if cols := widgets.ColumnNames(); strings.Join(cols, ",") != "WidgetID,Price,Color,Active,Age,Created,NextAge" {
+		t.Fatal("expected ColumnOrder, then the other columns sorted, got", cols)
+	}
+	if cols := widgets.DefaultColumns(); strings.Join(cols, ",") != "WidgetID,Color,Active,Age,Created,NextAge" {
+		t.Fatal("expected the EssentialColumns in ColumnOrder, got", cols)
+	}
+
+	names := []string{"Age", "Nowhere", "Color", "WidgetID"}
+	widgets.SortColumns(names)
+	if strings.Join(names, ",") != "WidgetID,Color,Age,Nowhere" {
+		t.Fatal("expected names in column order, unknown names last, got", names)
+	}
+
+	widgets.ColumnOrder = append(widgets.ColumnOrder, "Missing")
+	sch := mock.WidgetSchema()
+	sch.Tables[mock.WidgetsObjectType] = widgets
+	if err := sch.Validate(); err == nil || !strings.Contains(err.Error(), "ColumnOrder entry Missing") {
+		t.Fatal("expected an unknown ColumnOrder entry to be reported, got", err)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	for name, sch := range map[string]*schema.Schema{
+		"nested":  mock.NestedSchema(),
+		"widgets": mock.WidgetSchema(),
+		"library": mock.LibrarySchema(),
+	} {
+		if err := sch.Validate(); err != nil {
+			t.Errorf("%s: unexpected error %s", name, err.Error())
+		}
+	}
+
+	sch := mock.NestedSchema()
+	people := sch.Tables["people"]
+	people.Primary = "NoSuchColumn"
+	people.ParentTables = []string{"nowhere"}
+	people.Children["ghosts"] = schema.DefaultChildTable()
+	people.Children["addresses"].LocalColumn = "PersonID"
+	people.Children["addresses"].ForeignColumn = "Missing"
+	sch.Tables["addresses"].ForeignKeys = []string{"Gone"}
+
+	err := sch.Validate()
+	verrs, ok := err.(schema.ValidationErrors)
+	if !ok {
+		t.Fatal("expected ValidationErrors, got", err)
+	}
+	expected := []string{
+		"ForeignKeys entry Gone",
+		"Primary NoSuchColumn",
+		"ParentTables entry nowhere",
+		"foreign column Missing",
+		"child ghosts is not a table",
+	}
+	if len(verrs) != len(expected) {
+		t.Fatal("expected", len(expected), "errors, got", verrs)
+	}
+	for _, e := range expected {
+		if !strings.Contains(err.Error(), e) {
+			t.Errorf("expected the errors to mention %q: %s", e, err.Error())
+		}
+	}
+}
+
+func TestValidateFunction(t *testing.T) {
+	sch := mock.WidgetSchema()
+	if err := schema.Validate(sch); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	sch.Tables["gadgets"].Name = ""
+	sch.Tables["gadgets"].Primary = "NoSuchColumn"
+	err := schema.Validate(sch)
+	if err == nil || err.Error() != sch.Validate().Error() {
+		t.Fatal("expected schema.Validate to report what the method does, got", err)
+	}
+	if !strings.Contains(err.Error(), "table gadgets has an empty Name") {
+		t.Fatal("expected the empty Name to be reported, got", err)
+	}
+}
+
+func TestRemoveColumn(t *testing.T) {
+	sch := mock.WidgetSchema()
+	docs := sch.Tables[mock.PartDocsObjectType]
+	if refs := sch.ColumnReferences(mock.PartDocsObjectType, "PartRevision"); len(refs) != 1 || refs[0] != "a key column of child partdocs of table parts" {
+		t.Fatal("expected the child key column to be referenced by parts, got", refs)
+	}
+	if refs := sch.ColumnReferences("parts", "Revision"); len(refs) != 3 {
+		t.Fatal("expected the parent key column to be referenced as a key of parts and of its child, got", refs)
+	}
+	if refs := sch.ColumnReferences(mock.PartDocsObjectType, "Title"); refs != nil {
+		t.Fatal("expected Title not to be referenced, got", refs)
+	}
+	if err := docs.RemoveColumn("Title"); err != nil {
+		t.Fatal(err)
+	}
+	if docs.GetColumn("Title") != nil {
+		t.Fatal("expected Title to be removed")
+	}
+
+	gadgets := sch.Tables["gadgets"]
+	gadgets.EssentialColumns = []string{"Name"}
+	gadgets.Indexes = []schema.Index{{Name: "gadgets_Name_idx", Columns: []string{"Name"}}}
+	err := gadgets.RemoveColumn("Name")
+	if err == nil || !strings.Contains(err.Error(), "index gadgets_Name_idx") || !strings.Contains(err.Error(), "the only EssentialColumns entry") {
+		t.Fatal("expected the indexed, only essential column to be refused, got", err)
+	}
+	if gadgets.GetColumn("Name") == nil || len(gadgets.EssentialColumns) != 1 {
+		t.Fatal("expected a refused removal to leave the table unchanged")
+	}
+	if err := gadgets.RemoveColumn("GadgetID"); err == nil {
+		t.Fatal("expected the primary key to be refused")
+	}
+}
+
+func TestColumnAliases(t *testing.T) {
+	tbl := mock.WidgetSchema().Tables["gadgets"]
+	tbl.ColumnAliases = map[string]string{"Title": "Name", "Label": "Name"}
+
+	if tbl.GetColumnName("Title") != "Name" || tbl.GetColumn("Title") == nil {
+		t.Fatal("expected Title to resolve to Name")
+	}
+	if alias := tbl.GetColumnAlias("Name"); alias != "Label" {
+		t.Fatal("expected the alphabetically first alias, got", alias)
+	}
+	if alias := tbl.GetColumnAlias("GadgetID"); alias != "GadgetID" {
+		t.Fatal("expected an unaliased column to keep its name, got", alias)
+	}
+}
+
+func TestSchemaValidateLogicalTypes(t *testing.T) {
+	sch := mock.LibrarySchema()
+	books := sch.Tables["books"]
+	books.Columns["Title"].LogicalType = "money"
+	books.Columns["ShelfID"].DBType = ""
+
+	err := sch.Validate()
+	if err == nil {
+		t.Fatal("expected errors for the column types")
+	}
+	for _, e := range []string{"unknown LogicalType money", "ShelfID has neither a DBType nor a LogicalType"} {
+		if !strings.Contains(err.Error(), e) {
+			t.Errorf("expected the errors to mention %q: %s", e, err.Error())
+		}
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	price := mock.WidgetSchema().Tables["widgets"].Columns["Price"]
+	for _, tc := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{19.99, "19.99"},
+		{big.NewRat(1999, 100), "19.99"},
+		{"0.1", "0.10"},
+		{int64(5), "5.00"},
+		{int32(5), "5.00"},
+		{uint(7), "7.00"},
+		{uint32(8), "8.00"},
+		{"1e2", "100.00"},
+		{sql.NullString{String: "19.99", Valid: true}, "19.99"},
+		{sql.NullFloat64{Float64: 19.99, Valid: true}, "19.99"},
+	} {
+		d, ok, err := price.DecimalString(tc.value)
+		if !ok || err != nil {
+			t.Fatalf("unexpected result for %v: %v %v", tc.value, ok, err)
+		}
+		if d != tc.expected {
+			t.Errorf("expected %v to render as %s, got %s", tc.value, tc.expected, d)
+		}
+	}
+
+	if _, ok, err := price.DecimalString("nineteen"); !ok || err == nil {
+		t.Fatal("expected an error for an invalid decimal string")
+	}
+	if _, ok, _ := price.DecimalString(true); ok {
+		t.Fatal("expected a bool not to be handled as a decimal")
+	}
+	if _, ok, _ := price.DecimalString(sql.NullFloat64{}); ok {
+		t.Fatal("expected an invalid sql.NullFloat64 to be left as NULL")
+	}
+}
+
+func TestUint64Value(t *testing.T) {
+	age := mock.WidgetSchema().Tables["widgets"].Columns["Age"]
+	large := uint64(math.MaxInt64) + 1
+	if v, err := age.Uint64Value(7); err != nil || v != int64(7) {
+		t.Fatalf("expected an int64, got %#v %v", v, err)
+	}
+	if _, err := age.Uint64Value(large); err == nil {
+		t.Fatal("expected an error for a value above math.MaxInt64 in an integer column")
+	}
+	age.DBType = "NUMBER(20)"
+	if v, err := age.Uint64Value(large); err != nil || v != "9223372036854775808" {
+		t.Fatalf("expected a decimal string for a NUMBER column, got %#v %v", v, err)
+	}
+}
+
+func TestSchemaEqual(t *testing.T) {
+	sch := mock.NestedSchema()
+	if ok, diffs := sch.Equal(mock.NestedSchema()); !ok {
+		t.Fatal("expected identical schemas to be equal", diffs)
+	}
+
+	jsonStr, err := sch.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := schema.FromJSON(jsonStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, diffs := sch.Equal(loaded); !ok {
+		t.Fatal("expected a schema to equal its JSON round-trip", diffs)
+	}
+
+	other := mock.NestedSchema()
+	other.Tables["people"].Columns["Name"].DBType = "varchar"
+	other.Tables["people"].EssentialColumns = nil
+	delete(other.Tables["addresses"].Columns, "Zip")
+	other.Tables["widgets"] = schema.DefaultTable()
+
+	ok, diffs := sch.Equal(other)
+	if ok {
+		t.Fatal("expected the schemas to differ")
+	}
+	expected := []string{
+		"table addresses: column Zip is missing from other",
+		"table people: column Name: DBType text != varchar",
+		"table people: EssentialColumns [PersonID Name NullText NullInt NullVarchar NullBlob] != []",
+		"table widgets is only in other",
+	}
+	if strings.Join(diffs, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected differences:\n%s", strings.Join(diffs, "\n"))
+	}
+}
+
+func TestSchemaValidateAllowedValues(t *testing.T) {
+	sch := mock.WidgetSchema()
+	color := sch.Tables["widgets"].Columns["Color"]
+	color.AllowedValues = []string{"red", "blue"}
+	if err := sch.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	color.AllowedValues = []string{"red", "green"}
+	if err := sch.Validate(); err == nil || !strings.Contains(err.Error(), "DefaultValue blue is not one of its AllowedValues") {
+		t.Fatal("expected a DefaultValue outside AllowedValues to be reported, got", err)
+	}
+}
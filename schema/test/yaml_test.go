@@ -0,0 +1,69 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
+const yamlSchema = `
+Tables:
+  people:
+    Name: people
+    Primary: PersonID
+    EssentialColumns: [PersonID, Name]
+    Columns:
+      PersonID:
+        Name: PersonID
+        DBType: integer
+        IsNumber: true
+        IsIdentity: true
+      Name:
+        Name: Name
+        DBType: varchar
+        Length: 30
+    Children:
+      addresses:
+        LocalColumn: PersonID
+        ForeignColumn: PersonID
+  addresses:
+    Name: addresses
+    Primary: AddressID
+    EssentialColumns: [AddressID, PersonID]
+    Columns:
+      AddressID:
+        Name: AddressID
+        DBType: integer
+        IsIdentity: true
+      PersonID:
+        Name: PersonID
+        DBType: integer
+`
+
+func TestLoadYAML(t *testing.T) {
+	sch, err := schema.LoadYAML(strings.NewReader(yamlSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	people := sch.GetTable("people")
+	if people == nil || people.Primary != "PersonID" || len(people.EssentialColumns) != 2 {
+		t.Fatal("unexpected people table", people)
+	}
+	col := people.GetColumn("Name")
+	if col == nil || col.Length != 30 || col.DBType != "varchar" {
+		t.Fatal("unexpected Name column", col)
+	}
+	if !people.GetColumn("PersonID").IsIdentity {
+		t.Fatal("expected PersonID to be an identity column")
+	}
+	child := people.Children["addresses"]
+	if child == nil || child.LocalColumn != "PersonID" {
+		t.Fatal("unexpected addresses child", child)
+	}
+
+	_, err = schema.LoadYAML(strings.NewReader("Tables:\n  people:\n    Name: people\n"))
+	if err == nil || !strings.Contains(err.Error(), "table people has no Primary") {
+		t.Fatal("expected the JSON loader's validation to apply, got", err)
+	}
+}
@@ -2,19 +2,29 @@ package schema
 
 // Schema is the metadata container for a schema definition
 type Schema struct {
-	Name   string
+	Name   string            `json:"Name"`
 	Tables map[string]*Table `json:"Tables"`
 	// For get ops
 	TableAliases map[string]string `json:"TableAliases"`
+	// TablePrefix is prepended to every table name in the generated SQL, so
+	// that the same schema can be deployed more than once in a database
+	// (staging_people, prod_people). Code keeps using the unprefixed names.
+	TablePrefix string `json:"TablePrefix"`
+	// SchemaName qualifies every table name in the generated SQL with the
+	// schema (owner) the tables belong to, as in hr.people, so that tables
+	// owned by another schema can be reached. It may name a catalog too, as
+	// in sales.hr. Tables can set their own.
+	SchemaName string `json:"SchemaName"`
 }
 
 // Table is the metadata container for a SQL table definition
 type Table struct {
-	CallerSuppliesPK bool   // Do we use a LastInsertID mechanism or does the caller supply a PK
-	MultiKey         bool   `json:"MultiKey"` // Use Primary or Primary + ForeignKeys
+	CallerSuppliesPK bool   `json:"CallerSuppliesPK"` // Do we use a LastInsertID mechanism or does the caller supply a PK
+	MultiKey         bool   `json:"MultiKey"`         // Use Primary or Primary + ForeignKeys
 	Primary          string `json:"Primary"`
 	Name             string `json:"Name"`
-	AliasName        string `json:"AliasName"` // Combined with AliasName - for set op	s
+	AliasName        string `json:"AliasName"`  // Combined with AliasName - for set ops
+	SchemaName       string `json:"SchemaName"` // Overrides the Schema's SchemaName for this table
 
 	// MultiKey must be set to true if a table has
 	// foreign keys.
@@ -22,14 +32,28 @@ type Table struct {
 	// Columns is the column definitions for the SQL table
 	Columns       map[string]*Column `json:"Columns"`
 	ColumnAliases map[string]string  `json:"ColumnAliases"`
+	// ColumnOrder is the order columns are created, inserted and selected
+	// in. Columns it leaves out follow the listed ones, sorted by name.
+	ColumnOrder []string `json:"ColumnOrder"`
 
+	// EssentialColumns are the columns retrieved by default, so that wide
+	// or heavy columns can be left out of ordinary fetches. When empty,
+	// every column is retrieved. WithAllColumns overrides them per call.
 	EssentialColumns []string `json:"EssentialColumns"`
 
 	ParentTables []string               `json:"ParentTables"`
 	Children     map[string]*ChildTable `json:"Children"`
 
+	// Indexes are created after the table itself
+	Indexes []Index `json:"Indexes"`
+
+	// Checks are table-level CHECK constraint expressions, for conditions
+	// that involve more than a single column. The table's column names are
+	// quoted for the dialect, and the rest is passed through to the database
+	// as-is.
+	Checks []string `json:"Checks"`
+
 	// YAGNI?
-	// TODO: ChildrenInsertionOrder?
 	// TODO: DeletionOrder?
 }
 
@@ -48,12 +72,31 @@ type Column struct {
 	AllowNull    bool   `json:"AllowNull"`
 	IsNumber     bool   `json:"IsNumber"`
 	IsIdentity   bool   `json:"IsIdentity"`
+	IsUUID       bool   `json:"IsUUID"` // Primary key populated with a generated UUID on insert
 	IsForeignKey bool   `json:"IsForeignKey"`
 	IsUnique     bool   `json:"IsUnique"`
 	Length       int    `json:"Length"`
+	Precision    int    `json:"Precision"` // Total digits of a decimal column
+	Scale        int    `json:"Scale"`     // Digits after the decimal point of a decimal column
 	Name         string `json:"Name"`
-	DefaultValue string `json:"DefaultValue"` // Converts to integer if IsNumber is set
+	DefaultValue string `json:"DefaultValue"` // Rendered unquoted if IsNumber is set, quoted otherwise
 	DBType       string `json:"DBType"`
+	LogicalType  string `json:"LogicalType"` // Portable type, mapped per dialect when DBType is empty
+	Check        string `json:"Check"`       // CHECK constraint expression, passed through with the column name quoted
+	Generated    string `json:"Generated"`   // expression the database computes the column from, see IsReadOnly
+	ReadOnly     bool   `json:"ReadOnly"`    // populated by the database, never written by the ORM
+
+	// AllowedValues, if set, are the only string values the column
+	// accepts. Validate rejects any others, and the column is created with a
+	// CHECK constraint enforcing them, or as an ENUM in MySQL.
+	AllowedValues []string `json:"AllowedValues"`
+}
+
+// Index represents a single (optionally unique) index on a SQL table
+type Index struct {
+	Name    string   `json:"Name"`
+	Columns []string `json:"Columns"`
+	Unique  bool     `json:"Unique"`
 }
 
 // ChildTable represents a relationship between a parent table
@@ -67,4 +110,8 @@ type ChildTable struct {
 
 	LocalColumns   []string `json:"LocalColumns"`
 	ForeignColumns []string `json:"ForeignColumns"`
+
+	// OnDeleteCascade adds ON DELETE CASCADE to the generated FOREIGN KEY
+	// constraint, so deleting a parent row also deletes these child rows.
+	OnDeleteCascade bool `json:"OnDeleteCascade"`
 }
@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// LoadYAML parses a schema definition written in YAML. The document uses the
+// same field names as the JSON representation (Tables, Primary, Columns,
+// etc.) and is checked the same way as LoadJSON.
+func LoadYAML(r io.Reader) (*Schema, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("dyndao/schema: LoadYAML: %s", err.Error())
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return nil, fmt.Errorf("dyndao/schema: LoadYAML: %s", err.Error())
+	}
+	doc, err = yamlToJSONValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("dyndao/schema: LoadYAML: %s", err.Error())
+	}
+
+	// Round trip through JSON so that the JSON field names and decoding rules
+	// apply unchanged.
+	jsonBuf, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("dyndao/schema: LoadYAML: %s", err.Error())
+	}
+	return LoadJSON(bytes.NewReader(jsonBuf))
+}
+
+// LoadYAMLFile is LoadYAML for the file at path.
+func LoadYAMLFile(path string) (*Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("dyndao/schema: LoadYAMLFile: %s", err.Error())
+	}
+	defer f.Close()
+
+	sch, err := LoadYAML(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s (file %s)", err.Error(), path)
+	}
+	return sch, nil
+}
+
+// yamlToJSONValue converts the map[interface{}]interface{} values produced by
+// the YAML decoder into map[string]interface{}, which encoding/json requires.
+func yamlToJSONValue(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			ks, ok := k.(string)
+			if !ok {
+				ks = fmt.Sprint(k)
+			}
+			cv, err := yamlToJSONValue(val)
+			if err != nil {
+				return nil, err
+			}
+			m[ks] = cv
+		}
+		return m, nil
+	case []interface{}:
+		for i, val := range t {
+			cv, err := yamlToJSONValue(val)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = cv
+		}
+		return t, nil
+	default:
+		return v, nil
+	}
+}
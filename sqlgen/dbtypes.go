@@ -0,0 +1,184 @@
+package sqlgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
+// RegisterDBType teaches the generator a column type, such as a vendor
+// specific NUMBER(10) or VARCHAR2, by the schema logical type whose
+// behaviour it has. Registered types are matched ignoring case, and with or
+// without their parameters: VARCHAR2 matches VARCHAR2(30), while TINYINT(1)
+// can be registered apart from TINYINT. They take precedence over the
+// dialect's own IsStringType, IsNumberType and so on, which decide how
+// values are bound and scanned, and InferColumn uses them to describe
+// introspected columns. Adapters register their defaults in New, so types
+// should be registered after it, and Is*Type functions set afterwards
+// ignore them.
+func (g *SQLGenerator) RegisterDBType(dbType string, logicalType string) error {
+	if !schema.IsLogicalType(logicalType) {
+		return fmt.Errorf("RegisterDBType: unknown logical type %s for %s", logicalType, dbType)
+	}
+	if g.DBTypes == nil {
+		g.DBTypes = make(map[string]string)
+		g.checkDBTypes()
+	}
+	g.DBTypes[normalizeDBType(dbType)] = logicalType
+	return nil
+}
+
+// RegisterDBTypes registers each column type of types, see RegisterDBType.
+func (g *SQLGenerator) RegisterDBTypes(types map[string]string) error {
+	for dbType, logicalType := range types {
+		if err := g.RegisterDBType(dbType, logicalType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisteredLogicalType returns the logical type registered for dbType.
+func (g *SQLGenerator) RegisteredLogicalType(dbType string) (string, bool) {
+	if len(g.DBTypes) == 0 {
+		return "", false
+	}
+	n := normalizeDBType(dbType)
+	if lt, ok := g.DBTypes[n]; ok {
+		return lt, true
+	}
+	lt, ok := g.DBTypes[baseDBType(n)]
+	return lt, ok
+}
+
+// LogicalTypeOf returns the logical type of a column type: the registered
+// one, or else the one the dialect's Is*Type functions tell it to be. It
+// returns "" for types the generator doesn't know.
+func (g *SQLGenerator) LogicalTypeOf(dbType string) string {
+	if lt, ok := g.RegisteredLogicalType(dbType); ok {
+		return lt
+	}
+	for _, k := range []string{dbType, baseDBType(dbType)} {
+		switch {
+		case g.IsDecimalType(k):
+			return schema.LogicalDecimal
+		case g.IsBooleanType(k):
+			return schema.LogicalBool
+		case g.IsNumberType(k):
+			return schema.LogicalInt
+		case g.IsFloatingType(k):
+			return schema.LogicalFloat
+		case g.IsTimestampType(k):
+			return schema.LogicalTimestamp
+		case g.IsBinaryType(k):
+			return schema.LogicalBlob
+		case g.IsStringType(k):
+			return schema.LogicalString
+		case g.IsLOBType(k):
+			return schema.LogicalText
+		}
+	}
+	return ""
+}
+
+// InferColumn fills in what the column's DBType tells of it, for columns
+// read from the database rather than authored: IsNumber for numeric types,
+// the Length of string types and the Precision and Scale of numeric ones,
+// such as NUMBER(10,2). Fields already set are left as they are.
+func (g *SQLGenerator) InferColumn(f *schema.Column) {
+	params := dbTypeParams(f.DBType)
+	switch g.LogicalTypeOf(f.DBType) {
+	case schema.LogicalInt, schema.LogicalFloat, schema.LogicalDecimal:
+		f.IsNumber = true
+		if len(params) > 0 && f.Precision == 0 {
+			f.Precision = params[0]
+		}
+		if len(params) > 1 && f.Scale == 0 {
+			f.Scale = params[1]
+		}
+	case schema.LogicalString:
+		if len(params) > 0 && f.Length == 0 {
+			f.Length = params[0]
+		}
+	}
+}
+
+// InferColumns calls InferColumn for every column of the schema, as loaded
+// by one of the schema parsers.
+func (g *SQLGenerator) InferColumns(sch *schema.Schema) {
+	for _, tbl := range sch.Tables {
+		for _, f := range tbl.Columns {
+			g.InferColumn(f)
+		}
+	}
+}
+
+// checkDBTypes makes the generator's Is*Type functions answer for the
+// registered types.
+func (g *SQLGenerator) checkDBTypes() {
+	check := func(fn func(string) bool, logicalTypes ...string) func(string) bool {
+		return func(k string) bool {
+			lt, ok := g.RegisteredLogicalType(k)
+			if !ok {
+				return fn != nil && fn(k)
+			}
+			for _, t := range logicalTypes {
+				if lt == t {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	g.IsStringType = check(g.IsStringType, schema.LogicalString, schema.LogicalText)
+	g.IsNumberType = check(g.IsNumberType, schema.LogicalInt)
+	g.IsFloatingType = check(g.IsFloatingType, schema.LogicalFloat)
+	g.IsDecimalType = check(g.IsDecimalType, schema.LogicalDecimal)
+	g.IsTimestampType = check(g.IsTimestampType, schema.LogicalTimestamp)
+	g.IsBooleanType = check(g.IsBooleanType, schema.LogicalBool)
+	g.IsBinaryType = check(g.IsBinaryType, schema.LogicalBlob)
+	g.IsLOBType = check(g.IsLOBType, schema.LogicalText, schema.LogicalBlob)
+}
+
+// normalizeDBType upper cases a column type and collapses its spaces.
+func normalizeDBType(dbType string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(dbType)), " ")
+}
+
+// baseDBType strips the parameters from a column type, so that
+// TIMESTAMP(6) WITH TIME ZONE becomes TIMESTAMP WITH TIME ZONE.
+func baseDBType(dbType string) string {
+	open := strings.Index(dbType, "(")
+	if open < 0 {
+		return dbType
+	}
+	end := strings.Index(dbType[open:], ")")
+	if end < 0 {
+		return strings.TrimSpace(dbType[:open])
+	}
+	return strings.Join(strings.Fields(dbType[:open]+" "+dbType[open+end+1:]), " ")
+}
+
+// dbTypeParams returns the numeric parameters of a column type, such as 10
+// and 2 for NUMBER(10,2).
+func dbTypeParams(dbType string) []int {
+	open := strings.Index(dbType, "(")
+	if open < 0 {
+		return nil
+	}
+	end := strings.Index(dbType[open:], ")")
+	if end < 0 {
+		return nil
+	}
+	var params []int
+	for _, p := range strings.Split(dbType[open+1:open+end], ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil
+		}
+		params = append(params, n)
+	}
+	return params
+}
@@ -0,0 +1,68 @@
+package sqlgen
+
+import (
+	"testing"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
+// typeGenerator returns a generator that knows a single number type, INT,
+// and a single string type, TEXT.
+func typeGenerator() *SQLGenerator {
+	none := func(string) bool { return false }
+	return &SQLGenerator{
+		IsStringType:    func(k string) bool { return k == "TEXT" },
+		IsNumberType:    func(k string) bool { return k == "INT" },
+		IsFloatingType:  none,
+		IsTimestampType: none,
+		IsLOBType:       none,
+		IsBooleanType:   none,
+		IsBinaryType:    none,
+		IsDecimalType:   none,
+	}
+}
+
+func TestRegisterDBType(t *testing.T) {
+	g := typeGenerator()
+	if g.IsNumberType("NUMBER(10)") {
+		t.Fatal("expected NUMBER to be unknown before it is registered")
+	}
+	if err := g.RegisterDBType("number", schema.LogicalInt); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RegisterDBType("TEXT", schema.LogicalBool); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RegisterDBType("MONEY", "currency"); err == nil {
+		t.Fatal("expected an unknown logical type to be refused")
+	}
+
+	if !g.IsNumberType("NUMBER(10)") || !g.IsNumberType("Number") || !g.IsNumberType("INT") {
+		t.Fatal("expected the registered type to be a number, along with the dialect's own")
+	}
+	if g.IsStringType("TEXT") || !g.IsBooleanType("TEXT") {
+		t.Fatal("expected a registered type to override the dialect's")
+	}
+	if lt := g.LogicalTypeOf("VARCHAR2"); lt != "" {
+		t.Fatal("expected an unknown type to have no logical type, got", lt)
+	}
+
+	f := schema.DefaultColumn()
+	f.Name = "Price"
+	f.DBType = "NUMBER(10,2)"
+	g.InferColumn(f)
+	if !f.IsNumber || f.Precision != 10 || f.Scale != 2 {
+		t.Fatalf("expected IsNumber, a precision and a scale to be inferred from %s, got %v %d %d", f.DBType, f.IsNumber, f.Precision, f.Scale)
+	}
+
+	if err := g.RegisterDBType("VARCHAR2", schema.LogicalString); err != nil {
+		t.Fatal(err)
+	}
+	f = schema.DefaultColumn()
+	f.Name = "Name"
+	f.DBType = "VARCHAR2(30)"
+	g.InferColumn(f)
+	if f.IsNumber || f.Length != 30 {
+		t.Fatalf("expected the length of %s to be inferred, got %v %d", f.DBType, f.IsNumber, f.Length)
+	}
+}
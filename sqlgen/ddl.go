@@ -0,0 +1,34 @@
+package sqlgen
+
+import (
+	"strings"
+
+	"github.com/rbastic/dyndao/schema"
+)
+
+// RenderSchemaDDL renders the statements creating every table of the schema
+// as CreateTables would run them, without running them: each table's CREATE
+// TABLE, with its keys and constraints, followed by a CREATE INDEX for each
+// of its Indexes, parents before their children.
+func (g *SQLGenerator) RenderSchemaDDL(sch *schema.Schema) ([]string, error) {
+	tableNames, err := sch.TableCreationOrder()
+	if err != nil {
+		return nil, err
+	}
+	var stmts []string
+	for _, tName := range tableNames {
+		sqlStr, err := g.CreateTable(g, sch, tName)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, strings.TrimSpace(sqlStr))
+		for _, idx := range sch.GetTable(tName).Indexes {
+			sqlStr, err := g.CreateIndex(g, sch, tName, idx)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, sqlStr)
+		}
+	}
+	return stmts, nil
+}
@@ -0,0 +1,12 @@
+package sqlgen
+
+import "strings"
+
+// LastInsertIDBind returns the placeholder, and the bind name, of the
+// RETURNING ... INTO bind that receives the generated key of an INSERT of n
+// columns, for dialects with FixLastInsertIDbug. It is numbered after the
+// values, so that positional placeholders don't repeat.
+func (g *SQLGenerator) LastInsertIDBind(n int, identityCol string) (string, string) {
+	placeholder := g.Placeholder(n, identityCol)
+	return placeholder, strings.TrimLeft(placeholder, ":@")
+}
@@ -4,33 +4,83 @@ import (
 	"database/sql"
 
 	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
 	"github.com/rbastic/dyndao/schema"
 )
 
 type FnBindingInsert func(g *SQLGenerator, sch *schema.Schema, table string, data map[string]interface{}) (string, []interface{}, error)
 type FnBindingUpdate func(g *SQLGenerator, sch *schema.Schema, obj *object.Object) (string, []interface{}, []interface{}, error)
 type FnBindingRetrieve func(g *SQLGenerator, sch *schema.Schema, obj *object.Object) (string, []string, []interface{}, error)
+type FnBindingRetrieveColumns func(g *SQLGenerator, sch *schema.Schema, obj *object.Object, columns []string) (string, []string, []interface{}, error)
+type FnBindingExists func(g *SQLGenerator, sch *schema.Schema, obj *object.Object) (string, []interface{}, error)
+type FnBindingAggregate func(g *SQLGenerator, sch *schema.Schema, table string, groupCols []string, aggs []query.Aggregate, having []query.Having) (string, []string, []interface{}, error)
+type FnBindingRetrieveJoined func(g *SQLGenerator, sch *schema.Schema, obj *object.Object, child string) (string, []JoinColumn, []interface{}, error)
+type FnBindingQuery func(g *SQLGenerator, sch *schema.Schema, q *query.Query) (string, []string, []interface{}, error)
 type FnBindingDelete func(g *SQLGenerator, sch *schema.Schema, obj *object.Object) (string, []interface{}, error)
+type FnBindingDeleteReturning func(g *SQLGenerator, sch *schema.Schema, obj *object.Object, columns []string) (string, []string, []interface{}, error)
+type FnDeleteReturningSQL func(tableName string, whereClause string, columnNames []string) string
 type FnCreateTable func(g *SQLGenerator, sch *schema.Schema, table string) (string, error)
+type FnCreateIndex func(g *SQLGenerator, sch *schema.Schema, table string, idx schema.Index) (string, error)
+type FnAddColumn func(g *SQLGenerator, sch *schema.Schema, table string, f *schema.Column) (string, error)
+type FnDropColumn func(g *SQLGenerator, sch *schema.Schema, table string, column string) (string, error)
 type FnDropTable func(name string) string
+type FnBindingTableExists func(g *SQLGenerator, name string) (string, []interface{})
+type FnTruncate func(name string, cascade bool) (string, error)
+type FnSavepoint func(name string) string
+type FnPlaceholder func(index int, name string) string
 type FnRenderBindingValue func(f *schema.Column) string
 type FnRenderBindingValueWithInt func(f *schema.Column, i int64) string
+type FnQuoteIdentifier func(name string) string
+type FnQuoteLiteral func(s string) string
+type FnIsRetryable func(err error) bool
+type FnRenderLimit func(hasOrderBy bool, limit int64, offset int64) string
 type FnRenderInsertValue func(f *schema.Column, value interface{}) (interface{}, error)
 type FnIsStringType func(string) bool
 type FnIsNumberType func(string) bool
 type FnIsFloatingType func(string) bool
 type FnIsTimestampType func(string) bool
 type FnIsLOBType func(string) bool
+type FnIsBooleanType func(string) bool
+type FnIsBinaryType func(string) bool
+type FnIsDecimalType func(string) bool
 type FnDynamicObjectSetter func(g *SQLGenerator, columnNames []string, columnPointers []interface{}, columnTypes []*sql.ColumnType, obj *object.Object) error
 type FnMakeColumnPointers func(g *SQLGenerator, sliceLen int, columnTypes []*sql.ColumnType) ([]interface{}, error)
 
 type FnRenderWhereClause func(g *SQLGenerator, schTable *schema.Table, obj *object.Object) (string, []interface{}, error)
 type FnRenderUpdateWhereClause func(g *SQLGenerator, schTable *schema.Table, fieldsMap map[string]*schema.Column, obj *object.Object) (string, []interface{}, error)
 
-type FnCoreBindingInsert func(g *SQLGenerator, schTable *schema.Table, data map[string]interface{}, identityCol string, fieldsMap map[string]*schema.Column) ([]string, []string, []interface{})
+type FnCoreBindingInsert func(g *SQLGenerator, schTable *schema.Table, data map[string]interface{}, identityCol string, fieldsMap map[string]*schema.Column) ([]string, []string, []interface{}, error)
 
 type FnRenderCreateColumn func(g *SQLGenerator, f *schema.Column) string
+type FnColumnDBType func(g *SQLGenerator, f *schema.Column) string
 type FnBindingInsertSQL func(schTable *schema.Table, tableName string, colNames []string, bindNames []string, identityCol string) string
+type FnBindingBulkInsert func(g *SQLGenerator, sch *schema.Schema, table string, rows []map[string]interface{}) (string, []interface{}, error)
+type FnBindingBulkInsertSQL func(schTable *schema.Table, tableName string, colNames []string, rowBindNames [][]string, identityCol string) string
+type FnBindingBulkUpdate func(g *SQLGenerator, sch *schema.Schema, table string, keyCols []string, setCols []string, rows []map[string]interface{}) (*BulkUpdate, error)
+type FnBulkUpdateSQL func(g *SQLGenerator, schTable *schema.Table, tableName string, tempName string, keyCols []string, setCols []string) *BulkUpdate
+
+// BulkUpdate is an update of many rows through a temporary table: Create
+// creates TempTable, Load fills it with the new values, Update copies them
+// into the table, joining on the key columns, and Drop drops TempTable.
+type BulkUpdate struct {
+	TempTable string
+	Create    string
+	Load      string
+	LoadArgs  []interface{}
+	Update    string
+	Drop      string
+}
+
+// JoinColumn describes a single column selected by BindingRetrieveJoined:
+// the schema table it belongs to, the real column name, the alias it is
+// selected as and whether it belongs to the joined child rather than the
+// root, which may be the same table.
+type JoinColumn struct {
+	Table  string
+	Column string
+	Alias  string
+	Child  bool
+}
 
 // SQLGenerator is the 'vtable struct' that an ORM expects a SQL string
 // generator to support.  While this does add an extra layer of indirection at
@@ -39,16 +89,40 @@ type FnBindingInsertSQL func(schTable *schema.Table, tableName string, colNames
 type SQLGenerator struct {
 	Tracing                   bool
 	FixLastInsertIDbug        bool
+	InsertOutputsPK           bool
+	LogicalTypes              map[string]string // schema logical type -> column type for this dialect
+	DBTypes                   map[string]string // column type -> schema logical type, see RegisterDBType
 	BindingInsert             FnBindingInsert
 	BindingUpdate             FnBindingUpdate
 	BindingRetrieve           FnBindingRetrieve
+	BindingRetrieveColumns    FnBindingRetrieveColumns
+	BindingRetrieveJoined     FnBindingRetrieveJoined
+	BindingQuery              FnBindingQuery
+	BindingExists             FnBindingExists
+	BindingAggregate          FnBindingAggregate
 	BindingDelete             FnBindingDelete
+	BindingDeleteReturning    FnBindingDeleteReturning // DELETE returning the deleted rows' columns, "" when the dialect cannot
+	DeleteReturningSQL        FnDeleteReturningSQL     // may render "", see BindingDeleteReturning
 	CreateTable               FnCreateTable
+	CreateIndex               FnCreateIndex
+	AddColumn                 FnAddColumn  // ALTER TABLE statement adding the column f
+	DropColumn                FnDropColumn // ALTER TABLE statement dropping the column
 	RenderCreateColumn        FnRenderCreateColumn
+	ColumnDBType              FnColumnDBType
 	DropTable                 FnDropTable
+	BindingTableExists        FnBindingTableExists // query returning a row if the table exists
+	Truncate                  FnTruncate
+	Savepoint                 FnSavepoint // statement creating the named savepoint
+	RollbackToSavepoint       FnSavepoint
+	ReleaseSavepoint          FnSavepoint   // may render "" for dialects that don't release savepoints
+	Placeholder               FnPlaceholder // bind placeholder for the index'th (0-based) bind argument of a statement, named name
 	RenderBindingValue        FnRenderBindingValue
 	RenderBindingValueWithInt FnRenderBindingValueWithInt
 	RenderInsertValue         FnRenderInsertValue
+	RenderLimit               FnRenderLimit
+	QuoteIdentifier           FnQuoteIdentifier
+	QuoteLiteral              FnQuoteLiteral // renders s as a string literal, escaped for the dialect
+	IsRetryable               FnIsRetryable  // reports whether a transaction that failed with err may succeed if run again
 
 	IsStringType FnIsStringType
 
@@ -56,6 +130,9 @@ type SQLGenerator struct {
 	IsFloatingType  FnIsFloatingType
 	IsTimestampType FnIsTimestampType
 	IsLOBType       FnIsLOBType
+	IsBooleanType   FnIsBooleanType
+	IsBinaryType    FnIsBinaryType
+	IsDecimalType   FnIsDecimalType
 
 	DynamicObjectSetter FnDynamicObjectSetter
 	MakeColumnPointers  FnMakeColumnPointers
@@ -64,4 +141,14 @@ type SQLGenerator struct {
 	RenderUpdateWhereClause FnRenderUpdateWhereClause
 	CoreBindingInsert       FnCoreBindingInsert
 	BindingInsertSQL        FnBindingInsertSQL
+	// BindingBulkInsert renders a single INSERT of several rows, returning
+	// the generated keys when the table has them. Both render "" when the
+	// dialect cannot, and the rows are then inserted one at a time.
+	BindingBulkInsert    FnBindingBulkInsert
+	BindingBulkInsertSQL FnBindingBulkInsertSQL
+	// BindingBulkUpdate renders an update of many rows through a temporary
+	// table, using the dialect's BulkUpdateSQL. Both return nil when the
+	// dialect cannot, and the rows are then updated one at a time.
+	BindingBulkUpdate FnBindingBulkUpdate
+	BulkUpdateSQL     FnBulkUpdateSQL
 }
@@ -10,15 +10,63 @@ func PanicIfInvalid(g *SQLGenerator) {
 	if g.BindingRetrieve == nil {
 		panic("dyndao: vtable BindingRetrieve is nil")
 	}
+	if g.BindingRetrieveColumns == nil {
+		panic("dyndao: vtable BindingRetrieveColumns is nil")
+	}
+	if g.BindingRetrieveJoined == nil {
+		panic("dyndao: vtable BindingRetrieveJoined is nil")
+	}
+	if g.BindingQuery == nil {
+		panic("dyndao: vtable BindingQuery is nil")
+	}
+	if g.BindingExists == nil {
+		panic("dyndao: vtable BindingExists is nil")
+	}
+	if g.BindingAggregate == nil {
+		panic("dyndao: vtable BindingAggregate is nil")
+	}
 	if g.BindingDelete == nil {
 		panic("dyndao: vtable BindingDelete is nil")
 	}
+	if g.BindingDeleteReturning == nil {
+		panic("dyndao: vtable BindingDeleteReturning is nil")
+	}
+	if g.DeleteReturningSQL == nil {
+		panic("dyndao: vtable DeleteReturningSQL is nil")
+	}
 	if g.CreateTable == nil {
 		panic("dyndao: vtable CreateTable is nil")
 	}
+	if g.CreateIndex == nil {
+		panic("dyndao: vtable CreateIndex is nil")
+	}
+	if g.AddColumn == nil {
+		panic("dyndao: vtable AddColumn is nil")
+	}
+	if g.DropColumn == nil {
+		panic("dyndao: vtable DropColumn is nil")
+	}
 	if g.DropTable == nil {
 		panic("dyndao: vtable DropTable is nil")
 	}
+	if g.BindingTableExists == nil {
+		panic("dyndao: vtable BindingTableExists is nil")
+	}
+	if g.Truncate == nil {
+		panic("dyndao: vtable Truncate is nil")
+	}
+	if g.Savepoint == nil {
+		panic("dyndao: vtable Savepoint is nil")
+	}
+	if g.RollbackToSavepoint == nil {
+		panic("dyndao: vtable RollbackToSavepoint is nil")
+	}
+	if g.ReleaseSavepoint == nil {
+		panic("dyndao: vtable ReleaseSavepoint is nil")
+	}
+	if g.Placeholder == nil {
+		panic("dyndao: vtable Placeholder is nil")
+	}
 	if g.RenderBindingValue == nil {
 		panic("dyndao: vtable RenderBindingValue is nil")
 	}
@@ -40,6 +88,15 @@ func PanicIfInvalid(g *SQLGenerator) {
 	if g.IsLOBType == nil {
 		panic("dyndao: vtable IsLOBType is nil")
 	}
+	if g.IsBooleanType == nil {
+		panic("dyndao: vtable IsBooleanType is nil")
+	}
+	if g.IsBinaryType == nil {
+		panic("dyndao: vtable IsBinaryType is nil")
+	}
+	if g.IsDecimalType == nil {
+		panic("dyndao: vtable IsDecimalType is nil")
+	}
 	if g.DynamicObjectSetter == nil {
 		panic("dyndao: vtable DynamicObjectSetter is nil")
 	}
@@ -58,10 +115,37 @@ func PanicIfInvalid(g *SQLGenerator) {
 	if g.RenderCreateColumn == nil {
 		panic("dyndao: vtable RenderCreateColumn is nil")
 	}
+	if g.ColumnDBType == nil {
+		panic("dyndao: vtable ColumnDBType is nil")
+	}
 	if g.RenderInsertValue == nil {
 		panic("dyndao: vtable RenderInsertValue is nil")
 	}
+	if g.RenderLimit == nil {
+		panic("dyndao: vtable RenderLimit is nil")
+	}
+	if g.QuoteIdentifier == nil {
+		panic("dyndao: vtable QuoteIdentifier is nil")
+	}
+	if g.QuoteLiteral == nil {
+		panic("dyndao: vtable QuoteLiteral is nil")
+	}
+	if g.IsRetryable == nil {
+		panic("dyndao: vtable IsRetryable is nil")
+	}
 	if g.BindingInsertSQL == nil {
 		panic("dyndao: vtable BindingInsertSQL is nil")
 	}
+	if g.BindingBulkInsert == nil {
+		panic("dyndao: vtable BindingBulkInsert is nil")
+	}
+	if g.BindingBulkInsertSQL == nil {
+		panic("dyndao: vtable BindingBulkInsertSQL is nil")
+	}
+	if g.BindingBulkUpdate == nil {
+		panic("dyndao: vtable BindingBulkUpdate is nil")
+	}
+	if g.BulkUpdateSQL == nil {
+		panic("dyndao: vtable BulkUpdateSQL is nil")
+	}
 }
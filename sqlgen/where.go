@@ -0,0 +1,158 @@
+package sqlgen
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rbastic/dyndao/object"
+	"github.com/rbastic/dyndao/query"
+)
+
+// Predicate is a single test in a WHERE clause. Operator defaults to "=". IN
+// and NOT IN take a slice Value and bind each of its elements, or a
+// *query.Query rendered by the WhereBuilder's Subquery. A nil, NULL SQLValue
+// or invalid sql.Null* Value renders IS NULL, or IS NOT NULL for the <> and
+// != operators, while a BoundNULL Value binds NULL like any other value.
+// Name is the bind variable name for dialects with named binds and defaults
+// to Column. Or joins the predicate to the previous one with OR rather than
+// AND.
+type Predicate struct {
+	Column   string
+	Name     string
+	Operator string
+	Value    interface{}
+	Or       bool
+}
+
+// BoundNULL is a predicate value that is bound as NULL, rather than rendered
+// as IS NULL. The comparison then follows the SQL standard, under which
+// col = NULL is unknown and matches no rows.
+type BoundNULL struct{}
+
+// WhereBuilder renders predicates into a WHERE clause for a dialect, so that
+// generators only need to supply their placeholder and quoting functions.
+type WhereBuilder struct {
+	Placeholder     FnPlaceholder
+	QuoteIdentifier FnQuoteIdentifier // optional, column names are used as is when nil
+	Prefix          string            // prepended to column names, such as a table alias "t0."
+	NumberBinds     bool              // append the bind index to bind names, for clauses that may bind a column twice
+	Start           int               // index of the first bind argument within the statement
+
+	// Subquery renders a *query.Query value of an IN predicate, whose bind
+	// arguments start at index start. Subqueries are refused when it is
+	// nil.
+	Subquery func(q *query.Query, start int) (string, []interface{}, error)
+}
+
+// NewWhereBuilder returns a WhereBuilder that renders g's placeholders and
+// leaves column names unquoted.
+func NewWhereBuilder(g *SQLGenerator) *WhereBuilder {
+	return &WhereBuilder{Placeholder: g.Placeholder}
+}
+
+// Render renders the predicates, returning the clause (without the WHERE
+// keyword) and the values to bind, in order.
+func (w *WhereBuilder) Render(preds []Predicate) (string, []interface{}, error) {
+	var sb strings.Builder
+	var bindArgs []interface{}
+
+	bind := func(name string, v interface{}) string {
+		index := w.Start + len(bindArgs)
+		if w.NumberBinds {
+			name = fmt.Sprintf("%s%d", name, index)
+		}
+		bindArgs = append(bindArgs, v)
+		return w.Placeholder(index, name)
+	}
+
+	for i, p := range preds {
+		op := strings.ToUpper(strings.TrimSpace(p.Operator))
+		if op == "" {
+			op = "="
+		}
+		col := p.Column
+		if w.QuoteIdentifier != nil {
+			col = w.QuoteIdentifier(col)
+		}
+		col = w.Prefix + col
+		name := p.Name
+		if name == "" {
+			name = p.Column
+		}
+
+		var clause string
+		switch {
+		case (op == "IN" || op == "NOT IN") && isSubquery(p.Value):
+			if w.Subquery == nil {
+				return "", nil, errors.New("dyndao: subqueries are not supported here, for column " + p.Column)
+			}
+			subSQL, subArgs, err := w.Subquery(p.Value.(*query.Query), w.Start+len(bindArgs))
+			if err != nil {
+				return "", nil, err
+			}
+			bindArgs = append(bindArgs, subArgs...)
+			clause = fmt.Sprintf("%s %s (%s)", col, op, subSQL)
+		case op == "IN" || op == "NOT IN":
+			rv := reflect.ValueOf(p.Value)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return "", nil, errors.New("dyndao: " + op + " needs a slice of values for column " + p.Column)
+			}
+			if rv.Len() == 0 {
+				// Nothing is IN an empty list, and everything is NOT IN it
+				if op == "IN" {
+					clause = "1=0"
+				} else {
+					clause = "1=1"
+				}
+				break
+			}
+			binds := make([]string, rv.Len())
+			for j := range binds {
+				binds[j] = bind(fmt.Sprintf("%s%d", name, j), rv.Index(j).Interface())
+			}
+			clause = fmt.Sprintf("%s %s (%s)", col, op, strings.Join(binds, ","))
+		case !query.Operators[op]:
+			return "", nil, errors.New("dyndao: unsupported operator " + p.Operator)
+		case isNULLValue(p.Value):
+			// col = NULL never matches, so NULL values need IS NULL
+			switch op {
+			case "=":
+				clause = col + " IS NULL"
+			case "<>", "!=":
+				clause = col + " IS NOT NULL"
+			default:
+				return "", nil, errors.New("dyndao: operator " + op + " cannot compare column " + p.Column + " with NULL")
+			}
+		default:
+			v := p.Value
+			if _, ok := v.(BoundNULL); ok {
+				v = nil
+			}
+			clause = fmt.Sprintf("%s %s %s", col, op, bind(name, v))
+		}
+
+		if i > 0 {
+			if p.Or {
+				sb.WriteString(" OR ")
+			} else {
+				sb.WriteString(" AND ")
+			}
+		}
+		sb.WriteString(clause)
+	}
+	return sb.String(), bindArgs, nil
+}
+
+// isSubquery reports whether a predicate value is a subquery.
+func isSubquery(v interface{}) bool {
+	_, ok := v.(*query.Query)
+	return ok
+}
+
+// isNULLValue reports whether a predicate value asks for NULL: a nil value,
+// an explicit NULL SQLValue or an invalid sql.Null* value, see object.IsNULL.
+func isNULLValue(v interface{}) bool {
+	return object.IsNULL(v)
+}
@@ -0,0 +1,67 @@
+package sqlgen
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rbastic/dyndao/object"
+)
+
+func TestWhereBuilder(t *testing.T) {
+	w := &WhereBuilder{
+		Placeholder: func(index int, name string) string { return fmt.Sprintf(":%s", name) },
+		Prefix:      "t0.",
+		NumberBinds: true,
+		Start:       2,
+	}
+	preds := []Predicate{
+		{Column: "Age", Operator: ">", Value: 18},
+		{Column: "Color", Operator: "in", Value: []string{"red", "blue"}},
+		{Column: "Name", Value: nil, Or: true},
+		{Column: "Created", Operator: "<>", Value: object.NewSQLValue("NULL")},
+		{Column: "Shape", Operator: "NOT IN", Value: []int{}},
+	}
+	clause, bindArgs, err := w.Render(preds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "t0.Age > :Age2 AND t0.Color IN (:Color03,:Color14) OR t0.Name IS NULL AND t0.Created IS NOT NULL AND 1=1"
+	if clause != expected {
+		t.Fatalf("expected [%s], got [%s]", expected, clause)
+	}
+	if !reflect.DeepEqual(bindArgs, []interface{}{18, "red", "blue"}) {
+		t.Fatal("unexpected bind args", bindArgs)
+	}
+
+	clause, bindArgs, err = w.Render([]Predicate{{Column: "Name", Value: BoundNULL{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clause != "t0.Name = :Name2" || !reflect.DeepEqual(bindArgs, []interface{}{nil}) {
+		t.Fatalf("expected a BoundNULL to bind NULL, got [%s] %v", clause, bindArgs)
+	}
+
+	clause, bindArgs, err = w.Render([]Predicate{
+		{Column: "Name", Value: sql.NullString{}},
+		{Column: "Age", Operator: "<>", Value: &sql.NullInt64{}},
+		{Column: "Color", Value: sql.NullString{String: "red", Valid: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clause != "t0.Name IS NULL AND t0.Age IS NOT NULL AND t0.Color = :Color2" || len(bindArgs) != 1 {
+		t.Fatalf("expected invalid sql.Null* values to render IS NULL, got [%s] %v", clause, bindArgs)
+	}
+
+	for _, p := range []Predicate{
+		{Column: "Age", Operator: "BETWEEN", Value: 1},
+		{Column: "Age", Operator: "IN", Value: 1},
+		{Column: "Age", Operator: ">", Value: nil},
+	} {
+		if _, _, err := w.Render([]Predicate{p}); err == nil {
+			t.Errorf("expected an error for %+v", p)
+		}
+	}
+}